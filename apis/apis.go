@@ -20,6 +20,7 @@ import (
 	repositories "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
 	repositorypermissions "github.com/krateoplatformops/azuredevops-provider/apis/repositorypermissions/v1alpha1"
 	runs "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+	runschedules "github.com/krateoplatformops/azuredevops-provider/apis/runschedules/v1alpha1"
 	securefiles "github.com/krateoplatformops/azuredevops-provider/apis/securefiles/v1alpha1"
 	teams "github.com/krateoplatformops/azuredevops-provider/apis/teams/v1alpha1"
 	users "github.com/krateoplatformops/azuredevops-provider/apis/users/v1alpha1"
@@ -33,6 +34,7 @@ func init() {
 		repositories.SchemeBuilder.AddToScheme,
 		pipelines.SchemeBuilder.AddToScheme,
 		runs.SchemeBuilder.AddToScheme,
+		runschedules.SchemeBuilder.AddToScheme,
 		pipelinepermissionsv1alpha1.SchemeBuilder.AddToScheme,
 		pipelinepermissionsv1alpha2.SchemeBuilder.AddToScheme,
 		teams.SchemeBuilder.AddToScheme,
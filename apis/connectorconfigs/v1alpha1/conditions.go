@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons a ConnectorConfig is or is not ready.
+const (
+	ReasonInvalidConfig rtv1.ConditionReason = "InvalidConfig"
+	ReasonUnauthorized  rtv1.ConditionReason = "Unauthorized"
+	ReasonForbidden     rtv1.ConditionReason = "Forbidden"
+	ReasonUnreachable   rtv1.ConditionReason = "Unreachable"
+)
+
+// GetCondition of this ConnectorConfig.
+func (cc *ConnectorConfig) GetCondition(ct rtv1.ConditionType) rtv1.Condition {
+	return cc.Status.GetCondition(ct)
+}
+
+// SetConditions of this ConnectorConfig.
+func (cc *ConnectorConfig) SetConditions(c ...rtv1.Condition) {
+	cc.Status.SetConditions(c...)
+}
+
+// NotReady returns a condition that indicates the connector
+// cannot be used for the specified reason.
+func NotReady(reason rtv1.ConditionReason, msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
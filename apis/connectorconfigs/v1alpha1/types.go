@@ -76,6 +76,106 @@ type ApiUrl struct {
 	Vssps string `json:"vssps,omitempty"`
 }
 
+// ServicePrincipal identifies a Microsoft Entra ID application used
+// to obtain OAuth2 access tokens (client credentials flow).
+type ServicePrincipal struct {
+	// TenantID: the Microsoft Entra tenant (directory) ID.
+	// +required
+	TenantID string `json:"tenantId"`
+
+	// ClientID: the application (client) ID of the service principal.
+	// +required
+	ClientID string `json:"clientId"`
+
+	// ClientSecretRef: reference to the secret key holding the client secret.
+	// +optional
+	ClientSecretRef *rtv1.SecretKeySelector `json:"clientSecretRef,omitempty"`
+
+	// ClientCertificateRef: reference to the secret key holding the PEM encoded
+	// certificate and private key used to sign the client assertion.
+	// +optional
+	ClientCertificateRef *rtv1.SecretKeySelector `json:"clientCertificateRef,omitempty"`
+
+	// TokenURL: the OAuth2 token endpoint
+	// (default: https://login.microsoftonline.com/{tenantId}/oauth2/v2.0/token).
+	// +optional
+	TokenURL string `json:"tokenUrl,omitempty"`
+
+	// Scope: the scope requested for the access token
+	// (default: 499b84ac-1321-427f-aa17-267ca6975798/.default).
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// WorkloadIdentity configures the exchange of the provider pod projected
+// service account token for a Microsoft Entra ID access token
+// (workload identity federation), without any long-lived secret.
+type WorkloadIdentity struct {
+	// TenantID: the Microsoft Entra tenant (directory) ID
+	// (default: the AZURE_TENANT_ID environment variable).
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+
+	// ClientID: the application (client) ID of the federated identity
+	// (default: the AZURE_CLIENT_ID environment variable).
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+
+	// TokenFilePath: the path of the projected service account token
+	// (default: the AZURE_FEDERATED_TOKEN_FILE environment variable).
+	// +optional
+	TokenFilePath string `json:"tokenFilePath,omitempty"`
+
+	// TokenURL: the OAuth2 token endpoint
+	// (default: {AZURE_AUTHORITY_HOST}/{tenantId}/oauth2/v2.0/token).
+	// +optional
+	TokenURL string `json:"tokenUrl,omitempty"`
+
+	// Scope: the scope requested for the access token
+	// (default: 499b84ac-1321-427f-aa17-267ca6975798/.default).
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// TLSConfig configures the TLS connections to the ReST API server.
+type TLSConfig struct {
+	// CABundleRef: reference to the secret key holding the PEM encoded CA
+	// certificates trusted in addition to the system ones.
+	// +optional
+	CABundleRef *rtv1.SecretKeySelector `json:"caBundleRef,omitempty"`
+
+	// InsecureSkipVerify: disables the verification of the server certificate.
+	// Do not use in production.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PathMode is the URL layout of the ReST API server.
+// +kubebuilder:validation:Enum=Organization;Collection
+type PathMode string
+
+const (
+	// PathModeOrganization: Azure DevOps Services layout, {baseUrl}/{organization}/...
+	// with a dedicated base URL for each service (default, feeds, vssps).
+	PathModeOrganization PathMode = "Organization"
+	// PathModeCollection: Azure DevOps Server layout, {baseUrl}/{virtualDirectory}/{collection}/...
+	// where every service is served by the default base URL and the
+	// organization of the resources is the collection name.
+	PathModeCollection PathMode = "Collection"
+)
+
+// HealthCheck configures the periodic validation of the connector.
+type HealthCheck struct {
+	// Organization: the organization (or collection) used to probe the endpoints.
+	// If not specified only the credentials are validated.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Interval: how often the connector is validated (default: 5m).
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
 type ConnectorConfigSpec struct {
 	// DEPRECATED: This field is deprecated and will be removed in a future version. Use the ApiUrls field instead.
 	// ApiUrl: the baseUrl for the REST API provider.
@@ -88,24 +188,120 @@ type ConnectorConfigSpec struct {
 	// +optional
 	ApiUrls *ApiUrl `json:"apiUrls,omitempty"`
 
-	// Credentials required to authenticate ReST API server.
-	// +required
-	Credentials *rtv1.CredentialSelectors `json:"credentials"`
+	// Credentials: the personal access token required to authenticate ReST API server.
+	// One of credentials, servicePrincipal or workloadIdentity must be specified.
+	// +optional
+	Credentials *rtv1.CredentialSelectors `json:"credentials,omitempty"`
+
+	// ServicePrincipal: the Microsoft Entra ID service principal used
+	// to authenticate ReST API server instead of a personal access token.
+	// +optional
+	ServicePrincipal *ServicePrincipal `json:"servicePrincipal,omitempty"`
+
+	// WorkloadIdentity: use the provider pod service account token
+	// federated with a Microsoft Entra ID application to authenticate ReST API server.
+	// +optional
+	WorkloadIdentity *WorkloadIdentity `json:"workloadIdentity,omitempty"`
 
 	// APIVersionConfig: the API version configuration.
 	// +optional
 	APIVersionConfig *APIVersionConfig `json:"apiVersionConfig,omitempty"`
+
+	// PathMode: the URL layout of the ReST API server (default: Organization).
+	// Use Collection for Azure DevOps Server (on-premises).
+	// +optional
+	PathMode PathMode `json:"pathMode,omitempty"`
+
+	// VirtualDirectory: the Azure DevOps Server virtual directory prepended to
+	// the collection in Collection path mode (default: tfs; empty for none).
+	// +optional
+	VirtualDirectory *string `json:"virtualDirectory,omitempty"`
+
+	// TLS: the TLS configuration (i.e. custom CA for self-signed certificates).
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL: the proxy used to connect to the ReST API server
+	// (default: from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables).
+	// +optional
+	ProxyURL string `json:"proxyUrl,omitempty"`
+
+	// HealthCheck: the connectivity and permission checks configuration.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+}
+
+// EndpointStatus is the result of the check of a ReST API base URL.
+type EndpointStatus struct {
+	// Name: the endpoint name (default, feeds, vssps).
+	Name string `json:"name"`
+
+	// URL: the probed URL.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Reachable: true if the endpoint answered successfully.
+	Reachable bool `json:"reachable"`
+
+	// StatusCode: the HTTP status code returned by the endpoint.
+	// +optional
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Message: details about the failure, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ConnectorConfigStatus is the observed state of the connector.
+type ConnectorConfigStatus struct {
+	rtv1.ConditionedStatus `json:",inline"`
+
+	// ObservedGeneration: the generation of the spec last validated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AuthMethod: the authentication method in use
+	// (PersonalAccessToken, ServicePrincipal or WorkloadIdentity).
+	// +optional
+	AuthMethod string `json:"authMethod,omitempty"`
+
+	// AuthenticatedUser: the display name of the authenticated identity.
+	// +optional
+	AuthenticatedUser string `json:"authenticatedUser,omitempty"`
+
+	// AuthenticatedUserId: the id of the authenticated identity.
+	// +optional
+	AuthenticatedUserId string `json:"authenticatedUserId,omitempty"`
+
+	// TokenExpiry: the expiration time of the current access token
+	// (not available for personal access tokens).
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+
+	// LastCheckTime: the time of the last validation.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Endpoints: the result of the check of each ReST API base URL.
+	// +optional
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
 }
 
 //+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster,categories={krateo,azuredevops}
+//+kubebuilder:printcolumn:name="AUTH",type="string",JSONPath=".status.authMethod"
+//+kubebuilder:printcolumn:name="USER",type="string",JSONPath=".status.authenticatedUser",priority=10
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="REASON",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",priority=10
 
 // ConnectorConfigSpec is the Schema for the AzureDevops Client
 type ConnectorConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ConnectorConfigSpec `json:"spec,omitempty"`
+	Spec   ConnectorConfigSpec   `json:"spec,omitempty"`
+	Status ConnectorConfigStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -14,6 +14,16 @@ type EndpointAuthorizationParams struct {
 	ServiceAccountCertificate *string `json:"serviceAccountCertificate,omitempty"`
 	IsCreatedFromSecretYaml   *string `json:"isCreatedFromSecretYaml,omitempty"`
 	Apitoken                  *string `json:"apitoken,omitempty"`
+
+	// ServiceprincipalKeyRef: reference to the secret key holding the service principal key; overrides ServiceprincipalKey.
+	// +optional
+	ServiceprincipalKeyRef *rtv1.SecretKeySelector `json:"serviceprincipalKeyRef,omitempty"`
+	// ServiceAccountCertificateRef: reference to the secret key holding the service account certificate; overrides ServiceAccountCertificate.
+	// +optional
+	ServiceAccountCertificateRef *rtv1.SecretKeySelector `json:"serviceAccountCertificateRef,omitempty"`
+	// ApitokenRef: reference to the secret key holding the API token; overrides Apitoken.
+	// +optional
+	ApitokenRef *rtv1.SecretKeySelector `json:"apitokenRef,omitempty"`
 }
 
 // Represents the authorization used for service endpoint.
@@ -90,6 +100,17 @@ type EndpointSpec struct {
 	// All other project references where the service endpoint is shared.
 	// +optional
 	ServiceEndpointProjectReferences []ServiceEndpointProjectReference `json:"serviceEndpointProjectReferences,omitempty"`
+	// WorkloadIdentityFederation: options of the endpoints using the 'WorkloadIdentityFederation' authorization scheme.
+	// +optional
+	WorkloadIdentityFederation *WorkloadIdentityFederation `json:"workloadIdentityFederation,omitempty"`
+}
+
+type WorkloadIdentityFederation struct {
+	// ConfigMapRef: the ConfigMap where the issuer and the subject identifier
+	// generated by Azure DevOps are written, with the 'issuer' and
+	// 'subjectIdentifier' keys, to create the matching federated credential.
+	// +optional
+	ConfigMapRef *rtv1.Reference `json:"configMapRef,omitempty"`
 }
 
 // EndpointStatus defines the observed state of a Endpoint
@@ -103,6 +124,20 @@ type EndpointStatus struct {
 	// Url: the url of the endpoint.
 	// +optional
 	Url *string `json:"url,omitempty"`
+
+	// WorkloadIdentityFederationIssuer: the issuer of the federated credential of the endpoint.
+	// +optional
+	WorkloadIdentityFederationIssuer *string `json:"workloadIdentityFederationIssuer,omitempty"`
+
+	// WorkloadIdentityFederationSubject: the subject identifier of the federated credential of the endpoint.
+	// +optional
+	WorkloadIdentityFederationSubject *string `json:"workloadIdentityFederationSubject,omitempty"`
+
+	// ParameterHashes: the hashes of the secret authorization parameters
+	// last sent, by parameter name. Azure DevOps never returns them: the
+	// hashes are used to detect their changes.
+	// +optional
+	ParameterHashes map[string]string `json:"parameterHashes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
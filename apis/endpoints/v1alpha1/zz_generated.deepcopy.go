@@ -170,6 +170,21 @@ func (in *EndpointAuthorizationParams) DeepCopyInto(out *EndpointAuthorizationPa
 		*out = new(string)
 		**out = **in
 	}
+	if in.ServiceprincipalKeyRef != nil {
+		in, out := &in.ServiceprincipalKeyRef, &out.ServiceprincipalKeyRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.ServiceAccountCertificateRef != nil {
+		in, out := &in.ServiceAccountCertificateRef, &out.ServiceAccountCertificateRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.ApitokenRef != nil {
+		in, out := &in.ApitokenRef, &out.ApitokenRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointAuthorizationParams.
@@ -285,6 +300,11 @@ func (in *EndpointSpec) DeepCopyInto(out *EndpointSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WorkloadIdentityFederation != nil {
+		in, out := &in.WorkloadIdentityFederation, &out.WorkloadIdentityFederation
+		*out = new(WorkloadIdentityFederation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointSpec.
@@ -311,6 +331,23 @@ func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.WorkloadIdentityFederationIssuer != nil {
+		in, out := &in.WorkloadIdentityFederationIssuer, &out.WorkloadIdentityFederationIssuer
+		*out = new(string)
+		**out = **in
+	}
+	if in.WorkloadIdentityFederationSubject != nil {
+		in, out := &in.WorkloadIdentityFederationSubject, &out.WorkloadIdentityFederationSubject
+		*out = new(string)
+		**out = **in
+	}
+	if in.ParameterHashes != nil {
+		in, out := &in.ParameterHashes, &out.ParameterHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
@@ -372,3 +409,23 @@ func (in *ServiceEndpointProjectReference) DeepCopy() *ServiceEndpointProjectRef
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentityFederation) DeepCopyInto(out *WorkloadIdentityFederation) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentityFederation.
+func (in *WorkloadIdentityFederation) DeepCopy() *WorkloadIdentityFederation {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentityFederation)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	variablegroups "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -9,11 +10,10 @@ import (
 type PipelineSpec struct {
 	rtv1.ManagedSpec `json:",inline"`
 
-	// Name: the name of the pipeline.
-	// +immutable
+	// Name: the name of the pipeline; changing it renames the pipeline.
 	Name string `json:"name"`
 
-	// Folder: the pipeline folder.
+	// Folder: the pipeline folder (i.e. '\builds'); changing it moves the pipeline.
 	Folder string `json:"folder,omitempty"`
 
 	// ConfigurationType: Type of configuration.
@@ -35,11 +35,122 @@ type PipelineSpec struct {
 	// +optional
 	ProjectRef *rtv1.Reference `json:"projectRef,omitempty"`
 
+	// BuildDefinition: the settings of the build definition of the pipeline,
+	// applied once the pipeline is created; the settings not specified are
+	// left as they are.
+	// +optional
+	BuildDefinition *BuildDefinitionSpec `json:"buildDefinition,omitempty"`
+
 	// ConnectorConfigRef: configuration spec for the REST API client.
 	// +immutable
 	ConnectorConfigRef *rtv1.Reference `json:"connectorConfigRef,omitempty"`
 }
 
+// BuildDefinitionSpec defines the settings of the build definition of a pipeline.
+type BuildDefinitionSpec struct {
+	// CITrigger: overrides the continuous integration trigger of the YAML file.
+	// +optional
+	CITrigger *CITrigger `json:"ciTrigger,omitempty"`
+
+	// PRTrigger: overrides the pull request trigger of the YAML file
+	// (GitHub repositories only).
+	// +optional
+	PRTrigger *PRTrigger `json:"prTrigger,omitempty"`
+
+	// Variables: the pipeline variables by name; when specified they
+	// replace all the variables of the pipeline.
+	// +optional
+	Variables map[string]PipelineVariable `json:"variables,omitempty"`
+
+	// QueueRef: reference to the Queue of the default agent pool.
+	// +optional
+	QueueRef *rtv1.Reference `json:"queueRef,omitempty"`
+
+	// BadgeEnabled: enables the status badge of the pipeline.
+	// +optional
+	BadgeEnabled *bool `json:"badgeEnabled,omitempty"`
+
+	// Retention: the retention policy of the pipeline runs.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+}
+
+type CITrigger struct {
+	// Disabled: disables the continuous integration.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// BranchFilters: the branches to include (i.e. '+refs/heads/main')
+	// or exclude (i.e. '-refs/heads/wip/*').
+	// +optional
+	BranchFilters []string `json:"branchFilters,omitempty"`
+
+	// PathFilters: the paths to include (i.e. '+/src') or exclude (i.e. '-/docs').
+	// +optional
+	PathFilters []string `json:"pathFilters,omitempty"`
+
+	// BatchChanges: batches the changes pushed while a run is in progress.
+	// +optional
+	BatchChanges *bool `json:"batchChanges,omitempty"`
+}
+
+type PRTrigger struct {
+	// Disabled: disables the pull request validation.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// BranchFilters: the target branches to include (i.e. '+refs/heads/main')
+	// or exclude (i.e. '-refs/heads/releases/*').
+	// +optional
+	BranchFilters []string `json:"branchFilters,omitempty"`
+
+	// PathFilters: the paths to include (i.e. '+/src') or exclude (i.e. '-/docs').
+	// +optional
+	PathFilters []string `json:"pathFilters,omitempty"`
+
+	// AutoCancel: cancels the running validation when the pull request is updated.
+	// +optional
+	AutoCancel *bool `json:"autoCancel,omitempty"`
+}
+
+type PipelineVariable struct {
+	// Value: the value of the variable.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom: the source of the variable value, resolved at reconcile
+	// time; if specified Value is ignored.
+	// +optional
+	ValueFrom *variablegroups.VariableValueSource `json:"valueFrom,omitempty"`
+
+	// IsSecret: the flag to indicate whether the variable value is secret.
+	// +optional
+	IsSecret bool `json:"isSecret,omitempty"`
+
+	// AllowOverride: the flag to indicate whether the value can be set at queue time.
+	// +optional
+	AllowOverride bool `json:"allowOverride,omitempty"`
+}
+
+type RetentionPolicy struct {
+	// DaysToKeep: the days the runs are kept.
+	// +kubebuilder:validation:Minimum=1
+	DaysToKeep int `json:"daysToKeep"`
+
+	// MinimumToKeep: the minimum number of runs kept for each branch.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinimumToKeep int `json:"minimumToKeep,omitempty"`
+
+	// Branches: the branches the policy applies to (default: '+refs/heads/*').
+	// +optional
+	Branches []string `json:"branches,omitempty"`
+
+	// DeleteBuildRecord: deletes the run records besides their artifacts (default: true).
+	// +optional
+	DeleteBuildRecord *bool `json:"deleteBuildRecord,omitempty"`
+}
+
 type PipelineStatus struct {
 	rtv1.ManagedStatus `json:",inline"`
 
@@ -49,6 +160,12 @@ type PipelineStatus struct {
 	Revision *int `json:"revision,omitempty"`
 	// URL of the pipeline
 	Url *string `json:"url,omitempty"`
+
+	// ValueHashes: the hashes of the values of the secret and of the
+	// referenced variables, by variable name. Azure DevOps never returns
+	// the secret values: the hashes are used to detect their changes.
+	// +optional
+	ValueHashes map[string]string `json:"valueHashes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -21,10 +21,118 @@ limitations under the License.
 package v1alpha1
 
 import (
+	variablegroupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
 	"github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDefinitionSpec) DeepCopyInto(out *BuildDefinitionSpec) {
+	*out = *in
+	if in.CITrigger != nil {
+		in, out := &in.CITrigger, &out.CITrigger
+		*out = new(CITrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PRTrigger != nil {
+		in, out := &in.PRTrigger, &out.PRTrigger
+		*out = new(PRTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]PipelineVariable, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.QueueRef != nil {
+		in, out := &in.QueueRef, &out.QueueRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.BadgeEnabled != nil {
+		in, out := &in.BadgeEnabled, &out.BadgeEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDefinitionSpec.
+func (in *BuildDefinitionSpec) DeepCopy() *BuildDefinitionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDefinitionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CITrigger) DeepCopyInto(out *CITrigger) {
+	*out = *in
+	if in.BranchFilters != nil {
+		in, out := &in.BranchFilters, &out.BranchFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathFilters != nil {
+		in, out := &in.PathFilters, &out.PathFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BatchChanges != nil {
+		in, out := &in.BatchChanges, &out.BatchChanges
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CITrigger.
+func (in *CITrigger) DeepCopy() *CITrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CITrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PRTrigger) DeepCopyInto(out *PRTrigger) {
+	*out = *in
+	if in.BranchFilters != nil {
+		in, out := &in.BranchFilters, &out.BranchFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathFilters != nil {
+		in, out := &in.PathFilters, &out.PathFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoCancel != nil {
+		in, out := &in.AutoCancel, &out.AutoCancel
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PRTrigger.
+func (in *PRTrigger) DeepCopy() *PRTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(PRTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pipeline) DeepCopyInto(out *Pipeline) {
 	*out = *in
@@ -113,6 +221,11 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 		*out = new(v1.Reference)
 		**out = **in
 	}
+	if in.BuildDefinition != nil {
+		in, out := &in.BuildDefinition, &out.BuildDefinition
+		*out = new(BuildDefinitionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ConnectorConfigRef != nil {
 		in, out := &in.ConnectorConfigRef, &out.ConnectorConfigRef
 		*out = new(v1.Reference)
@@ -149,6 +262,13 @@ func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ValueHashes != nil {
+		in, out := &in.ValueHashes, &out.ValueHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
@@ -160,3 +280,48 @@ func (in *PipelineStatus) DeepCopy() *PipelineStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineVariable) DeepCopyInto(out *PipelineVariable) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(variablegroupsv1alpha1.VariableValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineVariable.
+func (in *PipelineVariable) DeepCopy() *PipelineVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeleteBuildRecord != nil {
+		in, out := &in.DeleteBuildRecord, &out.DeleteBuildRecord
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
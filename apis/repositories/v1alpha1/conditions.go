@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the Ready condition of an imported or forked GitRepository.
+const (
+	ReasonImporting      rtv1.ConditionReason = "Importing"
+	ReasonImportFailed   rtv1.ConditionReason = "ImportFailed"
+	ReasonParentMismatch rtv1.ConditionReason = "ParentMismatch"
+)
+
+// Importing returns a condition that indicates the repository is
+// being imported: it is available once the import completes.
+func Importing() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonImporting,
+	}
+}
+
+// ImportFailed returns a condition that indicates the import of the
+// repository has failed.
+func ImportFailed(msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonImportFailed,
+		Message:            msg,
+	}
+}
+
+// ParentMismatch returns a condition that indicates the repository is
+// not a fork of the parent GitRepository of the spec.
+func ParentMismatch(msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonParentMismatch,
+		Message:            msg,
+	}
+}
@@ -5,6 +5,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeletionMode describes how the repository is deleted from Azure DevOps
+// and whether a soft-deleted repository is restored on creation.
+// +kubebuilder:validation:Enum=SoftDelete;HardDelete;Restore
+type DeletionMode string
+
+const (
+	// SoftDeletion moves the repository to the recycle bin, where it
+	// holds its name until destroyed.
+	SoftDeletion DeletionMode = "SoftDelete"
+	// HardDeletion destroys the repository, purging it from the recycle bin.
+	HardDeletion DeletionMode = "HardDelete"
+	// RestoreDeletion moves the repository to the recycle bin, and restores
+	// the soft-deleted repository with the same name in place of creating one.
+	RestoreDeletion DeletionMode = "Restore"
+)
+
 type GitRepositorySpec struct {
 	rtv1.ManagedSpec `json:",inline"`
 
@@ -22,15 +38,178 @@ type GitRepositorySpec struct {
 	// ProjectRef - A reference to a TeamProject.
 	ProjectRef *rtv1.Reference `json:"projectRef,omitempty"`
 
-	// Name: name of the Git repository.
+	// Name: name of the Git repository; changing it renames the repository.
 	Name string `json:"name,omitempty"`
 
 	// Init: initialize the Git repository.
 	Initialize *bool `json:"initialize,omitempty"`
 
-	// DefaultBranch: repository default branch.
+	// DefaultBranch: repository default branch; changing it sets the
+	// default branch of the repositories having branches.
 	// +optional
 	DefaultBranch *string `json:"defaultBranch,omitempty"`
+
+	// Template: the initial content of the repository, pushed on the
+	// default branch as a single commit in place of the README of Initialize.
+	// +immutable
+	// +optional
+	Template *RepositoryTemplate `json:"template,omitempty"`
+
+	// Import: the external Git repository imported into the new repository;
+	// it cannot be specified with Initialize and Template. A failed import
+	// is requested again when the spec changes (i.e. fixing the URL).
+	// +optional
+	Import *RepositoryImport `json:"import,omitempty"`
+
+	// Fork: the parent repository of the new repository, which is created
+	// as its fork; it cannot be specified with Initialize, Template and Import.
+	// +immutable
+	// +optional
+	Fork *RepositoryFork `json:"fork,omitempty"`
+
+	// DeletionMode: how the repository is deleted (SoftDelete, HardDelete,
+	// Restore); with Restore a soft-deleted repository with the same name
+	// is restored instead of creating a new one. Default is SoftDelete.
+	// +kubebuilder:default=SoftDelete
+	// +optional
+	DeletionMode DeletionMode `json:"deletionMode,omitempty"`
+
+	// Disabled: disables the repository: the disabled repositories
+	// cannot be read or pushed. If not specified it is left as it is.
+	// +optional
+	Disabled *bool `json:"disabled,omitempty"`
+}
+
+// RepositoryTemplate defines the files of a new repository: the files
+// of the template repository, if any, overwritten by the files of the
+// ConfigMap and of the Secret.
+type RepositoryTemplate struct {
+	// RepositoryRef: reference to the GitRepository whose files are copied.
+	// +optional
+	RepositoryRef *rtv1.Reference `json:"repositoryRef,omitempty"`
+
+	// Branch: the branch of the template repository (default: its default branch).
+	// +optional
+	Branch *string `json:"branch,omitempty"`
+
+	// ConfigMapRef: reference to the ConfigMap whose keys are files.
+	// +optional
+	ConfigMapRef *rtv1.Reference `json:"configMapRef,omitempty"`
+
+	// SecretRef: reference to the Secret whose keys are files.
+	// +optional
+	SecretRef *rtv1.Reference `json:"secretRef,omitempty"`
+
+	// Items: the paths of the keys of the ConfigMap and of the Secret; if
+	// not specified each key is a file in the repository root.
+	// +optional
+	Items []TemplateItem `json:"items,omitempty"`
+
+	// Render: renders the text files as Go templates of the GitRepository
+	// (i.e. '{{ .Spec.Name }}').
+	// +optional
+	Render bool `json:"render,omitempty"`
+}
+
+type TemplateItem struct {
+	// Key: the key of the ConfigMap or of the Secret.
+	Key string `json:"key"`
+
+	// Path: the path of the file in the repository (i.e. 'docs/index.md').
+	Path string `json:"path"`
+}
+
+// RepositoryImport defines the external Git repository to import.
+type RepositoryImport struct {
+	// Url: the clone URL of the repository (i.e. 'https://github.com/org/repo.git').
+	Url string `json:"url"`
+
+	// EndpointRef: reference to the Endpoint with the credentials of the
+	// private repositories (an 'Other Git' service connection).
+	// +optional
+	EndpointRef *rtv1.Reference `json:"endpointRef,omitempty"`
+
+	// SecretRef: reference to the Secret with the 'password' (or personal
+	// access token) and the optional 'username' keys of the private
+	// repositories; a service connection is created for the import and
+	// deleted when done.
+	// +optional
+	SecretRef *rtv1.Reference `json:"secretRef,omitempty"`
+}
+
+// RepositoryFork defines the parent repository of a fork.
+type RepositoryFork struct {
+	// ParentRef: reference to the parent GitRepository; it can belong to
+	// another TeamProject of the organization.
+	ParentRef *rtv1.Reference `json:"parentRef"`
+
+	// SourceBranch: the only branch of the parent repository copied to
+	// the fork (i.e. 'main'); all the branches are copied if not specified.
+	// +optional
+	SourceBranch *string `json:"sourceBranch,omitempty"`
+}
+
+// ImportStatus defines the observed state of the import of a repository.
+type ImportStatus struct {
+	// RequestId: the ID of the import request.
+	RequestId int `json:"requestId,omitempty"`
+
+	// Status: the status of the import (queued, inProgress, completed, failed or abandoned).
+	Status string `json:"status,omitempty"`
+
+	// Progress: the current step of the import (i.e. '2/5 Analyzing repository objects').
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// ErrorMessage: the error of the failed import.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// ObservedGeneration: the generation of the GitRepository when the
+	// import was observed; the failed imports are requested again when
+	// the spec changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ParentRepositoryStatus defines the observed parent repository of a fork.
+type ParentRepositoryStatus struct {
+	// Id: the ID of the parent repository.
+	Id string `json:"id,omitempty"`
+
+	// Name: the name of the parent repository.
+	Name string `json:"name,omitempty"`
+
+	// Project: the name of the project of the parent repository.
+	Project string `json:"project,omitempty"`
+
+	// RemoteUrl: the clone URL of the parent repository.
+	// +optional
+	RemoteUrl string `json:"remoteUrl,omitempty"`
+}
+
+// ForkSyncStatus defines the observed state of the last sync of a fork
+// with its parent repository.
+type ForkSyncStatus struct {
+	// OperationId: the ID of the fork sync request.
+	OperationId int `json:"operationId,omitempty"`
+
+	// Status: the status of the sync (queued, inProgress, completed, failed or abandoned).
+	Status string `json:"status,omitempty"`
+
+	// Progress: the current step of the sync (i.e. '2/3 Copying refs').
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// ErrorMessage: the error of the failed sync.
+	// +optional
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// ObservedGeneration: the generation of the GitRepository when the
+	// sync was observed; the completed syncs are observed again when the
+	// spec changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // GitRepositoryStatus defines the observed state of Repository
@@ -39,9 +218,27 @@ type GitRepositoryStatus struct {
 
 	Id            string `json:"id,omitempty"`
 	DefaultBranch string `json:"defaultBranch,omitempty"`
+	Disabled      bool   `json:"disabled,omitempty"`
 	SshUrl        string `json:"sshUrl,omitempty"`
 	Url           string `json:"url,omitempty"`
 	RemoteUrl     string `json:"remoteUrl,omitempty"`
+
+	// Seeded: true once the initial commit required by initialize or
+	// template is pushed.
+	// +optional
+	Seeded bool `json:"seeded,omitempty"`
+
+	// Import: the state of the import of the repository, if any.
+	// +optional
+	Import *ImportStatus `json:"import,omitempty"`
+
+	// Parent: the parent repository, if the repository is a fork.
+	// +optional
+	Parent *ParentRepositoryStatus `json:"parent,omitempty"`
+
+	// ForkSync: the state of the last sync of the fork with its parent.
+	// +optional
+	ForkSync *ForkSyncStatus `json:"forkSync,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -49,6 +246,8 @@ type GitRepositoryStatus struct {
 //+kubebuilder:resource:scope=Cluster,categories={krateo,azuredevops}
 //+kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id",priority=10
 //+kubebuilder:printcolumn:name="REMOTE_URL",type="string",JSONPath=".status.remoteUrl"
+//+kubebuilder:printcolumn:name="IMPORT",type="string",JSONPath=".status.import.status",priority=10
+//+kubebuilder:printcolumn:name="PARENT",type="string",JSONPath=".status.parent.name",priority=10
 //+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 //+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status",priority=10
 
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeCompleted resources report whether the pipeline run has
+// completed; the reason of the condition is the result of the run.
+const TypeCompleted rtv1.ConditionType = "Completed"
+
+// Reasons of the Completed condition of a Run.
+const (
+	ReasonInProgress rtv1.ConditionReason = "InProgress"
+	ReasonSucceeded  rtv1.ConditionReason = "Succeeded"
+	ReasonFailed     rtv1.ConditionReason = "Failed"
+	ReasonCanceled   rtv1.ConditionReason = "Canceled"
+)
+
+// InProgress returns a condition that indicates the run is in progress.
+func InProgress() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCompleted,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInProgress,
+	}
+}
+
+// Succeeded returns a condition that indicates the run has succeeded.
+func Succeeded() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCompleted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSucceeded,
+	}
+}
+
+// Failed returns a condition that indicates the run has failed.
+func Failed(msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCompleted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFailed,
+		Message:            msg,
+	}
+}
+
+// Canceled returns a condition that indicates the run has been canceled.
+func Canceled(msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               TypeCompleted,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCanceled,
+		Message:            msg,
+	}
+}
@@ -81,6 +81,64 @@ type RunSpec struct {
 	// ConnectorConfigRef: configuration spec for the REST API client.
 	// +immutable
 	ConnectorConfigRef *rtv1.Reference `json:"connectorConfigRef,omitempty"`
+
+	// Timeout: the maximum duration of the run (i.e. '1h30m'); the run is canceled if it is still in progress after it.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// FailureLogs: writes the tail of the logs of the failed tasks to a ConfigMap.
+	// +optional
+	FailureLogs *FailureLogs `json:"failureLogs,omitempty"`
+}
+
+type FailureLogs struct {
+	// ConfigMapRef: reference to the ConfigMap the logs are written to; the log of each failed task is stored in the '<task name>.log' key.
+	ConfigMapRef *rtv1.Reference `json:"configMapRef"`
+
+	// TailLines: the number of lines written from the end of each log (default 50).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	TailLines *int `json:"tailLines,omitempty"`
+}
+
+type StageStatus struct {
+	// Name: the name of the stage.
+	Name string `json:"name"`
+
+	// State: the state of the stage (pending, inProgress, completed).
+	// +optional
+	State *string `json:"state,omitempty"`
+
+	// Result: the result of the completed stage (succeeded, succeededWithIssues, failed, canceled, skipped, abandoned).
+	// +optional
+	Result *string `json:"result,omitempty"`
+}
+
+type FailedTaskStatus struct {
+	// Name: the name of the failed task.
+	Name string `json:"name"`
+
+	// Stage: the name of the stage of the task.
+	// +optional
+	Stage *string `json:"stage,omitempty"`
+
+	// Errors: the error messages reported by the task.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ArtifactStatus struct {
+	// Name: the name of the artifact.
+	Name string `json:"name"`
+
+	// Type: the type of the artifact resource (i.e. 'PipelineArtifact', 'Container').
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	// DownloadUrl: the link to download the artifact.
+	// +optional
+	DownloadUrl *string `json:"downloadUrl,omitempty"`
 }
 
 type RunStatus struct {
@@ -93,6 +151,30 @@ type RunStatus struct {
 
 	State *string `json:"state,omitempty"`
 
+	// Result: the result of the completed run (succeeded, failed, canceled, unknown).
+	// +optional
+	Result *string `json:"result,omitempty"`
+
+	// CreatedDate: the creation date of the run.
+	// +optional
+	CreatedDate *metav1.Time `json:"createdDate,omitempty"`
+
+	// FinishedDate: the date the run completed.
+	// +optional
+	FinishedDate *metav1.Time `json:"finishedDate,omitempty"`
+
+	// Stages: the outcomes of the stages of the run.
+	// +optional
+	Stages []StageStatus `json:"stages,omitempty"`
+
+	// FailedTasks: the tasks that failed, with their error messages.
+	// +optional
+	FailedTasks []FailedTaskStatus `json:"failedTasks,omitempty"`
+
+	// Artifacts: the artifacts published by the completed run.
+	// +optional
+	Artifacts []ArtifactStatus `json:"artifacts,omitempty"`
+
 	// URL of the Run
 	Url *string `json:"url,omitempty"`
 }
@@ -102,6 +184,7 @@ type RunStatus struct {
 //+kubebuilder:resource:scope=Cluster,categories={krateo,azuredevops}
 //+kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
 //+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url"
+//+kubebuilder:printcolumn:name="RESULT",type="string",JSONPath=".status.result"
 //+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 //+kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status",priority=10
 
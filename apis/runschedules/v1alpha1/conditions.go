@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons a RunSchedule is or is not ready.
+const (
+	ReasonScheduled       rtv1.ConditionReason = "Scheduled"
+	ReasonSuspended       rtv1.ConditionReason = "Suspended"
+	ReasonInvalidSchedule rtv1.ConditionReason = "InvalidSchedule"
+)
+
+// GetCondition of this RunSchedule.
+func (rs *RunSchedule) GetCondition(ct rtv1.ConditionType) rtv1.Condition {
+	return rs.Status.GetCondition(ct)
+}
+
+// SetConditions of this RunSchedule.
+func (rs *RunSchedule) SetConditions(c ...rtv1.Condition) {
+	rs.Status.SetConditions(c...)
+}
+
+// Scheduled returns a condition that indicates the runs are scheduled.
+func Scheduled() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonScheduled,
+	}
+}
+
+// Suspended returns a condition that indicates the runs are not scheduled.
+func Suspended() rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSuspended,
+	}
+}
+
+// InvalidSchedule returns a condition that indicates the schedule
+// cannot be parsed.
+func InvalidSchedule(msg string) rtv1.Condition {
+	return rtv1.Condition{
+		Type:               rtv1.TypeReady,
+		Status:             metav1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInvalidSchedule,
+		Message:            msg,
+	}
+}
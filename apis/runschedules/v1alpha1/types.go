@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+)
+
+// ConcurrencyPolicy describes how the scheduled runs are handled
+// while a previous run is still in progress.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows the runs to overlap.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the new run if the previous one is in progress.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent cancels the run in progress and starts the new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// RunScheduleSpec defines the desired state of RunSchedule
+type RunScheduleSpec struct {
+	// Schedule: the cron expression of the runs (i.e. '0 2 * * *' or '@daily').
+	Schedule string `json:"schedule"`
+
+	// TimeZone: the time zone name of the schedule (i.e. 'Europe/Rome'); default is UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// StartingDeadlineSeconds: deadline in seconds for starting a run that missed its scheduled time; missed runs are skipped after it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy: how to treat the concurrent runs (Allow, Forbid, Replace); default is Allow.
+	// +kubebuilder:default=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend: if true the subsequent runs are not started; the runs in progress are not affected.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// SuccessfulRunsHistoryLimit: the number of successful Runs to keep (default 3).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+
+	// FailedRunsHistoryLimit: the number of failed or canceled Runs to keep (default 1).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+
+	// PipelineRef: reference to the pipeline.
+	PipelineRef *rtv1.Reference `json:"pipelineRef"`
+
+	// ConnectorConfigRef: configuration spec for the REST API client.
+	ConnectorConfigRef *rtv1.Reference `json:"connectorConfigRef"`
+
+	// RunParameters: the parameters of each run.
+	// +optional
+	RunParameters *runsv1alpha1.RunPipelineParameters `json:"runParameters,omitempty"`
+
+	// Timeout: the maximum duration of each run; the run is canceled if it is still in progress after it.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RunScheduleStatus is the observed state of the RunSchedule.
+type RunScheduleStatus struct {
+	rtv1.ConditionedStatus `json:",inline"`
+
+	// Active: the names of the Runs in progress.
+	// +optional
+	Active []string `json:"active,omitempty"`
+
+	// LastScheduleTime: the last time a run was scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime: the last time a run completed successfully.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// NextScheduleTime: the next time a run is scheduled.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,categories={krateo,azuredevops}
+//+kubebuilder:printcolumn:name="SCHEDULE",type="string",JSONPath=".spec.schedule"
+//+kubebuilder:printcolumn:name="SUSPEND",type="boolean",JSONPath=".spec.suspend"
+//+kubebuilder:printcolumn:name="LAST SCHEDULE",type="date",JSONPath=".status.lastScheduleTime"
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="REASON",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason",priority=10
+
+// RunSchedule is the Schema for the RunSchedules API: it creates
+// the child Runs of a pipeline on a cron schedule.
+type RunSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RunScheduleSpec   `json:"spec,omitempty"`
+	Status RunScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RunScheduleList contains a list of RunSchedule
+type RunScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunSchedule `json:"items"`
+}
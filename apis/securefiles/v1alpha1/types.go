@@ -20,11 +20,43 @@ type SecureFilesSpec struct {
 	// Name: the name of the SecureFile.
 	// +required
 	Name string `json:"name"`
+
+	// Source: the content of the SecureFile; it is uploaded to the library
+	// and uploaded again when it changes, keeping its pipeline authorizations.
+	// If not specified the SecureFile with the specified name is adopted.
+	// +optional
+	Source *SecureFileSource `json:"source,omitempty"`
+}
+
+// SecureFileSource selects the key of a Secret or of a ConfigMap
+// holding the content of the SecureFile. Exactly one must be specified.
+type SecureFileSource struct {
+	// SecretKeyRef: reference to the secret key holding the content.
+	// +optional
+	SecretKeyRef *rtv1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// ConfigMapKeyRef: reference to the config map key holding the content
+	// (binaryData keys take precedence over data keys).
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// ConfigMapKeySelector is a reference to a config map key
+// in an arbitrary namespace.
+type ConfigMapKeySelector struct {
+	rtv1.Reference `json:",inline"`
+
+	// The key to select.
+	Key string `json:"key"`
 }
 
 type SecureFilesStatus struct {
 	rtv1.ManagedStatus `json:",inline"`
 	Id                 *string `json:"id,omitempty"`
+
+	// ContentHash: the hash of the uploaded content, keyed by the UID of
+	// the resource not to disclose the content.
+	ContentHash string `json:"contentHash,omitempty"`
 }
 
 //+kubebuilder:object:root=true
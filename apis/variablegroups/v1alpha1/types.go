@@ -37,8 +37,25 @@ type VariableGroupsSpec struct {
 	// +optional
 	Type *string `json:"type,omitempty"`
 
-	// Variables: a map of variables in the VariableGroup.
+	// Variables: a map of variables in the VariableGroup. Must be empty for Key Vault groups.
+	// +optional
 	Variables map[string]VariableValue `json:"variables"`
+
+	// KeyVault: the Azure Key Vault whose secrets are linked to the VariableGroup; if specified the type of the group is 'AzureKeyVault'.
+	// +optional
+	KeyVault *KeyVaultProvider `json:"keyVault,omitempty"`
+}
+
+type KeyVaultProvider struct {
+	// EndpointRef: reference to the Azure Resource Manager Endpoint used to access the vault.
+	EndpointRef *rtv1.Reference `json:"endpointRef"`
+
+	// Vault: the name of the Azure Key Vault.
+	Vault string `json:"vault"`
+
+	// Secrets: the names of the vault secrets linked to the VariableGroup.
+	// +kubebuilder:validation:MinItems=1
+	Secrets []string `json:"secrets"`
 }
 
 type VariableGroupProjectReference struct {
@@ -64,14 +81,30 @@ type VariableValue struct {
 	// +optional
 	Value string `json:"value"`
 
+	// ValueFrom: the source of the variable value, resolved at reconcile
+	// time; if specified Value is ignored.
+	// +optional
+	ValueFrom *VariableValueSource `json:"valueFrom,omitempty"`
+
 	// IsSecret: the flag to indicate whether the variable value is secret.
 	// +optional
 	IsSecret bool `json:"isSecret"`
 }
 
+type VariableValueSource struct {
+	// SecretKeyRef: reference to the secret key holding the value.
+	SecretKeyRef *rtv1.SecretKeySelector `json:"secretKeyRef"`
+}
+
 type VariableGroupsStatus struct {
 	rtv1.ManagedStatus `json:",inline"`
 	Id                 string `json:"id,omitempty"`
+
+	// ValueHashes: the hashes of the values of the secret and of the
+	// referenced variables, by variable name. Azure DevOps never returns
+	// the secret values: the hashes are used to detect their changes.
+	// +optional
+	ValueHashes map[string]string `json:"valueHashes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -25,6 +25,31 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyVaultProvider) DeepCopyInto(out *KeyVaultProvider) {
+	*out = *in
+	if in.EndpointRef != nil {
+		in, out := &in.EndpointRef, &out.EndpointRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyVaultProvider.
+func (in *KeyVaultProvider) DeepCopy() *KeyVaultProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyVaultProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VariableGroup) DeepCopyInto(out *VariableGroup) {
 	*out = *in
@@ -174,9 +199,14 @@ func (in *VariableGroupsSpec) DeepCopyInto(out *VariableGroupsSpec) {
 		in, out := &in.Variables, &out.Variables
 		*out = make(map[string]VariableValue, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.KeyVault != nil {
+		in, out := &in.KeyVault, &out.KeyVault
+		*out = new(KeyVaultProvider)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableGroupsSpec.
@@ -193,6 +223,13 @@ func (in *VariableGroupsSpec) DeepCopy() *VariableGroupsSpec {
 func (in *VariableGroupsStatus) DeepCopyInto(out *VariableGroupsStatus) {
 	*out = *in
 	in.ManagedStatus.DeepCopyInto(&out.ManagedStatus)
+	if in.ValueHashes != nil {
+		in, out := &in.ValueHashes, &out.ValueHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableGroupsStatus.
@@ -208,6 +245,11 @@ func (in *VariableGroupsStatus) DeepCopy() *VariableGroupsStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VariableValue) DeepCopyInto(out *VariableValue) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(VariableValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableValue.
@@ -219,3 +261,23 @@ func (in *VariableValue) DeepCopy() *VariableValue {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VariableValueSource) DeepCopyInto(out *VariableValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableValueSource.
+func (in *VariableValueSource) DeepCopy() *VariableValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VariableValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -100,7 +100,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*CheckC
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &CheckConfiguration{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -108,7 +107,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*CheckC
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -137,52 +136,20 @@ type ListResponse struct {
 // List check configuration by project
 // GET https://dev.azure.com/{organization}/{project}/_apis/pipelines/checks/configurations?api-version=7.0-preview.1
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListResponse, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal + azuredevops.ApiPreviewFlag + ".1"}
-	}
-
-	if opts.ResourceId == nil {
-		return nil, errors.New("ResourceId parameter is required")
-	}
-	if opts.ResourceType == nil {
-		return nil, errors.New("ResourceType parameter is required")
-	}
-	var queryparams []string
-	queryparams = append(apiVersionParams, "resourceId", helpers.String(opts.ResourceId))
-	queryparams = append(queryparams, "resourceType", helpers.String(opts.ResourceType))
-	ubo := httplib.URLBuilderOptions{
-		BaseURL: cli.BaseURL(azuredevops.Default),
-		Path:    path.Join(opts.Organization, opts.Project, "_apis/pipelines/checks/configurations"),
-		Params:  queryparams,
-	}
-
-	uri, err := httplib.NewURLBuilder(ubo).Build()
+	pager, err := newPager(cli, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := httplib.Get(uri.String())
+	all, err := pager.All(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
-	val := &ListResponse{
-		Value: []CheckConfiguration{},
-	}
-
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		Verbose:         cli.Verbose(),
-		AuthMethod:      cli.AuthMethod(),
-		ResponseHandler: httplib.FromJSON(val),
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
 
-	return val, err
+	return &ListResponse{
+		Value: all,
+		Count: len(all),
+	}, nil
 }
 
 type FindOptions struct {
@@ -191,19 +158,51 @@ type FindOptions struct {
 }
 
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*CheckConfiguration, error) {
-	list, err := List(ctx, cli, opts.ListOptions)
+	pager, err := newPager(cli, opts.ListOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, check := range list.Value {
-		if strings.EqualFold(check.Type.ID, opts.Type.ID) {
-			return &check, nil
-		}
+	res, err := pager.Find(ctx, func(check CheckConfiguration) bool {
+		return strings.EqualFold(check.Type.ID, opts.Type.ID)
+	})
+	if err != nil {
+		return nil, err
 	}
+	if res != nil {
+		return res, nil
+	}
+
 	return nil, &httplib.StatusError{StatusCode: http.StatusNotFound, Inner: errors.New("check configuration not found")}
 }
 
+func newPager(cli *azuredevops.Client, opts ListOptions) (*azuredevops.Pager[CheckConfiguration], error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal + azuredevops.ApiPreviewFlag + ".1"}
+	}
+
+	if opts.ResourceId == nil {
+		return nil, errors.New("ResourceId parameter is required")
+	}
+	if opts.ResourceType == nil {
+		return nil, errors.New("ResourceType parameter is required")
+	}
+	var queryparams []string
+	queryparams = append(queryparams, apiVersionParams...)
+	queryparams = append(queryparams, "resourceId", helpers.String(opts.ResourceId))
+	queryparams = append(queryparams, "resourceType", helpers.String(opts.ResourceType))
+
+	return azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[CheckConfiguration], error) {
+			return azuredevops.FetchPage[CheckConfiguration](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   path.Join(opts.Organization, opts.Project, "_apis/pipelines/checks/configurations"),
+				Params: queryparams,
+				Page:   pr,
+			})
+		}), nil
+}
+
 type Approver struct {
 	DisplayName string `json:"displayName"`
 	ID          string `json:"id"`
@@ -301,14 +300,13 @@ func Create[T CheckOptions](ctx context.Context, cli *azuredevops.Client, opts C
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &CheckConfiguration{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
@@ -346,7 +344,7 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
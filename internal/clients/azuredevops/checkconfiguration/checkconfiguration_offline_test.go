@@ -0,0 +1,77 @@
+package checkconfiguration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestCheckConfigurationOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	approval := Type{ID: "8c6f20a7-a545-4486-9777-f762fafe0d4d", Name: "Approval"}
+	chk, err := Create(ctx, cli, CreateOptions[Approval]{
+		Organization: fake.Organization,
+		Project:      projectId,
+		CheckRes: Approval{
+			Settings: ApprovalSettings{
+				Approvers:            []Approver{{DisplayName: "Fake User", ID: "00000000-0000-0000-0000-000000000001"}},
+				MinRequiredApprovers: 1,
+			},
+			Timeout:  60,
+			Type:     approval,
+			Resource: Resource{Type: "environment", ID: "1"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listOpts := ListOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceType: helpers.StringPtr("environment"),
+		ResourceId:   helpers.StringPtr("1"),
+	}
+	res, err := List(ctx, cli, listOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 {
+		t.Fatalf("expected 1 check configuration, got: %d", res.Count)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{ListOptions: listOpts, Type: approval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != chk.ID {
+		t.Fatalf("expected check %d, got: %d", chk.ID, found.ID)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, CheckID: fmt.Sprint(chk.ID)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type.ID != approval.ID || got.Resource.ID != "1" {
+		t.Fatalf("unexpected check configuration: %+v", got)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, Project: projectId, CheckId: fmt.Sprint(chk.ID)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Find(ctx, cli, FindOptions{ListOptions: listOpts, Type: approval}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
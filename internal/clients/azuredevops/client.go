@@ -1,12 +1,17 @@
 package azuredevops
 
 import (
-	"fmt"
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	connectorconfigsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/connectorconfigs/v1alpha1"
 
 	"github.com/lucasepe/httplib"
+	"golang.org/x/oauth2"
 )
 
 const (
@@ -24,11 +29,47 @@ const (
 	Vssps   URIKey = "vssps"
 )
 
+type PathMode string
+
+const (
+	// OrganizationPathMode is the Azure DevOps Services layout:
+	// {uri}/{organization}/... with a base URL for each URIKey.
+	OrganizationPathMode PathMode = "Organization"
+	// CollectionPathMode is the Azure DevOps Server layout:
+	// {default uri}/{virtual directory}/{collection}/... for every URIKey.
+	CollectionPathMode PathMode = "Collection"
+
+	DefaultVirtualDirectory = "tfs"
+)
+
 type ClientOptions struct {
-	Token            string
+	Token string
+	// TokenSource provides the bearer tokens used in place of the
+	// personal access token (i.e. ServicePrincipalTokenSource).
+	TokenSource      oauth2.TokenSource
 	Verbose          bool
 	UriMap           *map[URIKey]string
 	ApiVersionConfig *connectorconfigsv1alpha1.APIVersionConfig
+	// MaxRetries is the maximum number of retries of throttled
+	// idempotent requests (default: DefaultMaxRetries).
+	MaxRetries *int
+	// RetryWaitMin is the initial backoff between retries when Azure DevOps
+	// does not send a Retry-After header (default: DefaultRetryWaitMin).
+	RetryWaitMin time.Duration
+	// RetryWaitMax is the maximum time to wait before a retry; longer
+	// Retry-After values are not waited for (default: DefaultRetryWaitMax).
+	RetryWaitMax time.Duration
+	// PathMode selects the URL layout (default: OrganizationPathMode).
+	PathMode PathMode
+	// VirtualDirectory is prepended to the collection in CollectionPathMode
+	// (default: DefaultVirtualDirectory, empty string for none).
+	VirtualDirectory *string
+	// RootCAs are the trusted CA certificates (default: the system ones).
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify disables the server certificate verification.
+	InsecureSkipVerify bool
+	// ProxyURL is the proxy to use (default: from the environment).
+	ProxyURL *url.URL
 }
 
 type Client struct {
@@ -40,25 +81,105 @@ type Client struct {
 }
 
 func NewClient(opts ClientOptions) *Client {
-	if opts.UriMap == nil {
-		opts.UriMap = &map[URIKey]string{
-			Default: "https://dev.azure.com",
-			Feeds:   "https://feeds.dev.azure.com",
-			Vssps:   "https://vssps.dev.azure.com",
+	uriMap := baseURLs(opts)
+
+	httpClient := NewHTTPClient(opts)
+	// Metrics are recorded for each attempt, throttling retries included.
+	httpClient.Transport = newMetricsTransport(httpClient.Transport, uriMap)
+	httpClient.Transport = newThrottlingTransport(httpClient.Transport, basePath(uriMap[Default]), opts)
+
+	var authMethod httplib.AuthMethod = &httplib.BasicAuth{
+		Username: UserAgent,
+		Password: opts.Token,
+	}
+	if opts.TokenSource != nil {
+		// The bearer token is set by the transport so that
+		// it can be refreshed (and errors returned) on each request.
+		httpClient.Transport = &oauth2.Transport{
+			Source: opts.TokenSource,
+			Base:   httpClient.Transport,
 		}
+		authMethod = nil
 	}
+	// A single span is started for each call, throttling retries included.
+	httpClient.Transport = newTracingTransport(httpClient.Transport, uriMap)
+
 	return &Client{
-		httpClient: httplib.NewClient(),
-		uriMap:     *opts.UriMap,
-		verbose:    opts.Verbose,
-		authMethod: &httplib.BasicAuth{
-			Username: UserAgent,
-			Password: opts.Token,
-		},
+		httpClient:       httpClient,
+		uriMap:           uriMap,
+		verbose:          opts.Verbose,
+		authMethod:       authMethod,
 		ApiVersionConfig: opts.ApiVersionConfig,
 	}
 }
 
+// NewHTTPClient returns an http.Client honoring the TLS and proxy options.
+func NewHTTPClient(opts ClientOptions) *http.Client {
+	httpClient := httplib.NewClient()
+
+	if tr, ok := httpClient.Transport.(*http.Transport); ok {
+		if opts.RootCAs != nil || opts.InsecureSkipVerify {
+			tr.TLSClientConfig = &tls.Config{
+				MinVersion:         tls.VersionTLS12,
+				RootCAs:            opts.RootCAs,
+				InsecureSkipVerify: opts.InsecureSkipVerify, // #nosec G402 -- explicitly requested
+			}
+		}
+		if opts.ProxyURL != nil {
+			tr.Proxy = http.ProxyURL(opts.ProxyURL)
+		}
+	}
+
+	return httpClient
+}
+
+// baseURLs returns the base URL of each URIKey according to the path mode.
+func baseURLs(opts ClientOptions) map[URIKey]string {
+	res := map[URIKey]string{
+		Default: "https://dev.azure.com",
+		Feeds:   "https://feeds.dev.azure.com",
+		Vssps:   "https://vssps.dev.azure.com",
+	}
+	if opts.UriMap != nil {
+		res = map[URIKey]string{}
+		for k, v := range *opts.UriMap {
+			if len(v) > 0 {
+				res[k] = v
+			}
+		}
+	}
+
+	if opts.PathMode != CollectionPathMode {
+		return res
+	}
+
+	// Azure DevOps Server serves every area from the collection URL.
+	vdir := DefaultVirtualDirectory
+	if opts.VirtualDirectory != nil {
+		vdir = strings.Trim(*opts.VirtualDirectory, "/")
+	}
+
+	base := strings.TrimSuffix(res[Default], "/")
+	if len(vdir) > 0 && !strings.HasSuffix(base, "/"+vdir) {
+		base = base + "/" + vdir
+	}
+
+	return map[URIKey]string{
+		Default: base,
+		Feeds:   base,
+		Vssps:   base,
+	}
+}
+
+// basePath returns the path of the URL (i.e. '/tfs').
+func basePath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
 func (c *Client) SetVerbose(v bool) {
 	c.verbose = v
 }
@@ -82,14 +203,3 @@ func (c *Client) AuthMethod() httplib.AuthMethod {
 func (c *Client) HTTPClient() *http.Client {
 	return c.httpClient
 }
-
-type APIError struct {
-	Message   string `json:"message"`
-	TypeKey   string `json:"typeKey"`
-	ErrorCode int    `json:"errorCode"`
-	EventID   int    `json:"eventId"`
-}
-
-func (e *APIError) Error() string {
-	return fmt.Sprintf("azuredevops: %s (%s, %d)", e.Message, e.TypeKey, e.EventID)
-}
@@ -0,0 +1,130 @@
+package azuredevops
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCollectionPathMode(t *testing.T) {
+	none := ""
+
+	tests := []struct {
+		name string
+		opts ClientOptions
+		want string
+	}{
+		{
+			name: "default virtual directory",
+			opts: ClientOptions{
+				UriMap:   &map[URIKey]string{Default: "https://ado.corp.local/"},
+				PathMode: CollectionPathMode,
+			},
+			want: "https://ado.corp.local/tfs",
+		},
+		{
+			name: "virtual directory already in base url",
+			opts: ClientOptions{
+				UriMap:   &map[URIKey]string{Default: "https://ado.corp.local/tfs"},
+				PathMode: CollectionPathMode,
+			},
+			want: "https://ado.corp.local/tfs",
+		},
+		{
+			name: "no virtual directory",
+			opts: ClientOptions{
+				UriMap:           &map[URIKey]string{Default: "https://ado.corp.local"},
+				PathMode:         CollectionPathMode,
+				VirtualDirectory: &none,
+			},
+			want: "https://ado.corp.local",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := NewClient(tc.opts)
+			for _, key := range []URIKey{Default, Feeds, Vssps} {
+				if got := cli.BaseURL(key); got != tc.want {
+					t.Fatalf("%s: expected %s, got: %s", key, tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOrganizationPathModeFallback(t *testing.T) {
+	cli := NewClient(ClientOptions{
+		UriMap: &map[URIKey]string{
+			Default: "https://ado.corp.local",
+			Feeds:   "",
+		},
+	})
+	if got := cli.BaseURL(Feeds); got != "https://ado.corp.local" {
+		t.Fatalf("expected feeds to fall back to default, got: %s", got)
+	}
+}
+
+func TestCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tfs/DefaultCollection/_apis/projects" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set(HeaderRateLimitResource, "ATCPU")
+		w.Header().Set(HeaderRateLimitRemaining, "100")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	uri := srv.URL + "/tfs/DefaultCollection/_apis/projects"
+
+	cli := NewClient(ClientOptions{UriMap: &map[URIKey]string{Default: srv.URL}, PathMode: CollectionPathMode})
+	if err := fireGet(t, cli, uri); err == nil {
+		t.Fatalf("expected untrusted certificate error")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	cli = NewClient(ClientOptions{UriMap: &map[URIKey]string{Default: srv.URL}, PathMode: CollectionPathMode, RootCAs: pool})
+	if err := fireGet(t, cli, uri); err != nil {
+		t.Fatal(err)
+	}
+
+	// The collection (not the virtual directory) identifies the throttling state.
+	if rl, ok := RateLimitStatus("DefaultCollection"); !ok || rl.Resource != "ATCPU" {
+		t.Fatalf("expected rate limit status for the collection, got: %+v", rl)
+	}
+
+	cli = NewClient(ClientOptions{UriMap: &map[URIKey]string{Default: srv.URL}, InsecureSkipVerify: true})
+	if err := fireGet(t, cli, uri); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := NewClient(ClientOptions{
+		UriMap:   &map[URIKey]string{Default: "http://ado.corp.local"},
+		ProxyURL: proxyURL,
+	})
+	if err := fireGet(t, cli, "http://ado.corp.local/proxy-org/_apis/projects"); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://ado.corp.local/proxy-org/_apis/projects" {
+		t.Fatalf("request not sent through the proxy: %s", proxied)
+	}
+}
@@ -0,0 +1,78 @@
+package connectiondata
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/lucasepe/httplib"
+)
+
+// Identity is the authenticated (or authorized) identity.
+type Identity struct {
+	Id                  string `json:"id,omitempty"`
+	Descriptor          string `json:"descriptor,omitempty"`
+	SubjectDescriptor   string `json:"subjectDescriptor,omitempty"`
+	ProviderDisplayName string `json:"providerDisplayName,omitempty"`
+	IsActive            bool   `json:"isActive,omitempty"`
+}
+
+// ConnectionData describes the service instance and
+// the identity used to connect to it.
+type ConnectionData struct {
+	AuthenticatedUser *Identity `json:"authenticatedUser,omitempty"`
+	AuthorizedUser    *Identity `json:"authorizedUser,omitempty"`
+	InstanceId        string    `json:"instanceId,omitempty"`
+	DeploymentId      string    `json:"deploymentId,omitempty"`
+	DeploymentType    string    `json:"deploymentType,omitempty"`
+}
+
+type GetOptions struct {
+	// URIKey selects the service to probe (default: Default).
+	URIKey       azuredevops.URIKey
+	Organization string
+}
+
+// Get returns the connection data of the organization; it is the lightweight
+// call every service host answers and so is used to validate a connector.
+// GET https://dev.azure.com/{organization}/_apis/connectionData
+func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*ConnectionData, error) {
+	uriKey := opts.URIKey
+	if len(uriKey) == 0 {
+		uriKey = azuredevops.Default
+	}
+
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(uriKey),
+		Path:    path.Join(opts.Organization, "_apis/connectionData"),
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	// Get a 401 instead of a 203 redirect to the sign-in page
+	// when the credentials are not valid.
+	req.Header.Set("X-TFS-FedAuthRedirect", "Suppress")
+
+	val := &ConnectionData{}
+
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod:      cli.AuthMethod(),
+		Verbose:         cli.Verbose(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
@@ -0,0 +1,37 @@
+package connectiondata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestGetOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	for _, key := range []azuredevops.URIKey{azuredevops.Default, azuredevops.Feeds, azuredevops.Vssps} {
+		res, err := Get(context.TODO(), srv.Client(), GetOptions{URIKey: key, Organization: fake.Organization})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.AuthenticatedUser == nil || !res.AuthenticatedUser.IsActive {
+			t.Fatalf("expected an active authenticated user, got: %+v", res.AuthenticatedUser)
+		}
+	}
+}
+
+func TestGetUnauthorizedOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	opts := srv.ClientOptions()
+	opts.Token = "wrong"
+
+	_, err := Get(context.TODO(), azuredevops.NewClient(opts), GetOptions{Organization: fake.Organization})
+	if err == nil {
+		t.Fatalf("expected unauthorized error")
+	}
+}
@@ -57,7 +57,7 @@ func (c *Client) DeleteDefinition(ctx context.Context, opts DeleteDefinitionOpti
 		AuthMethod: c.authMethod,
 		Verbose:    c.verbose,
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
@@ -2,9 +2,7 @@ package endpoints
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"path"
 	"reflect"
@@ -15,6 +13,17 @@ import (
 	"github.com/lucasepe/httplib"
 )
 
+// SchemeWorkloadIdentityFederation is the authorization scheme of the Azure
+// Resource Manager endpoints using a federated credential in place of a secret.
+const SchemeWorkloadIdentityFederation = "WorkloadIdentityFederation"
+
+// Authorization parameters generated by Azure DevOps for the workload identity
+// federation: the federated credential must trust this issuer and subject.
+const (
+	ParamWorkloadIdentityFederationIssuer  = "workloadIdentityFederationIssuer"
+	ParamWorkloadIdentityFederationSubject = "workloadIdentityFederationSubject"
+)
+
 // Represents the authorization used for service endpoint.
 type EndpointAuthorization struct {
 	// Gets or sets the parameters for the selected authorization scheme.
@@ -91,11 +100,6 @@ type FindOptions struct {
 	//IncludeDetails *bool
 }
 
-type FindResult struct {
-	Count  int               `json:"count"`
-	Values []ServiceEndpoint `json:"value,omitempty"`
-}
-
 func getAPIVersion(cli *azuredevops.Client) (apiVersionParams []string, isNone bool) {
 	if cli.ApiVersionConfig != nil {
 		apiVersion := cli.ApiVersionConfig.Endpoints
@@ -113,13 +117,7 @@ func getAPIVersion(cli *azuredevops.Client) (apiVersionParams []string, isNone b
 
 // GET https://dev.azure.com/{organization}/{project}/_apis/serviceendpoint/endpoints?endpointNames={endpointNames}&type={type}&authSchemes={authSchemes}&owner={owner}&includeFailed={includeFailed}&includeDetails={includeDetails}&api-version=7.0
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) ([]ServiceEndpoint, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-
 	var params []string
-	params = append(params, apiVersionParams...)
 	if len(opts.EndpointNames) > 0 {
 		params = append(params, "endpointNames", strings.Join(opts.EndpointNames, ","))
 	}
@@ -134,49 +132,11 @@ func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) ([]Ser
 	}
 	params = append(params, "includeFailed", fmt.Sprintf("%t", opts.IncludeFailed))
 
-	uri, err := httplib.NewURLBuilder(
-		httplib.URLBuilderOptions{
-			BaseURL: cli.BaseURL(azuredevops.Default),
-			Path:    path.Join(opts.Organization, opts.Project, "_apis/serviceendpoint/endpoints"),
-			Params:  params,
-		}).Build()
+	val, err := newPager(cli, path.Join(opts.Organization, opts.Project, "_apis/serviceendpoint/endpoints"), params).All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := httplib.Get(uri.String())
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
-	val := []ServiceEndpoint{}
-
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		AuthMethod: cli.AuthMethod(),
-		Verbose:    cli.Verbose(),
-		ResponseHandler: func(res *http.Response) error {
-			data, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-
-			all := &FindResult{}
-			if err = json.Unmarshal(data, &all); err != nil {
-				return err
-			}
-
-			val = append(val, all.Values...)
-
-			return nil
-		},
-
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
-
 	if len(val) == 0 {
 		return nil, &httplib.StatusError{
 			StatusCode: http.StatusNotFound,
@@ -184,18 +144,12 @@ func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) ([]Ser
 		}
 	}
 
-	return val, err
+	return val, nil
 }
 
 // GET https://dev.azure.com/{organization}/{project}/_apis/serviceendpoint/endpoints?type={type}&authSchemes={authSchemes}&endpointIds={endpointIds}&owner={owner}&includeFailed={includeFailed}&includeDetails={includeDetails}&api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]ServiceEndpoint, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-
 	var params []string
-	params = append(params, apiVersionParams...)
 	if len(opts.EndpointIds) > 0 {
 		params = append(params, "endpointIds", strings.Join(opts.EndpointIds, ","))
 	}
@@ -210,48 +164,10 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]Ser
 	}
 	params = append(params, "includeFailed", fmt.Sprintf("%t", opts.IncludeFailed))
 
-	uri, err := httplib.NewURLBuilder(
-		httplib.URLBuilderOptions{
-			BaseURL: cli.BaseURL(azuredevops.Default),
-			Path:    path.Join(opts.Organization, "_apis/serviceendpoint/endpoints"),
-			Params:  params,
-		}).Build()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := httplib.Get(uri.String())
+	val, err := newPager(cli, path.Join(opts.Organization, "_apis/serviceendpoint/endpoints"), params).All(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
-	val := []ServiceEndpoint{}
-
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		AuthMethod: cli.AuthMethod(),
-		Verbose:    cli.Verbose(),
-		ResponseHandler: func(res *http.Response) error {
-			data, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-
-			all := &FindResult{}
-			if err = json.Unmarshal(data, &all); err != nil {
-				return err
-			}
-
-			val = append(val, all.Values...)
-
-			return nil
-		},
-
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
 
 	if len(val) == 0 {
 		return nil, &httplib.StatusError{
@@ -260,7 +176,27 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]Ser
 		}
 	}
 
-	return val, err
+	return val, nil
+}
+
+func newPager(cli *azuredevops.Client, fullPath string, queryParams []string) *azuredevops.Pager[ServiceEndpoint] {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+
+	var params []string
+	params = append(params, apiVersionParams...)
+	params = append(params, queryParams...)
+
+	return azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[ServiceEndpoint], error) {
+			return azuredevops.FetchPage[ServiceEndpoint](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   fullPath,
+				Params: params,
+				Page:   pr,
+			})
+		})
 }
 
 type ListOptions struct {
@@ -314,7 +250,6 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ServiceEndpoint{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -322,7 +257,7 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -378,7 +313,7 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
@@ -416,7 +351,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Servic
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ServiceEndpoint{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -424,7 +358,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Servic
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if val != nil && reflect.DeepEqual(*val, ServiceEndpoint{}) {
@@ -463,7 +397,6 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ServiceEndpoint{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -471,7 +404,7 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -507,7 +440,6 @@ func ShareServiceEndpoint(ctx context.Context, cli *azuredevops.Client, opts Sha
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ServiceEndpoint{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -515,7 +447,7 @@ func ShareServiceEndpoint(ctx context.Context, cli *azuredevops.Client, opts Sha
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -0,0 +1,89 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestEndpointsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	demo := srv.AddProject(fake.Organization, "demo")
+	other := srv.AddProject(fake.Organization, "other")
+	demoId, otherId := fake.String(demo["id"]), fake.String(other["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	ep, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		Endpoint: &ServiceEndpoint{
+			Name: helpers.StringPtr("github"),
+			Type: helpers.StringPtr("github"),
+			Url:  helpers.StringPtr("https://github.com"),
+			Authorization: &EndpointAuthorization{
+				Scheme:     helpers.StringPtr("PersonalAccessToken"),
+				Parameters: map[string]string{"accessToken": "token"},
+			},
+			ServiceEndpointProjectReferences: []ServiceEndpointProjectReference{
+				{Name: helpers.StringPtr("github"), ProjectReference: &ProjectReference{Id: helpers.StringPtr(demoId)}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: demoId, EndpointNames: []string{"github"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || helpers.String(res[0].Id) != helpers.String(ep.Id) {
+		t.Fatalf("expected endpoint %s, got: %+v", helpers.String(ep.Id), res)
+	}
+	if _, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: otherId, EndpointNames: []string{"github"}}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+
+	ep, err = ShareServiceEndpoint(ctx, cli, ShareOptions{
+		Organization: fake.Organization,
+		EndpointId:   helpers.String(ep.Id),
+		Endpoints: []ServiceEndpointProjectReference{
+			{Name: helpers.StringPtr("github"), ProjectReference: &ProjectReference{Id: helpers.StringPtr(otherId)}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !helpers.Bool(ep.IsShared) {
+		t.Fatalf("expected endpoint to be shared")
+	}
+
+	ep.Description = helpers.StringPtr("updated")
+	if _, err := Update(ctx, cli, UpdateOptions{Organization: fake.Organization, EndpointId: helpers.String(ep.Id), Endpoint: ep}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: otherId, EndpointId: helpers.String(ep.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(got.Description) != "updated" {
+		t.Fatalf("expected updated description, got: %s", helpers.String(got.Description))
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, EndpointId: helpers.String(ep.Id), ProjectIds: []string{demoId}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: demoId, EndpointId: helpers.String(ep.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: otherId, EndpointId: helpers.String(ep.Id)}); err != nil {
+		t.Fatalf("expected endpoint still shared with 'other', got: %v", err)
+	}
+}
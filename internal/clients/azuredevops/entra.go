@@ -0,0 +1,385 @@
+package azuredevops
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// AzureDevOpsScope is the default scope requested for Azure DevOps
+	// access tokens (499b84ac-... is the Azure DevOps resource ID).
+	AzureDevOpsScope = "499b84ac-1321-427f-aa17-267ca6975798/.default"
+
+	entraTokenURLFormat = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionTTL  = 10 * time.Minute
+	tokenExpiryDelta    = 2 * time.Minute
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// ServicePrincipalCredentials holds the Microsoft Entra ID application
+// credentials used to obtain access tokens with the client credentials flow.
+type ServicePrincipalCredentials struct {
+	TenantID string
+	ClientID string
+	// ClientSecret authenticates the application with a shared secret.
+	ClientSecret string
+	// ClientCertificate is the PEM encoded certificate and private key
+	// used to sign the client assertion (used if ClientSecret is empty).
+	ClientCertificate []byte
+	// TokenURL overrides the Entra ID token endpoint.
+	TokenURL string
+	// Scope overrides the requested scope (default: AzureDevOpsScope).
+	Scope string
+}
+
+func (sp *ServicePrincipalCredentials) tokenURL() string {
+	if len(sp.TokenURL) > 0 {
+		return sp.TokenURL
+	}
+	return fmt.Sprintf(entraTokenURLFormat, url.PathEscape(sp.TenantID))
+}
+
+func (sp *ServicePrincipalCredentials) scope() string {
+	if len(sp.Scope) > 0 {
+		return sp.Scope
+	}
+	return AzureDevOpsScope
+}
+
+// cacheKey identifies the credentials without keeping the secrets in clear.
+func (sp *ServicePrincipalCredentials) cacheKey() string {
+	return hashKey(sp.TenantID, sp.ClientID, sp.ClientSecret,
+		string(sp.ClientCertificate), sp.tokenURL(), sp.scope())
+}
+
+// identity identifies the application the credentials belong to
+// and the scope of its tokens.
+func (sp *ServicePrincipalCredentials) identity() string {
+	return hashKey("service-principal", sp.tokenURL(), sp.ClientID, sp.scope())
+}
+
+// TokenError is returned when the token endpoint refuses to issue a token.
+type TokenError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *TokenError) Error() string {
+	return fmt.Sprintf("entra: cannot get access token (%d): %s %s",
+		e.StatusCode, e.Code, e.Description)
+}
+
+// Clients are created on every reconcile, tokens are shared
+// process-wide so they are requested only when expired. The token
+// sources are cached by identity (token endpoint, client and scope):
+// when the credentials of an identity change (i.e. a rotated client
+// secret) the superseded token source is replaced.
+var tokenSources = struct {
+	sync.Mutex
+	m map[string]cachedTokenSource
+}{m: map[string]cachedTokenSource{}}
+
+type cachedTokenSource struct {
+	// key identifies the credentials the token source was created with.
+	key string
+	src httpTokenSource
+	ts  oauth2.TokenSource
+}
+
+// httpTokenSource is a token source requesting the tokens with
+// a replaceable HTTP client.
+type httpTokenSource interface {
+	oauth2.TokenSource
+	setHTTPClient(httpClient *http.Client)
+}
+
+// tokenHTTPClient is the HTTP client used to request the tokens: the
+// cached token sources use the one of the latest connector settings
+// (CA bundle, proxy...) for the next requests.
+type tokenHTTPClient struct {
+	val atomic.Pointer[http.Client]
+}
+
+func (c *tokenHTTPClient) setHTTPClient(httpClient *http.Client) {
+	c.val.Store(httpClient)
+}
+
+func (c *tokenHTTPClient) httpClient() *http.Client {
+	return c.val.Load()
+}
+
+// cachedTokenSourceFor returns the cached token source of the identity
+// if its credentials did not change, otherwise the one created by newFn.
+// The token source requests the next tokens with the HTTP client.
+func cachedTokenSourceFor(identity, key string, httpClient *http.Client, newFn func() httpTokenSource) oauth2.TokenSource {
+	tokenSources.Lock()
+	defer tokenSources.Unlock()
+	if cached, ok := tokenSources.m[identity]; ok && cached.key == key {
+		cached.src.setHTTPClient(httpClient)
+		return cached.ts
+	}
+
+	src := newFn()
+	src.setHTTPClient(httpClient)
+	res := oauth2.ReuseTokenSourceWithExpiry(nil, src, tokenExpiryDelta)
+	tokenSources.m[identity] = cachedTokenSource{key: key, src: src, ts: res}
+	return res
+}
+
+// hashKey returns the hex encoded SHA-256 hash of the values.
+func hashKey(values ...string) string {
+	h := sha256.New()
+	for _, el := range values {
+		h.Write([]byte(el))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ServicePrincipalTokenSource returns a TokenSource that caches and
+// automatically refreshes the access tokens of the service principal.
+func ServicePrincipalTokenSource(sp *ServicePrincipalCredentials, httpClient *http.Client) (oauth2.TokenSource, error) {
+	if sp == nil {
+		return nil, errors.New("no service principal credentials specified")
+	}
+	if len(sp.TenantID) == 0 && len(sp.TokenURL) == 0 {
+		return nil, errors.New("service principal tenantId is required")
+	}
+	if len(sp.ClientID) == 0 {
+		return nil, errors.New("service principal clientId is required")
+	}
+
+	ts := &servicePrincipalTokenSource{
+		creds: *sp,
+		now:   time.Now,
+	}
+	if len(sp.ClientSecret) == 0 {
+		if len(sp.ClientCertificate) == 0 {
+			return nil, errors.New("service principal clientSecret or clientCertificate is required")
+		}
+		cert, key, err := parseCertificate(sp.ClientCertificate)
+		if err != nil {
+			return nil, err
+		}
+		ts.cert, ts.key = cert, key
+	}
+
+	return cachedTokenSourceFor(sp.identity(), sp.cacheKey(), httpClient, func() httpTokenSource {
+		return ts
+	}), nil
+}
+
+type servicePrincipalTokenSource struct {
+	tokenHTTPClient
+	creds ServicePrincipalCredentials
+	cert  *x509.Certificate
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// Token requests a new access token to the token endpoint.
+// https://learn.microsoft.com/en-us/entra/identity-platform/v2-oauth2-client-creds-grant-flow
+func (ts *servicePrincipalTokenSource) Token() (*oauth2.Token, error) {
+	tokenURL := ts.creds.tokenURL()
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", ts.creds.ClientID)
+	form.Set("scope", ts.creds.scope())
+	if ts.key != nil {
+		assertion, err := ts.clientAssertion(tokenURL)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("client_assertion_type", clientAssertionType)
+		form.Set("client_assertion", assertion)
+	} else {
+		form.Set("client_secret", ts.creds.ClientSecret)
+	}
+
+	return fetchToken(context.Background(), ts.httpClient(), tokenURL, form, ts.now)
+}
+
+// clientAssertion builds the JWT signed with the certificate private key.
+// https://learn.microsoft.com/en-us/entra/identity-platform/certificate-credentials
+func (ts *servicePrincipalTokenSource) clientAssertion(aud string) (string, error) {
+	thumbprint := sha1.Sum(ts.cert.Raw)
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
+	now := ts.now()
+	header := map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	}
+	claims := map[string]any{
+		"aud": aud,
+		"iss": ts.creds.ClientID,
+		"sub": ts.creds.ClientID,
+		"jti": hex.EncodeToString(jti),
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+		"exp": now.Add(clientAssertionTTL).Unix(),
+	}
+
+	hdr, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	clm, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(hdr) + "." +
+		base64.RawURLEncoding.EncodeToString(clm)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, ts.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// fetchToken posts the form to the token endpoint and decodes the response.
+// The token sources are not context aware: the request is bounded by the
+// client timeout (default: tokenRequestTimeout).
+func fetchToken(ctx context.Context, httpClient *http.Client, tokenURL string, form url.Values, now func() time.Time) (*oauth2.Token, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	timeout := httpClient.Timeout
+	if timeout <= 0 {
+		timeout = tokenRequestTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		tokErr := &TokenError{StatusCode: res.StatusCode}
+		if err := json.Unmarshal(data, tokErr); err != nil || len(tokErr.Code) == 0 {
+			tokErr.Description = strings.TrimSpace(string(data))
+		}
+		return nil, tokErr
+	}
+
+	val := struct {
+		TokenType   string      `json:"token_type"`
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return nil, err
+	}
+	if len(val.AccessToken) == 0 {
+		return nil, errors.New("entra: token endpoint returned an empty access token")
+	}
+
+	tok := &oauth2.Token{
+		AccessToken: val.AccessToken,
+		TokenType:   val.TokenType,
+	}
+	if secs, err := val.ExpiresIn.Int64(); err == nil && secs > 0 {
+		tok.Expiry = now().Add(time.Duration(secs) * time.Second)
+	}
+
+	return tok, nil
+}
+
+// parseCertificate extracts the first certificate and the RSA
+// private key from PEM encoded data.
+func parseCertificate(data []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	var cert *x509.Certificate
+	var key *rsa.PrivateKey
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "CERTIFICATE":
+			if cert != nil {
+				continue
+			}
+			val, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			cert = val
+		case "RSA PRIVATE KEY":
+			val, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			key = val
+		case "PRIVATE KEY":
+			val, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			rsaKey, ok := val.(*rsa.PrivateKey)
+			if !ok {
+				return nil, nil, errors.New("client certificate private key must be RSA")
+			}
+			key = rsaKey
+		}
+	}
+
+	if cert == nil {
+		return nil, nil, errors.New("no certificate found in client certificate PEM")
+	}
+	if key == nil {
+		return nil, nil, errors.New("no private key found in client certificate PEM")
+	}
+
+	return cert, key, nil
+}
@@ -0,0 +1,323 @@
+package azuredevops
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestServicePrincipalClientSecret(t *testing.T) {
+	var tokenCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+			atomic.AddInt32(&tokenCalls, 1)
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if r.PostForm.Get("grant_type") != "client_credentials" ||
+				r.PostForm.Get("client_id") != "client-secret-app" ||
+				r.PostForm.Get("client_secret") != "s3cr3t" ||
+				r.PostForm.Get("scope") != AzureDevOpsScope {
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"token_type":   "Bearer",
+				"access_token": "access-token",
+				"expires_in":   3599,
+			})
+			return
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer access-token" {
+			t.Errorf("unexpected Authorization header: %s", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	creds := &ServicePrincipalCredentials{
+		TenantID:     "tenant",
+		ClientID:     "client-secret-app",
+		ClientSecret: "s3cr3t",
+		TokenURL:     srv.URL + "/tenant/oauth2/v2.0/token",
+	}
+
+	for i := 0; i < 2; i++ {
+		ts, err := ServicePrincipalTokenSource(creds, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cli := NewClient(ClientOptions{
+			UriMap:      &map[URIKey]string{Default: srv.URL},
+			TokenSource: ts,
+		})
+		if err := fireGet(t, cli, srv.URL+"/sp-org/_apis/projects"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&tokenCalls); got != 1 {
+		t.Fatalf("expected the token to be requested once, got: %d", got)
+	}
+}
+
+func TestServicePrincipalTokenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error":             "invalid_client",
+			"error_description": "AADSTS7000215: Invalid client secret provided.",
+		})
+	}))
+	defer srv.Close()
+
+	ts, err := ServicePrincipalTokenSource(&ServicePrincipalCredentials{
+		TenantID:     "tenant",
+		ClientID:     "wrong-secret-app",
+		ClientSecret: "wrong",
+		TokenURL:     srv.URL,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ts.Token()
+	var tokErr *TokenError
+	if !errors.As(err, &tokErr) {
+		t.Fatalf("expected TokenError, got: %v", err)
+	}
+	if tokErr.Code != "invalid_client" {
+		t.Fatalf("unexpected error code: %s", tokErr.Code)
+	}
+}
+
+func TestServicePrincipalSecretRotation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"access_token": "token-" + r.PostForm.Get("client_secret"),
+			"expires_in":   3599,
+		})
+	}))
+	defer srv.Close()
+
+	before := cachedTokenSources()
+
+	for _, secret := range []string{"first", "second", "third"} {
+		ts, err := ServicePrincipalTokenSource(&ServicePrincipalCredentials{
+			TenantID:     "tenant",
+			ClientID:     "rotated-secret-app",
+			ClientSecret: secret,
+			TokenURL:     srv.URL,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.AccessToken != "token-"+secret {
+			t.Fatalf("expected the token of the %s secret, got: %s", secret, tok.AccessToken)
+		}
+	}
+
+	// The token sources of the superseded secrets are evicted.
+	if got := cachedTokenSources() - before; got != 1 {
+		t.Fatalf("expected 1 cached token source, got: %d", got)
+	}
+}
+
+func TestServicePrincipalTransportChange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		// The tokens expire within the expiry delta: each one is requested.
+		json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"access_token": "token-" + r.PostForm.Get("scope"),
+			"expires_in":   60,
+		})
+	}))
+	defer srv.Close()
+
+	before := cachedTokenSources()
+	var first, second int32
+	newTokenSource := func(scope string, calls *int32) oauth2.TokenSource {
+		ts, err := ServicePrincipalTokenSource(&ServicePrincipalCredentials{
+			TenantID:     "tenant",
+			ClientID:     "transport-app",
+			ClientSecret: "s3cr3t",
+			TokenURL:     srv.URL,
+			Scope:        scope,
+		}, &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(calls, 1)
+			return http.DefaultTransport.RoundTrip(req)
+		})})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+
+	if _, err := newTokenSource("scope-a", &first).Token(); err != nil {
+		t.Fatal(err)
+	}
+	// The cached token source requests the next tokens with the client
+	// of the latest settings (i.e. a changed proxy).
+	if _, err := newTokenSource("scope-a", &second).Token(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&first) != 1 || atomic.LoadInt32(&second) != 1 {
+		t.Fatalf("expected a token request per client, got: %d, %d", first, second)
+	}
+
+	// The tokens of the scopes are cached apart.
+	tokA, err := newTokenSource("scope-a", &second).Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokB, err := newTokenSource("scope-b", &second).Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokA.AccessToken != "token-scope-a" || tokB.AccessToken != "token-scope-b" {
+		t.Fatalf("unexpected tokens: %s, %s", tokA.AccessToken, tokB.AccessToken)
+	}
+	if got := cachedTokenSources() - before; got != 2 {
+		t.Fatalf("expected 2 cached token sources, got: %d", got)
+	}
+}
+
+// cachedTokenSources returns the number of cached token sources.
+func cachedTokenSources() int {
+	tokenSources.Lock()
+	defer tokenSources.Unlock()
+	return len(tokenSources.m)
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestServicePrincipalClientCertificate(t *testing.T) {
+	certPEM, key := newTestCertificate(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("client_assertion_type") != clientAssertionType {
+			t.Errorf("unexpected client_assertion_type: %s", r.PostForm.Get("client_assertion_type"))
+		}
+		if len(r.PostForm.Get("client_secret")) > 0 {
+			t.Errorf("client_secret must not be sent")
+		}
+
+		parts := strings.Split(r.PostForm.Get("client_assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("malformed client assertion")
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Fatalf("invalid client assertion signature: %v", err)
+		}
+
+		data, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims := map[string]any{}
+		if err := json.Unmarshal(data, &claims); err != nil {
+			t.Fatal(err)
+		}
+		if claims["iss"] != "client-cert-app" || claims["aud"] != "http://"+r.Host+r.URL.Path {
+			t.Errorf("unexpected claims: %v", claims)
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"access_token": "cert-access-token",
+			"expires_in":   "3599",
+		})
+	}))
+	defer srv.Close()
+
+	ts, err := ServicePrincipalTokenSource(&ServicePrincipalCredentials{
+		TenantID:          "tenant",
+		ClientID:          "client-cert-app",
+		ClientCertificate: certPEM,
+		TokenURL:          srv.URL + "/token",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "cert-access-token" {
+		t.Fatalf("unexpected access token: %s", tok.AccessToken)
+	}
+	if tok.Expiry.IsZero() {
+		t.Fatalf("expected token expiry to be set")
+	}
+}
+
+func newTestCertificate(t *testing.T) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client-cert-app"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	res = append(res, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})...)
+	return res, key
+}
@@ -98,7 +98,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Enviro
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Environment{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -106,7 +105,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Enviro
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -154,14 +153,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Environment{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
@@ -173,53 +171,10 @@ type ListOptions struct {
 	IncludeUrls  bool
 }
 
-type ListReturn struct {
-	Count int           `json:"count"`
-	Value []Environment `json:"value"`
-}
-
 // Get all environments.
 // GET https://dev.azure.com/{organization}/{project}/_apis/distributedtask/environments?api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]Environment, error) {
-	fullPath := path.Join(opts.Organization, opts.Project, "_apis/distributedtask/environments")
-
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-
-	ubo := httplib.URLBuilderOptions{
-		BaseURL: cli.BaseURL(azuredevops.Default),
-		Path:    fullPath,
-		Params:  apiVersionParams,
-	}
-
-	uri, err := httplib.NewURLBuilder(ubo).Build()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := httplib.Get(uri.String())
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
-	val := &ListReturn{
-		Value: []Environment{},
-	}
-
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		Verbose:         cli.Verbose(),
-		AuthMethod:      cli.AuthMethod(),
-		ResponseHandler: httplib.FromJSON(val),
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
-
-	return val.Value, err
+	return newPager(cli, opts).All(ctx)
 }
 
 type FindOptions struct {
@@ -230,22 +185,31 @@ type FindOptions struct {
 
 // Find an environment by its name.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*Environment, error) {
-	all, err := List(ctx, cli, ListOptions{
+	pager := newPager(cli, ListOptions{
 		Organization: opts.Organization,
 		Project:      opts.Project,
 		IncludeUrls:  true,
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	for _, el := range all {
-		if helpers.String(el.Name) == opts.EnvironmentName {
-			return &el, nil
-		}
+	return pager.Find(ctx, func(el Environment) bool {
+		return helpers.String(el.Name) == opts.EnvironmentName
+	})
+}
+
+func newPager(cli *azuredevops.Client, opts ListOptions) *azuredevops.Pager[Environment] {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
 
-	return nil, nil
+	return azuredevops.NewPager(azuredevops.ContinuationTokenPaging, azuredevops.DefaultPageSize,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[Environment], error) {
+			return azuredevops.FetchPage[Environment](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   path.Join(opts.Organization, opts.Project, "_apis/distributedtask/environments"),
+				Params: apiVersionParams,
+				Page:   pr,
+			})
+		})
 }
 
 type DeleteOptions struct {
@@ -281,12 +245,11 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 
@@ -328,14 +291,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Environment{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
@@ -0,0 +1,65 @@
+package environments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestEnvironmentsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	env, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Environment:  &Environment{Name: helpers.StringPtr("staging"), Description: helpers.StringPtr("staging env")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Project == nil || env.Project.Id != projectId {
+		t.Fatalf("expected project reference %s, got: %+v", projectId, env.Project)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: projectId, EnvironmentName: "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || helpers.Int(found.Id) != helpers.Int(env.Id) {
+		t.Fatalf("expected environment %d, got: %v", helpers.Int(env.Id), found)
+	}
+
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization:  fake.Organization,
+		Project:       projectId,
+		EnvironmentId: helpers.Int(env.Id),
+		Environment:   &Environment{Name: helpers.StringPtr("staging"), Description: helpers.StringPtr("updated")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, EnvironmentId: helpers.Int(env.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(got.Description) != "updated" {
+		t.Fatalf("expected updated description, got: %s", helpers.String(got.Description))
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, Project: projectId, EnvironmentId: helpers.Int(env.Id)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, EnvironmentId: helpers.Int(env.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
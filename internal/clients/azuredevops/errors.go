@@ -0,0 +1,149 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lucasepe/httplib"
+)
+
+// Classes of the errors returned by Azure DevOps: use errors.Is
+// (or the Is* helpers) to check the class of an error.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrConflict      = errors.New("update conflict")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrThrottled     = errors.New("throttled")
+	ErrValidation    = errors.New("validation failed")
+)
+
+// maxErrorBodySize is the maximum size of the error envelopes read.
+const maxErrorBodySize = 1 << 20
+
+// APIError is the error envelope returned by Azure DevOps.
+type APIError struct {
+	Message   string `json:"message"`
+	TypeKey   string `json:"typeKey"`
+	ErrorCode int    `json:"errorCode"`
+	EventID   int    `json:"eventId"`
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if len(e.TypeKey) == 0 {
+		return fmt.Sprintf("azuredevops: %s", e.Message)
+	}
+	return fmt.Sprintf("azuredevops: %s (%s, %d)", e.Message, e.TypeKey, e.EventID)
+}
+
+// Is reports whether the error belongs to the target class.
+func (e *APIError) Is(target error) bool {
+	class := classify(e.StatusCode, e.TypeKey)
+	return class != nil && class == target
+}
+
+// DecodeError returns a response validator accepting the specified status
+// codes; any other response is returned as an httplib.StatusError wrapping
+// the APIError decoded from its body.
+func DecodeError(acceptStatuses ...int) httplib.HandleResponseFunc {
+	return func(res *http.Response) error {
+		if slices.Contains(acceptStatuses, res.StatusCode) {
+			return nil
+		}
+
+		apiErr := &APIError{}
+		if res.Body != nil {
+			// Some endpoints return HTML error pages: the body is ignored
+			// if it is not a JSON error envelope.
+			data, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+			if err != nil || json.Unmarshal(data, apiErr) != nil {
+				apiErr = &APIError{}
+			}
+		}
+		apiErr.StatusCode = res.StatusCode
+		if len(apiErr.Message) == 0 {
+			apiErr.Message = fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+		}
+
+		return &httplib.StatusError{StatusCode: res.StatusCode, Inner: apiErr}
+	}
+}
+
+// classify returns the class of the error described by the status
+// code and the Azure DevOps type key (i.e. 'ProjectAlreadyExistsException').
+func classify(statusCode int, typeKey string) error {
+	switch {
+	case strings.HasSuffix(typeKey, "UpdateConflictException"):
+		return ErrConflict
+	case strings.HasSuffix(typeKey, "AlreadyExistsException"), statusCode == http.StatusConflict:
+		return ErrAlreadyExists
+	case strings.HasSuffix(typeKey, "NotFoundException"), statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case typeKey == "AccessCheckException", statusCode == http.StatusForbidden:
+		return ErrForbidden
+	case isThrottled(statusCode):
+		return ErrThrottled
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
+
+// is reports whether the error belongs to the target class; status
+// errors without an Azure DevOps envelope are classified by status code.
+func is(err error, target error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, target) {
+		return true
+	}
+	se := new(httplib.StatusError)
+	if errors.As(err, &se) {
+		return classify(se.StatusCode, "") == target
+	}
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return is(err, ErrNotFound)
+}
+
+// IsAlreadyExists returns true if the resource already exists
+// or the request conflicts with its current state.
+func IsAlreadyExists(err error) bool {
+	return is(err, ErrAlreadyExists)
+}
+
+// IsConflict returns true if the resource was changed by someone
+// else since it was read (i.e. a stale revision was sent).
+func IsConflict(err error) bool {
+	return is(err, ErrConflict)
+}
+
+func IsUnauthorized(err error) bool {
+	return is(err, ErrUnauthorized)
+}
+
+func IsForbidden(err error) bool {
+	return is(err, ErrForbidden)
+}
+
+func IsThrottled(err error) bool {
+	return is(err, ErrThrottled)
+}
+
+func IsValidation(err error) bool {
+	return is(err, ErrValidation)
+}
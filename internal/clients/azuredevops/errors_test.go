@@ -0,0 +1,123 @@
+package azuredevops
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/httplib"
+)
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+		msg    string
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"message":"TF200016: The project does not exist.","typeKey":"ProjectDoesNotExistException","eventId":3000}`,
+			want:   ErrNotFound,
+			msg:    "TF200016: The project does not exist.",
+		},
+		{
+			name:   "html not found",
+			status: http.StatusNotFound,
+			body:   `<html><body>Not Found</body></html>`,
+			want:   ErrNotFound,
+			msg:    "404 Not Found",
+		},
+		{
+			name:   "already exists",
+			status: http.StatusBadRequest,
+			body:   `{"message":"A Git repository with the name demo already exists.","typeKey":"GitRepositoryNameAlreadyExistsException"}`,
+			want:   ErrAlreadyExists,
+		},
+		{
+			name:   "conflict",
+			status: http.StatusConflict,
+			want:   ErrAlreadyExists,
+		},
+		{
+			name:   "update conflict",
+			status: http.StatusConflict,
+			body:   `{"message":"The definition has been updated by another client.","typeKey":"DefinitionUpdateConflictException"}`,
+			want:   ErrConflict,
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			want:   ErrUnauthorized,
+		},
+		{
+			name:   "access check",
+			status: http.StatusBadRequest,
+			body:   `{"message":"Access denied.","typeKey":"AccessCheckException"}`,
+			want:   ErrForbidden,
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			want:   ErrForbidden,
+		},
+		{
+			name:   "throttled",
+			status: http.StatusTooManyRequests,
+			want:   ErrThrottled,
+		},
+		{
+			name:   "validation",
+			status: http.StatusBadRequest,
+			body:   `{"message":"The name is invalid.","typeKey":"InvalidArgumentValueException"}`,
+			want:   ErrValidation,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DecodeError(http.StatusOK)(&http.Response{
+				StatusCode: tc.status,
+				Body:       io.NopCloser(strings.NewReader(tc.body)),
+			})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got: %v", tc.want, err)
+			}
+			if !httplib.HasStatusErr(err, tc.status) {
+				t.Fatalf("expected a status error, got: %v", err)
+			}
+
+			apiErr := &APIError{}
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an APIError, got: %v", err)
+			}
+			if len(tc.msg) > 0 && apiErr.Message != tc.msg {
+				t.Fatalf("expected message %q, got: %q", tc.msg, apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestDecodeErrorAcceptedStatus(t *testing.T) {
+	err := DecodeError(http.StatusOK, http.StatusNoContent)(&http.Response{StatusCode: http.StatusNoContent})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestIsHelpers(t *testing.T) {
+	// Status errors built by the clients themselves carry no envelope.
+	if !IsNotFound(&httplib.StatusError{StatusCode: http.StatusNotFound}) {
+		t.Error("expected a not found status error to be not found")
+	}
+	if !IsThrottled(&ThrottledError{Organization: "org"}) {
+		t.Error("expected a ThrottledError to be throttled")
+	}
+	if IsNotFound(errors.New("boom")) || IsNotFound(nil) {
+		t.Error("expected unclassified errors not to be not found")
+	}
+}
@@ -0,0 +1,98 @@
+package fake
+
+import (
+	"net/http"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+)
+
+// operation is an async operation; done is called when it succeeds.
+type operation struct {
+	id     string
+	polls  int
+	status azuredevops.OperationStatus
+	done   func()
+}
+
+// FailOperations makes the async operations started from now on fail.
+func (s *Server) FailOperations(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failOps = fail
+}
+
+// startOperation queues an async operation and returns its reference.
+func (c *Call) startOperation(done func()) Object {
+	op := &operation{
+		id:     c.newUUID(),
+		polls:  c.OperationPolls,
+		status: azuredevops.StatusQueued,
+		done:   done,
+	}
+	if c.failOps {
+		op.done = nil
+	}
+	c.ops[op.id] = op
+
+	// Complete the operation right away unless it must be polled.
+	if op.polls == 0 {
+		c.advance(op)
+	}
+
+	return Object{
+		"id":     op.id,
+		"status": string(op.status),
+		"url":    c.URL + "/" + c.Param("org") + "/_apis/operations/" + op.id,
+	}
+}
+
+func (s *Server) advance(op *operation) {
+	if op.status == azuredevops.StatusSucceeded || op.status == azuredevops.StatusFailed {
+		return
+	}
+	if op.polls > 0 {
+		op.polls--
+		op.status = azuredevops.StatusInProgress
+		return
+	}
+	if s.failOps && op.done == nil {
+		op.status = azuredevops.StatusFailed
+		return
+	}
+	op.status = azuredevops.StatusSucceeded
+	if op.done != nil {
+		op.done()
+	}
+}
+
+func (s *Server) registerCore() {
+	s.handle(http.MethodGet, "{org}/_apis/operations/{id}", func(c *Call) {
+		op, ok := c.ops[c.Param("id")]
+		if !ok {
+			c.NotFound("operation " + c.Param("id"))
+			return
+		}
+		c.advance(op)
+
+		res := Object{
+			"id":     op.id,
+			"status": string(op.status),
+		}
+		if op.status == azuredevops.StatusFailed {
+			res["resultMessage"] = "operation failed"
+		}
+		c.JSON(http.StatusOK, res)
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/connectionData", func(c *Call) {
+		c.JSON(http.StatusOK, Object{
+			"authenticatedUser": Object{
+				"id":                  "00000000-0000-0000-0000-000000000001",
+				"providerDisplayName": "Fake User",
+				"isActive":            true,
+			},
+			"instanceId":     "00000000-0000-0000-0000-000000000002",
+			"deploymentType": "hosted",
+		})
+	})
+}
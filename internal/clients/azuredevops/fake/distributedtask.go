@@ -0,0 +1,474 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AddPool stores an agent pool and returns it.
+func (s *Server) AddPool(org, name string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.table("pools", org).insert(Object{
+		"id":       s.nextInt(),
+		"name":     name,
+		"poolType": "automation",
+		"isHosted": false,
+		"size":     0,
+	})
+}
+
+// AddSecureFile uploads a secure file in the project and returns it.
+func (s *Server) AddSecureFile(org, project, name string, content []byte) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return public(s.addSecureFile(org, prj, name, content))
+}
+
+// SecureFile returns the secure file with the specified id or name and its content.
+func (s *Server) SecureFile(org, project, idOrName string) (Object, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil, nil
+	}
+	t := s.table("securefiles", org, String(prj["id"]))
+	_, sf := t.byID(idOrName)
+	if sf == nil {
+		_, sf = t.byField("name", idOrName)
+	}
+	if sf == nil {
+		return nil, nil
+	}
+	data, _ := sf["_content"].([]byte)
+	return public(sf), data
+}
+
+// VariableGroup returns the variable group with the specified id.
+func (s *Server) VariableGroup(org string, id int) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, vg := s.table("variablegroups", org).byID(String(id))
+	return vg
+}
+
+func (s *Server) registerDistributedTask() {
+	s.registerPools()
+	s.registerQueues()
+	s.registerVariableGroups()
+	s.registerEnvironments()
+	s.registerSecureFiles()
+}
+
+func (s *Server) registerPools() {
+	s.handle(http.MethodGet, "{org}/_apis/distributedtask/pools", func(c *Call) {
+		name, poolType := c.Query("poolName"), c.Query("poolType")
+		c.List(c.table("pools", c.Param("org")).list(func(el Object) bool {
+			return (len(name) == 0 || strings.EqualFold(String(el["name"]), name)) &&
+				(len(poolType) == 0 || strings.EqualFold(String(el["poolType"]), poolType))
+		}))
+	})
+}
+
+func (s *Server) registerQueues() {
+	queues := func(c *Call, prj Object) *table {
+		return c.table("queues", c.Param("org"), String(prj["id"]))
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/queues", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		var names []string
+		if val := c.Query("queueNames"); len(val) > 0 {
+			names = strings.Split(val, ",")
+		}
+		c.List(queues(c, prj).list(func(el Object) bool {
+			if len(names) == 0 {
+				return true
+			}
+			for _, name := range names {
+				if strings.EqualFold(String(el["name"]), name) {
+					return true
+				}
+			}
+			return false
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/queues/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, q := queues(c, prj).byID(c.Param("id"))
+		if q == nil {
+			c.NotFound("queue " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, q)
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/distributedtask/queues", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		q, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, el := queues(c, prj).byField("name", String(q["name"])); el != nil {
+			c.Error(http.StatusConflict, "TaskAgentQueueExistsException",
+				fmt.Sprintf("queue '%s' already exists", String(q["name"])))
+			return
+		}
+		ref, _ := q["pool"].(Object)
+		_, pool := c.table("pools", c.Param("org")).byID(String(ref["id"]))
+		if pool == nil {
+			c.Error(http.StatusBadRequest, "TaskAgentPoolNotFoundException",
+				fmt.Sprintf("pool '%s' not found", String(ref["id"])))
+			return
+		}
+		q["id"] = c.nextInt()
+		q["projectId"] = prj["id"]
+		q["pool"] = Object{"id": pool["id"], "name": pool["name"], "isHosted": pool["isHosted"]}
+		c.JSON(http.StatusOK, queues(c, prj).insert(q))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/distributedtask/queues/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		idx, q := queues(c, prj).byID(c.Param("id"))
+		if q == nil {
+			c.NotFound("queue " + c.Param("id"))
+			return
+		}
+		queues(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
+
+// Variable groups are stored by organization and shared
+// with the projects listed in their project references.
+func (s *Server) registerVariableGroups() {
+	groups := func(c *Call) *table {
+		return c.table("variablegroups", c.Param("org"))
+	}
+	sharedWith := func(vg, prj Object) bool {
+		refs, _ := vg["variableGroupProjectReferences"].([]any)
+		for _, el := range refs {
+			ref, _ := el.(Object)
+			pr, _ := ref["projectReference"].(Object)
+			if String(pr["id"]) == String(prj["id"]) || strings.EqualFold(String(pr["name"]), String(prj["name"])) {
+				return true
+			}
+		}
+		return false
+	}
+	get := func(c *Call, prj Object) (int, Object) {
+		idx, vg := groups(c).byID(c.Param("id"))
+		if vg == nil || !sharedWith(vg, prj) {
+			c.NotFound("variable group " + c.Param("id"))
+			return -1, nil
+		}
+		return idx, vg
+	}
+	save := func(c *Call, vg Object) bool {
+		if t := String(vg["type"]); len(t) > 0 && t != "Vsts" && t != "AzureKeyVault" {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException",
+				fmt.Sprintf("variable group type '%s' is not valid", t))
+			return false
+		}
+		// Key Vault groups link the vault secrets through an Azure
+		// Resource Manager service endpoint.
+		if String(vg["type"]) == "AzureKeyVault" {
+			data, _ := vg["providerData"].(Object)
+			if len(String(data["vault"])) == 0 {
+				c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "providerData.vault is required")
+				return false
+			}
+			if _, ep := c.table("endpoints", c.Param("org")).byID(String(data["serviceEndpointId"])); ep == nil {
+				c.NotFound("service endpoint " + String(data["serviceEndpointId"]))
+				return false
+			}
+			data["lastRefreshedOn"] = time.Now().UTC().Format(time.RFC3339)
+		}
+		// Secret values are never returned.
+		vars, _ := vg["variables"].(Object)
+		for _, el := range vars {
+			if v, ok := el.(Object); ok && v["isSecret"] == true {
+				v["value"] = nil
+			}
+		}
+		vg["modifiedOn"] = time.Now().UTC().Format(time.RFC3339)
+		return true
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/variablegroups", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		name := c.Query("groupName")
+		c.List(groups(c).list(func(el Object) bool {
+			return sharedWith(el, prj) && (len(name) == 0 || strings.EqualFold(String(el["name"]), name))
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/variablegroups/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		if _, vg := get(c, prj); vg != nil {
+			c.JSON(http.StatusOK, vg)
+		}
+	})
+
+	create := func(c *Call) {
+		vg, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, el := groups(c).byField("name", String(vg["name"])); el != nil {
+			c.Error(http.StatusConflict, "VariableGroupExistsException",
+				fmt.Sprintf("variable group '%s' already exists", String(vg["name"])))
+			return
+		}
+		if !save(c, vg) {
+			return
+		}
+		vg["id"] = c.nextInt()
+		vg["createdOn"] = vg["modifiedOn"]
+		c.JSON(http.StatusOK, groups(c).insert(vg))
+	}
+	s.handle(http.MethodPost, "{org}/_apis/distributedtask/variablegroups", create)
+	s.handle(http.MethodPost, "{org}/{project}/_apis/distributedtask/variablegroups", func(c *Call) {
+		if c.project() != nil {
+			create(c)
+		}
+	})
+
+	update := func(c *Call) {
+		_, vg := groups(c).byID(c.Param("id"))
+		if vg == nil {
+			c.NotFound("variable group " + c.Param("id"))
+			return
+		}
+		obj, ok := c.Object()
+		if !ok || !save(c, obj) {
+			return
+		}
+		obj["id"] = vg["id"]
+		obj["createdOn"] = vg["createdOn"]
+		c.JSON(http.StatusOK, merge(vg, obj))
+	}
+	s.handle(http.MethodPut, "{org}/_apis/distributedtask/variablegroups/{id}", update)
+	s.handle(http.MethodPut, "{org}/{project}/_apis/distributedtask/variablegroups/{id}", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			if _, vg := get(c, prj); vg != nil {
+				update(c)
+			}
+		}
+	})
+
+	s.handle(http.MethodDelete, "{org}/_apis/distributedtask/variablegroups/{id}", func(c *Call) {
+		if len(c.Query("projectIds")) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "projectIds is required")
+			return
+		}
+		idx, vg := groups(c).byID(c.Param("id"))
+		if vg == nil {
+			c.NotFound("variable group " + c.Param("id"))
+			return
+		}
+		groups(c).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func (s *Server) registerEnvironments() {
+	envs := func(c *Call, prj Object) *table {
+		return c.table("environments", c.Param("org"), String(prj["id"]))
+	}
+	get := func(c *Call) (Object, int, Object) {
+		prj := c.project()
+		if prj == nil {
+			return nil, -1, nil
+		}
+		idx, env := envs(c, prj).byID(c.Param("id"))
+		if env == nil {
+			c.NotFound("environment " + c.Param("id"))
+		}
+		return prj, idx, env
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/environments", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		name := c.Query("name")
+		c.List(envs(c, prj).list(func(el Object) bool {
+			return len(name) == 0 || strings.EqualFold(String(el["name"]), name)
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/environments/{id}", func(c *Call) {
+		if _, _, env := get(c); env != nil {
+			c.JSON(http.StatusOK, env)
+		}
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/distributedtask/environments", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		env, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, el := envs(c, prj).byField("name", String(env["name"])); el != nil {
+			c.Error(http.StatusConflict, "EnvironmentExistsException",
+				fmt.Sprintf("environment '%s' already exists", String(env["name"])))
+			return
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		env["id"] = c.nextInt()
+		env["createdOn"] = now
+		env["lastModifiedOn"] = now
+		env["project"] = Object{"id": prj["id"], "name": prj["name"]}
+		c.JSON(http.StatusOK, envs(c, prj).insert(env))
+	})
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/distributedtask/environments/{id}", func(c *Call) {
+		_, _, env := get(c)
+		if env == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		delete(obj, "id")
+		env["lastModifiedOn"] = time.Now().UTC().Format(time.RFC3339)
+		c.JSON(http.StatusOK, merge(env, obj))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/distributedtask/environments/{id}", func(c *Call) {
+		prj, idx, env := get(c)
+		if env == nil {
+			return
+		}
+		envs(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func (s *Server) addSecureFile(org string, prj Object, name string, content []byte) Object {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return s.table("securefiles", org, String(prj["id"])).insert(Object{
+		"id":         s.newUUID(),
+		"name":       name,
+		"createdOn":  now,
+		"modifiedOn": now,
+		"_content":   append([]byte(nil), content...),
+	})
+}
+
+func (s *Server) registerSecureFiles() {
+	files := func(c *Call, prj Object) *table {
+		return c.table("securefiles", c.Param("org"), String(prj["id"]))
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/securefiles", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.List(publicList(files(c, prj).list(nil)))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/distributedtask/securefiles/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, sf := files(c, prj).byID(c.Param("id"))
+		if sf == nil {
+			c.NotFound("secure file " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, public(sf))
+	})
+
+	// Upload: the request body is the file content.
+	s.handle(http.MethodPost, "{org}/{project}/_apis/distributedtask/securefiles", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		name := c.Query("name")
+		if len(name) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "name is required")
+			return
+		}
+		if _, el := files(c, prj).byField("name", name); el != nil {
+			c.Error(http.StatusConflict, "SecureFileExistsException",
+				fmt.Sprintf("secure file '%s' already exists", name))
+			return
+		}
+		c.JSON(http.StatusOK, public(c.addSecureFile(c.Param("org"), prj, name, c.body)))
+	})
+
+	// Update: only the name of a secure file can be changed.
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/distributedtask/securefiles/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		upd, ok := c.Object()
+		if !ok {
+			return
+		}
+		_, sf := files(c, prj).byID(c.Param("id"))
+		if sf == nil {
+			c.NotFound("secure file " + c.Param("id"))
+			return
+		}
+		if name := String(upd["name"]); len(name) > 0 && name != String(sf["name"]) {
+			if _, el := files(c, prj).byField("name", name); el != nil {
+				c.Error(http.StatusConflict, "SecureFileExistsException",
+					fmt.Sprintf("secure file '%s' already exists", name))
+				return
+			}
+			sf["name"] = name
+			sf["modifiedOn"] = time.Now().UTC().Format(time.RFC3339)
+		}
+		c.JSON(http.StatusOK, public(sf))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/distributedtask/securefiles/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		idx, sf := files(c, prj).byID(c.Param("id"))
+		if sf == nil {
+			c.NotFound("secure file " + c.Param("id"))
+			return
+		}
+		files(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,284 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ServiceEndpoint returns the service endpoint with the specified id, if any;
+// unlike the API responses, the secret authorization parameters are included.
+func (s *Server) ServiceEndpoint(org, id string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ep := s.table("endpoints", org).byID(id)
+	return ep
+}
+
+// AddServiceEndpoint stores a service endpoint shared with the project and returns it.
+func (s *Server) AddServiceEndpoint(org, project, name, epType string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return s.table("endpoints", org).insert(Object{
+		"id":       s.newUUID(),
+		"name":     name,
+		"type":     epType,
+		"isReady":  true,
+		"isShared": false,
+		"owner":    "library",
+		"data":     Object{},
+		"serviceEndpointProjectReferences": []any{
+			Object{
+				"name":             name,
+				"projectReference": Object{"id": prj["id"], "name": prj["name"]},
+			},
+		},
+	})
+}
+
+// secretParameters are the authorization parameters never returned.
+var secretParameters = []string{"serviceprincipalKey", "serviceAccountCertificate", "apitoken", "password"}
+
+// masked returns a copy of the endpoint without the secret authorization parameters.
+func masked(ep Object) Object {
+	auth, _ := ep["authorization"].(Object)
+	params, _ := auth["parameters"].(Object)
+	if params == nil {
+		return ep
+	}
+	hidden := merge(Object{}, params)
+	for _, key := range secretParameters {
+		if _, ok := hidden[key]; ok {
+			hidden[key] = nil
+		}
+	}
+	return merge(merge(Object{}, ep), Object{
+		"authorization": merge(merge(Object{}, auth), Object{"parameters": hidden}),
+	})
+}
+
+// federate sets the issuer and the subject identifier of the endpoints
+// using the workload identity federation scheme.
+func federate(org string, ep Object) {
+	auth, _ := ep["authorization"].(Object)
+	if String(auth["scheme"]) != "WorkloadIdentityFederation" {
+		return
+	}
+	params, _ := auth["parameters"].(Object)
+	if params == nil {
+		params = Object{}
+		auth["parameters"] = params
+	}
+	var project string
+	if refs, _ := ep["serviceEndpointProjectReferences"].([]any); len(refs) > 0 {
+		ref, _ := refs[0].(Object)
+		pr, _ := ref["projectReference"].(Object)
+		project = String(pr["name"])
+	}
+	params["workloadIdentityFederationIssuer"] = "https://vstoken.dev.azure.com/" + org
+	params["workloadIdentityFederationSubject"] = fmt.Sprintf("sc://%s/%s/%s", org, project, String(ep["name"]))
+}
+
+func maskedList(items []Object) []Object {
+	res := make([]Object, 0, len(items))
+	for _, el := range items {
+		res = append(res, masked(el))
+	}
+	return res
+}
+
+// Service endpoints are stored by organization and shared with
+// the projects listed in their project references.
+func (s *Server) registerEndpoints() {
+	endpoints := func(c *Call) *table {
+		return c.table("endpoints", c.Param("org"))
+	}
+	projectRefs := func(ep Object) []any {
+		refs, _ := ep["serviceEndpointProjectReferences"].([]any)
+		return refs
+	}
+	sharedWith := func(ep, prj Object) bool {
+		for _, el := range projectRefs(ep) {
+			ref, _ := el.(Object)
+			pr, _ := ref["projectReference"].(Object)
+			if String(pr["id"]) == String(prj["id"]) || strings.EqualFold(String(pr["name"]), String(prj["name"])) {
+				return true
+			}
+		}
+		return false
+	}
+	filter := func(c *Call, match func(Object) bool) []Object {
+		var names, ids []string
+		if val := c.Query("endpointNames"); len(val) > 0 {
+			names = strings.Split(val, ",")
+		}
+		if val := c.Query("endpointIds"); len(val) > 0 {
+			ids = strings.Split(val, ",")
+		}
+		epType := c.Query("type")
+		return endpoints(c).list(func(el Object) bool {
+			if !match(el) {
+				return false
+			}
+			if len(epType) > 0 && !strings.EqualFold(String(el["type"]), epType) {
+				return false
+			}
+			if len(names) > 0 && !containsFold(names, String(el["name"])) {
+				return false
+			}
+			if len(ids) > 0 && !containsFold(ids, String(el["id"])) {
+				return false
+			}
+			return true
+		})
+	}
+
+	s.handle(http.MethodGet, "{org}/_apis/serviceendpoint/endpoints", func(c *Call) {
+		c.List(maskedList(filter(c, func(Object) bool { return true })))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/serviceendpoint/endpoints", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		c.List(maskedList(filter(c, func(el Object) bool { return sharedWith(el, prj) })))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/serviceendpoint/endpoints/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, ep := endpoints(c).byID(c.Param("id"))
+		if ep == nil || !sharedWith(ep, prj) {
+			c.NotFound("service endpoint " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, masked(ep))
+	})
+
+	s.handle(http.MethodPost, "{org}/_apis/serviceendpoint/endpoints", func(c *Call) {
+		ep, ok := c.Object()
+		if !ok {
+			return
+		}
+		refs := projectRefs(ep)
+		if len(refs) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException",
+				"serviceEndpointProjectReferences is required")
+			return
+		}
+		for _, el := range refs {
+			ref, _ := el.(Object)
+			pr, _ := ref["projectReference"].(Object)
+			if c.findProject(c.Param("org"), String(pr["id"])) == nil {
+				c.NotFound("project " + String(pr["id"]))
+				return
+			}
+			if _, dup := endpoints(c).find(func(other Object) bool {
+				return strings.EqualFold(String(other["name"]), String(ep["name"])) &&
+					sharedWith(other, c.findProject(c.Param("org"), String(pr["id"])))
+			}); dup != nil {
+				c.Error(http.StatusConflict, "DuplicateServiceConnectionException",
+					fmt.Sprintf("service endpoint '%s' already exists", String(ep["name"])))
+				return
+			}
+		}
+
+		ep["id"] = c.newUUID()
+		ep["isReady"] = true
+		ep["isShared"] = len(refs) > 1
+		ep["owner"] = "library"
+		if _, ok := ep["data"]; !ok {
+			ep["data"] = Object{}
+		}
+		federate(c.Param("org"), ep)
+		c.JSON(http.StatusOK, masked(endpoints(c).insert(ep)))
+	})
+
+	s.handle(http.MethodPut, "{org}/_apis/serviceendpoint/endpoints/{id}", func(c *Call) {
+		_, ep := endpoints(c).byID(c.Param("id"))
+		if ep == nil {
+			c.NotFound("service endpoint " + c.Param("id"))
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		obj["id"] = ep["id"]
+		merge(ep, obj)
+		federate(c.Param("org"), ep)
+		c.JSON(http.StatusOK, masked(ep))
+	})
+
+	// Share the endpoint with other projects.
+	s.handle(http.MethodPatch, "{org}/_apis/serviceendpoint/endpoints/{id}", func(c *Call) {
+		_, ep := endpoints(c).byID(c.Param("id"))
+		if ep == nil {
+			c.NotFound("service endpoint " + c.Param("id"))
+			return
+		}
+		var refs []any
+		if !c.Decode(&refs) {
+			return
+		}
+		for _, el := range refs {
+			ref, _ := el.(Object)
+			pr, _ := ref["projectReference"].(Object)
+			prj := c.findProject(c.Param("org"), String(pr["id"]))
+			if prj == nil {
+				c.NotFound("project " + String(pr["id"]))
+				return
+			}
+			if !sharedWith(ep, prj) {
+				ep["serviceEndpointProjectReferences"] = append(projectRefs(ep), ref)
+			}
+		}
+		ep["isShared"] = len(projectRefs(ep)) > 1
+		c.JSON(http.StatusOK, masked(ep))
+	})
+
+	s.handle(http.MethodDelete, "{org}/_apis/serviceendpoint/endpoints/{id}", func(c *Call) {
+		ids := c.Query("projectIds")
+		if len(ids) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "projectIds is required")
+			return
+		}
+		idx, ep := endpoints(c).byID(c.Param("id"))
+		if ep == nil {
+			c.NotFound("service endpoint " + c.Param("id"))
+			return
+		}
+
+		// Remove the endpoint from the specified projects only.
+		keep := []any{}
+		for _, el := range projectRefs(ep) {
+			ref, _ := el.(Object)
+			pr, _ := ref["projectReference"].(Object)
+			if !containsFold(strings.Split(ids, ","), String(pr["id"])) {
+				keep = append(keep, el)
+			}
+		}
+		if len(keep) == 0 {
+			endpoints(c).remove(idx)
+		} else {
+			ep["serviceEndpointProjectReferences"] = keep
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func containsFold(list []string, val string) bool {
+	for _, el := range list {
+		if strings.EqualFold(strings.TrimSpace(el), val) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Feeds are stored by organization: the project scoped
+// feeds have a project reference.
+func (s *Server) registerFeeds() {
+	feeds := func(c *Call) *table {
+		return c.table("feeds", c.Param("org"))
+	}
+	// scope returns the project of the request (nil if organization
+	// scoped) and false if the project does not exist.
+	scope := func(c *Call) (Object, bool) {
+		if len(c.Param("project")) == 0 {
+			return nil, true
+		}
+		prj := c.project()
+		return prj, prj != nil
+	}
+	inScope := func(feed, prj Object) bool {
+		ref, _ := feed["project"].(Object)
+		if prj == nil {
+			return ref == nil
+		}
+		return ref != nil && String(ref["id"]) == String(prj["id"])
+	}
+	get := func(c *Call) (int, Object) {
+		prj, ok := scope(c)
+		if !ok {
+			return -1, nil
+		}
+		idx, feed := feeds(c).find(func(el Object) bool {
+			return inScope(el, prj) && (String(el["id"]) == c.Param("feed") ||
+				strings.EqualFold(String(el["name"]), c.Param("feed")))
+		})
+		if feed == nil {
+			c.Error(http.StatusNotFound, "FeedIdNotFoundException",
+				fmt.Sprintf("feed '%s' not found", c.Param("feed")))
+		}
+		return idx, feed
+	}
+
+	list := func(c *Call) {
+		prj, ok := scope(c)
+		if !ok {
+			return
+		}
+		c.List(publicList(feeds(c).list(func(el Object) bool { return inScope(el, prj) })))
+	}
+
+	create := func(c *Call) {
+		prj, ok := scope(c)
+		if !ok {
+			return
+		}
+		feed, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, dup := feeds(c).find(func(el Object) bool {
+			return inScope(el, prj) && strings.EqualFold(String(el["name"]), String(feed["name"]))
+		}); dup != nil {
+			c.Error(http.StatusConflict, "FeedNameAlreadyExistsException",
+				fmt.Sprintf("feed '%s' already exists", String(feed["name"])))
+			return
+		}
+		feed["id"] = c.newUUID()
+		feed["fullyQualifiedId"] = feed["id"]
+		feed["fullyQualifiedName"] = feed["name"]
+		feed["url"] = fmt.Sprintf("%s/%s/_apis/packaging/Feeds/%s", c.URL, c.Param("org"), feed["id"])
+		delete(feed, "project")
+		if prj != nil {
+			feed["project"] = Object{"id": prj["id"], "name": prj["name"], "visibility": prj["visibility"]}
+			feed["url"] = fmt.Sprintf("%s/%s/%s/_apis/packaging/Feeds/%s", c.URL, c.Param("org"), prj["id"], feed["id"])
+		}
+		feed["_permissions"] = []any{}
+		c.upstreamSources(feed)
+		c.JSON(http.StatusCreated, public(feeds(c).insert(feed)))
+	}
+
+	read := func(c *Call) {
+		if _, feed := get(c); feed != nil {
+			c.JSON(http.StatusOK, public(feed))
+		}
+	}
+
+	update := func(c *Call) {
+		_, feed := get(c)
+		if feed == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		obj["id"] = feed["id"]
+		c.JSON(http.StatusOK, public(c.upstreamSources(merge(feed, obj))))
+	}
+
+	remove := func(c *Call) {
+		idx, feed := get(c)
+		if feed == nil {
+			return
+		}
+		feeds(c).remove(idx)
+		c.Status(http.StatusNoContent)
+	}
+
+	permissions := func(c *Call) {
+		if _, feed := get(c); feed != nil {
+			perms, _ := feed["_permissions"].([]any)
+			c.JSON(http.StatusOK, Object{"count": len(perms), "value": perms})
+		}
+	}
+
+	// Sets the role of the identities: the 'none' role removes it.
+	setPermissions := func(c *Call) {
+		_, feed := get(c)
+		if feed == nil {
+			return
+		}
+		var upd []Object
+		if !c.Decode(&upd) {
+			return
+		}
+		perms, _ := feed["_permissions"].([]any)
+		for _, el := range upd {
+			keep := []any{}
+			for _, old := range perms {
+				if String(old.(Object)["identityDescriptor"]) != String(el["identityDescriptor"]) {
+					keep = append(keep, old)
+				}
+			}
+			if !strings.EqualFold(String(el["role"]), "none") {
+				keep = append(keep, el)
+			}
+			perms = keep
+		}
+		feed["_permissions"] = perms
+		c.JSON(http.StatusOK, Object{"count": len(perms), "value": perms})
+	}
+
+	for _, prefix := range []string{"{org}", "{org}/{project}"} {
+		s.handle(http.MethodGet, prefix+"/_apis/packaging/feeds", list)
+		s.handle(http.MethodPost, prefix+"/_apis/packaging/feeds", create)
+		s.handle(http.MethodGet, prefix+"/_apis/packaging/feeds/{feed}", read)
+		s.handle(http.MethodPatch, prefix+"/_apis/packaging/feeds/{feed}", update)
+		s.handle(http.MethodDelete, prefix+"/_apis/packaging/feeds/{feed}", remove)
+		s.handle(http.MethodGet, prefix+"/_apis/packaging/feeds/{feed}/permissions", permissions)
+		s.handle(http.MethodPatch, prefix+"/_apis/packaging/feeds/{feed}/permissions", setPermissions)
+	}
+}
+
+// upstreamSources fills the server generated fields of the feed upstream sources.
+func (s *Server) upstreamSources(feed Object) Object {
+	all, _ := feed["upstreamSources"].([]any)
+	for _, el := range all {
+		src, ok := el.(Object)
+		if !ok {
+			continue
+		}
+		if len(String(src["id"])) == 0 {
+			src["id"] = s.newUUID()
+		}
+		if len(String(src["displayLocation"])) == 0 {
+			src["displayLocation"] = src["location"]
+		}
+		src["status"] = "ok"
+	}
+	return feed
+}
@@ -0,0 +1,731 @@
+package fake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AddRepository stores an empty repository in the project and returns it.
+func (s *Server) AddRepository(org, project, name string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return s.addRepository(org, prj, Object{"name": name})
+}
+
+// Repository returns the repository with the specified id or name, if any.
+func (s *Server) Repository(org, project, idOrName string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return s.findRepository(org, prj, idOrName)
+}
+
+// Refs returns the refs of the repository mapped to their commit ids.
+func (s *Server) Refs(org, project, repository string) map[string]string {
+	repo := s.Repository(org, project, repository)
+	if repo == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := map[string]string{}
+	for k, v := range repo["_refs"].(map[string]string) {
+		res[k] = v
+	}
+	return res
+}
+
+// The steps of the import requests.
+var importSteps = []any{
+	"Processing request", "Analyzing repository objects", "Storing objects", "Storing index file", "Updating references",
+}
+
+// CompleteImport completes the last import request of the repository
+// importing a 'main' branch with a README, or fails it with the
+// specified error message.
+func (s *Server) CompleteImport(org, project, repository, errorMessage string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return false
+	}
+	repo := s.findRepository(org, prj, repository)
+	if repo == nil {
+		return false
+	}
+	items := s.table("importrequests", org, String(repo["id"])).list(nil)
+	if len(items) == 0 {
+		return false
+	}
+	req := items[len(items)-1]
+	detail := req["detailedStatus"].(Object)
+	if len(errorMessage) > 0 {
+		req["status"] = "failed"
+		detail["errorMessage"] = errorMessage
+	} else {
+		req["status"] = "completed"
+		detail["currentStep"] = len(importSteps)
+		repo["_refs"].(map[string]string)["refs/heads/main"] = fmt.Sprintf("%040d", s.nextInt())
+		repo["_files"].(map[string]map[string][]byte)["refs/heads/main"] = map[string][]byte{
+			"/README.md": []byte("# imported"),
+		}
+		repo["defaultBranch"] = "refs/heads/main"
+	}
+
+	params, _ := req["parameters"].(Object)
+	if del, _ := params["deleteServiceEndpointAfterImportIsDone"].(bool); del {
+		if idx, ep := s.table("endpoints", org).byID(String(params["serviceEndpointId"])); ep != nil {
+			s.table("endpoints", org).remove(idx)
+		}
+	}
+	return true
+}
+
+// The steps of the fork sync requests.
+var forkSyncSteps = []any{"Processing request", "Copying refs", "Updating references"}
+
+// CompleteForkSync completes the last fork sync request of the fork
+// copying the refs of the parent repository, or fails it with the
+// specified error message.
+func (s *Server) CompleteForkSync(org, project, repository, errorMessage string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return false
+	}
+	repo := s.findRepository(org, prj, repository)
+	if repo == nil {
+		return false
+	}
+	items := s.table("forksyncrequests", org, String(repo["id"])).list(nil)
+	if len(items) == 0 {
+		return false
+	}
+	req := items[len(items)-1]
+	detail := req["detailedStatus"].(Object)
+	if len(errorMessage) > 0 {
+		req["status"] = "failed"
+		detail["errorMessage"] = errorMessage
+		return true
+	}
+
+	src := req["source"].(Object)
+	var parent Object
+	if parentPrj := s.findProject(org, String(src["projectId"])); parentPrj != nil {
+		parent = s.findRepository(org, parentPrj, String(src["repositoryId"]))
+	}
+	if parent == nil {
+		return false
+	}
+	sourceRef := String(req["_sourceRef"])
+	refs := repo["_refs"].(map[string]string)
+	files := repo["_files"].(map[string]map[string][]byte)
+	for name, id := range parent["_refs"].(map[string]string) {
+		if len(sourceRef) > 0 && name != sourceRef {
+			continue
+		}
+		refs[name] = id
+		tree := map[string][]byte{}
+		for p, content := range parent["_files"].(map[string]map[string][]byte)[name] {
+			tree[p] = content
+		}
+		files[name] = tree
+	}
+	if _, ok := refs[String(parent["defaultBranch"])]; ok {
+		repo["defaultBranch"] = parent["defaultBranch"]
+	} else if len(sourceRef) > 0 {
+		repo["defaultBranch"] = sourceRef
+	}
+	req["status"] = "completed"
+	detail["currentStep"] = len(forkSyncSteps)
+	return true
+}
+
+func (s *Server) addRepository(org string, prj, repo Object) Object {
+	id := s.newUUID()
+	name := String(repo["name"])
+	repo["id"] = id
+	repo["project"] = Object{
+		"id":         prj["id"],
+		"name":       prj["name"],
+		"state":      prj["state"],
+		"visibility": prj["visibility"],
+	}
+	repo["url"] = fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s", s.URL, org, prj["id"], id)
+	repo["remoteUrl"] = fmt.Sprintf("%s/%s/%s/_git/%s", s.URL, org, prj["name"], name)
+	repo["sshUrl"] = fmt.Sprintf("git@ssh.dev.azure.com:v3/%s/%s/%s", org, prj["name"], name)
+	repo["isDisabled"] = false
+	repo["isFork"] = false
+	repo["_refs"] = map[string]string{}
+	// The files of the branches by ref name and path.
+	repo["_files"] = map[string]map[string][]byte{}
+	return s.table("repositories", org, String(prj["id"])).insert(repo)
+}
+
+func (s *Server) findRepository(org string, prj Object, idOrName string) Object {
+	t := s.table("repositories", org, String(prj["id"]))
+	if _, repo := t.byID(idOrName); repo != nil {
+		return repo
+	}
+	_, repo := t.byField("name", idOrName)
+	return repo
+}
+
+// repository resolves the '{repository}' segment writing a 404 if not found.
+func (c *Call) repository(prj Object) Object {
+	repo := c.findRepository(c.Param("org"), prj, c.Param("repository"))
+	if repo == nil {
+		c.NotFound("repository " + c.Param("repository"))
+	}
+	return repo
+}
+
+// public returns a copy of the object without the internal fields.
+func public(obj Object) Object {
+	res := Object{}
+	for k, v := range obj {
+		if !strings.HasPrefix(k, "_") {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+func publicList(items []Object) []Object {
+	res := make([]Object, 0, len(items))
+	for _, el := range items {
+		res = append(res, public(el))
+	}
+	return res
+}
+
+func (s *Server) registerGit() {
+	repos := func(c *Call, prj Object) *table {
+		return c.table("repositories", c.Param("org"), String(prj["id"]))
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.List(publicList(repos(c, prj).list(nil)))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		if repo := c.repository(prj); repo != nil {
+			c.JSON(http.StatusOK, public(repo))
+		}
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/git/repositories", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		name := String(obj["name"])
+		if c.findRepository(c.Param("org"), prj, name) != nil {
+			c.Error(http.StatusConflict, "GitRepositoryNameAlreadyExistsException",
+				"repository '"+name+"' already exists")
+			return
+		}
+		// The soft-deleted repositories hold their names until destroyed.
+		if _, del := c.table("recyclebin", c.Param("org"), String(prj["id"])).byField("name", name); del != nil {
+			c.Error(http.StatusConflict, "GitRepositoryNameAlreadyExistsException",
+				"repository '"+name+"' already exists in the recycle bin")
+			return
+		}
+		ref, isFork := obj["parentRepository"].(Object)
+		if !isFork {
+			c.JSON(http.StatusCreated, public(c.addRepository(c.Param("org"), prj, Object{"name": name})))
+			return
+		}
+
+		// The parent repository can be in any project of the organization.
+		parentPrj := prj
+		if p, _ := ref["project"].(Object); len(String(p["id"])) > 0 {
+			parentPrj = c.findProject(c.Param("org"), String(p["id"]))
+		}
+		var parent Object
+		if parentPrj != nil {
+			parent = c.findRepository(c.Param("org"), parentPrj, String(ref["id"]))
+		}
+		if parent == nil {
+			c.Error(http.StatusNotFound, "GitRepositoryNotFoundException",
+				fmt.Sprintf("parent repository '%s' not found", String(ref["id"])))
+			return
+		}
+		sourceRef := c.Query("sourceRef")
+		if _, ok := parent["_refs"].(map[string]string)[sourceRef]; len(sourceRef) > 0 && !ok {
+			c.Error(http.StatusBadRequest, "GitRefNotFoundException",
+				fmt.Sprintf("ref '%s' not found in the parent repository", sourceRef))
+			return
+		}
+
+		repo := c.addRepository(c.Param("org"), prj, Object{"name": name})
+		repo["isFork"] = true
+		repo["parentRepository"] = Object{
+			"id":        parent["id"],
+			"name":      parent["name"],
+			"project":   parent["project"],
+			"isFork":    parent["isFork"],
+			"url":       parent["url"],
+			"remoteUrl": parent["remoteUrl"],
+			"sshUrl":    parent["sshUrl"],
+		}
+		// The refs are copied by the fork sync request: see CompleteForkSync.
+		c.table("forksyncrequests", c.Param("org"), String(repo["id"])).insert(Object{
+			"operationId": c.nextInt(),
+			"source": Object{
+				"projectId":    parentPrj["id"],
+				"repositoryId": parent["id"],
+			},
+			"status":         "inProgress",
+			"detailedStatus": Object{"allSteps": forkSyncSteps, "currentStep": 1},
+			"_sourceRef":     sourceRef,
+		})
+		c.JSON(http.StatusCreated, public(repo))
+	})
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/git/repositories/{repository}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		repo := c.repository(prj)
+		if repo == nil {
+			return
+		}
+		upd, ok := c.Object()
+		if !ok {
+			return
+		}
+		if val, ok := upd["isDisabled"].(bool); ok {
+			if len(upd) > 1 {
+				c.Error(http.StatusBadRequest, "InvalidArgumentValueException",
+					"isDisabled cannot be updated with other properties")
+				return
+			}
+			repo["isDisabled"] = val
+			c.JSON(http.StatusOK, public(repo))
+			return
+		}
+		if disabled, _ := repo["isDisabled"].(bool); disabled {
+			c.Error(http.StatusForbidden, "GitRepositoryDisabledException",
+				fmt.Sprintf("repository '%s' is disabled", String(repo["name"])))
+			return
+		}
+		if branch, ok := upd["defaultBranch"]; ok {
+			if _, ok := repo["_refs"].(map[string]string)[String(branch)]; !ok {
+				c.Error(http.StatusBadRequest, "GitRefNotFoundException",
+					fmt.Sprintf("ref '%s' not found", String(branch)))
+				return
+			}
+			repo["defaultBranch"] = branch
+		}
+		if val, ok := upd["name"]; ok && String(val) != String(repo["name"]) {
+			name := String(val)
+			if other := c.findRepository(c.Param("org"), prj, name); other != nil && String(other["id"]) != String(repo["id"]) {
+				c.Error(http.StatusConflict, "GitRepositoryNameAlreadyExistsException",
+					"repository '"+name+"' already exists")
+				return
+			}
+			repo["name"] = name
+			repo["remoteUrl"] = fmt.Sprintf("%s/%s/%s/_git/%s", c.URL, c.Param("org"), prj["name"], name)
+			repo["sshUrl"] = fmt.Sprintf("git@ssh.dev.azure.com:v3/%s/%s/%s", c.Param("org"), prj["name"], name)
+		}
+		c.JSON(http.StatusOK, public(repo))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/git/repositories/{repository}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		idx, repo := repos(c, prj).byID(c.Param("repository"))
+		if repo == nil {
+			c.NotFound("repository " + c.Param("repository"))
+			return
+		}
+		repos(c, prj).remove(idx)
+		repo["_deletedDate"] = time.Now().UTC().Format(time.RFC3339)
+		c.table("recyclebin", c.Param("org"), String(prj["id"])).insert(repo)
+		c.Status(http.StatusNoContent)
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/recycleBin/repositories", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		res := []Object{}
+		for _, repo := range c.table("recyclebin", c.Param("org"), String(prj["id"])).list(nil) {
+			res = append(res, Object{
+				"id":          repo["id"],
+				"name":        repo["name"],
+				"project":     repo["project"],
+				"deletedDate": repo["_deletedDate"],
+			})
+		}
+		c.List(res)
+	})
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/git/recycleBin/repositories/{repository}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		if deleted, ok := obj["deleted"].(bool); !ok || deleted {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "only 'deleted: false' is supported")
+			return
+		}
+		bin := c.table("recyclebin", c.Param("org"), String(prj["id"]))
+		idx, repo := bin.byID(c.Param("repository"))
+		if repo == nil {
+			c.NotFound("repository " + c.Param("repository"))
+			return
+		}
+		if name := String(repo["name"]); c.findRepository(c.Param("org"), prj, name) != nil {
+			c.Error(http.StatusConflict, "GitRepositoryNameAlreadyExistsException",
+				"repository '"+name+"' already exists")
+			return
+		}
+		bin.remove(idx)
+		delete(repo, "_deletedDate")
+		c.JSON(http.StatusOK, public(repos(c, prj).insert(repo)))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/git/recycleBin/repositories/{repository}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		bin := c.table("recyclebin", c.Param("org"), String(prj["id"]))
+		idx, repo := bin.byID(c.Param("repository"))
+		if repo == nil {
+			c.NotFound("repository " + c.Param("repository"))
+			return
+		}
+		bin.remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/git/repositories/{repository}/pushes", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		repo := c.repository(prj)
+		if repo == nil {
+			return
+		}
+		push, ok := c.Object()
+		if !ok {
+			return
+		}
+
+		refs := repo["_refs"].(map[string]string)
+		updates, _ := push["refUpdates"].([]any)
+		for _, el := range updates {
+			upd, _ := el.(Object)
+			name := String(upd["name"])
+			if old := String(upd["oldObjectId"]); refs[name] != "" && old != refs[name] {
+				c.Error(http.StatusConflict, "GitReferenceStaleException",
+					fmt.Sprintf("ref '%s' has been updated by another client", name))
+				return
+			}
+		}
+
+		files := repo["_files"].(map[string]map[string][]byte)
+		commits, _ := push["commits"].([]any)
+		for _, el := range updates {
+			upd, _ := el.(Object)
+			name := String(upd["name"])
+			tree := map[string][]byte{}
+			for k, v := range files[name] {
+				tree[k] = v
+			}
+			for _, cm := range commits {
+				commit, _ := cm.(Object)
+				if !applyChanges(c, tree, commit) {
+					return
+				}
+			}
+			files[name] = tree
+			refs[name] = fmt.Sprintf("%040d", c.nextInt())
+			upd["newObjectId"] = refs[name]
+			if _, ok := repo["defaultBranch"]; !ok {
+				repo["defaultBranch"] = name
+			}
+		}
+
+		push["pushId"] = c.nextInt()
+		push["repository"] = public(repo)
+		c.JSON(http.StatusCreated, push)
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/items", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		repo := c.repository(prj)
+		if repo == nil {
+			return
+		}
+		ref := String(repo["defaultBranch"])
+		if val := c.Query("versionDescriptor.version"); len(val) > 0 {
+			ref = "refs/heads/" + val
+		}
+		tree, ok := repo["_files"].(map[string]map[string][]byte)[ref]
+		if !ok {
+			c.Error(http.StatusNotFound, "GitItemNotFoundException", fmt.Sprintf("branch '%s' not found", ref))
+			return
+		}
+
+		if p := c.Query("path"); len(p) > 0 {
+			data, ok := tree[p]
+			if !ok {
+				c.Error(http.StatusNotFound, "GitItemNotFoundException", fmt.Sprintf("item '%s' not found", p))
+				return
+			}
+			c.w.Header().Set("Content-Type", "application/octet-stream")
+			c.w.WriteHeader(http.StatusOK)
+			c.w.Write(data)
+			return
+		}
+
+		// The folders are listed with the files.
+		scope := "/" + strings.Trim(c.Query("scopePath"), "/")
+		items := []Object{{"path": scope, "isFolder": true, "gitObjectType": "tree"}}
+		folders := map[string]bool{scope: true}
+		paths := make([]string, 0, len(tree))
+		for p := range tree {
+			if scope == "/" || strings.HasPrefix(p, scope+"/") {
+				paths = append(paths, p)
+			}
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			for dir := path.Dir(p); !folders[dir]; dir = path.Dir(dir) {
+				folders[dir] = true
+				items = append(items, Object{"path": dir, "isFolder": true, "gitObjectType": "tree"})
+			}
+			items = append(items, Object{"path": p, "gitObjectType": "blob", "objectId": c.newUUID()})
+		}
+		c.List(items)
+	})
+
+	s.registerPullRequests()
+	s.registerImportRequests()
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/forkSyncRequests", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		if repo := c.repository(prj); repo != nil {
+			c.List(publicList(c.table("forksyncrequests", c.Param("org"), String(repo["id"])).list(nil)))
+		}
+	})
+}
+
+// applyChanges applies the changes of a pushed commit to the files
+// of a branch, writing a 400 on the invalid changes.
+func applyChanges(c *Call, tree map[string][]byte, commit Object) bool {
+	changes, _ := commit["changes"].([]any)
+	for _, el := range changes {
+		change, _ := el.(Object)
+		item, _ := change["item"].(Object)
+		p := "/" + strings.TrimPrefix(String(item["path"]), "/")
+
+		switch String(change["changeType"]) {
+		case "add", "edit":
+			content, _ := change["newContent"].(Object)
+			data := []byte(String(content["content"]))
+			if String(content["contentType"]) == "base64Encoded" {
+				var err error
+				if data, err = base64.StdEncoding.DecodeString(String(content["content"])); err != nil {
+					c.Error(http.StatusBadRequest, "InvalidArgumentValueException", err.Error())
+					return false
+				}
+			}
+			if _, ok := tree[p]; ok && String(change["changeType"]) == "add" {
+				c.Error(http.StatusConflict, "GitItemAlreadyExistsException", fmt.Sprintf("item '%s' already exists", p))
+				return false
+			}
+			tree[p] = data
+		case "delete":
+			delete(tree, p)
+		}
+	}
+	return true
+}
+
+func (s *Server) registerPullRequests() {
+	prs := func(c *Call, repo Object) *table {
+		return c.table("pullrequests", c.Param("org"), String(repo["id"]))
+	}
+	resolve := func(c *Call) Object {
+		prj := c.project()
+		if prj == nil {
+			return nil
+		}
+		return c.repository(prj)
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/pullrequests", func(c *Call) {
+		if repo := resolve(c); repo != nil {
+			c.List(prs(c, repo).list(nil))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/pullrequests/{id}", func(c *Call) {
+		repo := resolve(c)
+		if repo == nil {
+			return
+		}
+		_, pr := prs(c, repo).byField("pullRequestId", c.Param("id"))
+		if pr == nil {
+			c.NotFound("pull request " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, pr)
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/git/repositories/{repository}/pullrequests", func(c *Call) {
+		repo := resolve(c)
+		if repo == nil {
+			return
+		}
+		pr, ok := c.Object()
+		if !ok {
+			return
+		}
+		refs := repo["_refs"].(map[string]string)
+		for _, k := range []string{"sourceRefName", "targetRefName"} {
+			if _, ok := refs[String(pr[k])]; !ok {
+				c.Error(http.StatusNotFound, "GitRefNotFoundException",
+					fmt.Sprintf("ref '%s' not found", String(pr[k])))
+				return
+			}
+		}
+		pr["pullRequestId"] = c.nextInt()
+		pr["status"] = "active"
+		pr["mergeStatus"] = "succeeded"
+		c.JSON(http.StatusCreated, prs(c, repo).insert(pr))
+	})
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/git/repositories/{repository}/pullrequests/{id}", func(c *Call) {
+		repo := resolve(c)
+		if repo == nil {
+			return
+		}
+		_, pr := prs(c, repo).byField("pullRequestId", c.Param("id"))
+		if pr == nil {
+			c.NotFound("pull request " + c.Param("id"))
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, merge(pr, obj))
+	})
+}
+
+// Import requests are stored by repository: the imports stay in
+// progress until completed by CompleteImport.
+func (s *Server) registerImportRequests() {
+	imports := func(c *Call, repo Object) *table {
+		return c.table("importrequests", c.Param("org"), String(repo["id"]))
+	}
+	resolve := func(c *Call) Object {
+		prj := c.project()
+		if prj == nil {
+			return nil
+		}
+		return c.repository(prj)
+	}
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/git/repositories/{repository}/importRequests", func(c *Call) {
+		repo := resolve(c)
+		if repo == nil {
+			return
+		}
+		req, ok := c.Object()
+		if !ok {
+			return
+		}
+		if len(repo["_refs"].(map[string]string)) > 0 {
+			c.Error(http.StatusConflict, "GitImportForbiddenOnNonEmptyRepositoryException",
+				fmt.Sprintf("repository '%s' is not empty", String(repo["name"])))
+			return
+		}
+		params, _ := req["parameters"].(Object)
+		src, _ := params["gitSource"].(Object)
+		if len(String(src["url"])) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "gitSource.url is required")
+			return
+		}
+		if id := String(params["serviceEndpointId"]); len(id) > 0 {
+			if _, ep := c.table("endpoints", c.Param("org")).byID(id); ep == nil {
+				c.Error(http.StatusBadRequest, "ServiceEndpointNotFoundException",
+					fmt.Sprintf("service endpoint '%s' not found", id))
+				return
+			}
+		}
+
+		id := c.nextInt()
+		req["importRequestId"] = id
+		req["status"] = "inProgress"
+		req["detailedStatus"] = Object{"allSteps": importSteps, "currentStep": 1}
+		req["repository"] = public(repo)
+		req["url"] = fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/importRequests/%d",
+			c.URL, c.Param("org"), c.Param("project"), String(repo["id"]), id)
+		c.JSON(http.StatusCreated, imports(c, repo).insert(req))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/importRequests", func(c *Call) {
+		if repo := resolve(c); repo != nil {
+			c.List(imports(c, repo).list(nil))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/git/repositories/{repository}/importRequests/{id}", func(c *Call) {
+		repo := resolve(c)
+		if repo == nil {
+			return
+		}
+		_, req := imports(c, repo).byField("importRequestId", c.Param("id"))
+		if req == nil {
+			c.NotFound("import request " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+}
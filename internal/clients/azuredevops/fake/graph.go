@@ -0,0 +1,324 @@
+package fake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AddUser stores an AAD user and returns it.
+func (s *Server) AddUser(org, principalName string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(org, principalName, "")
+}
+
+// AddGroup stores an organization level group and returns it.
+func (s *Server) AddGroup(org, displayName string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addGroup(org, nil, displayName, "")
+}
+
+// IsMember returns true if the subject is a direct member of the container.
+func (s *Server) IsMember(org, subjectDescriptor, containerDescriptor string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, el := s.table("memberships", org).find(func(el Object) bool {
+		return el["memberDescriptor"] == subjectDescriptor && el["containerDescriptor"] == containerDescriptor
+	})
+	return el != nil
+}
+
+func descriptor(prefix, val string) string {
+	return prefix + "." + base64.RawURLEncoding.EncodeToString([]byte(val))
+}
+
+func (s *Server) addUser(org, principalName, originId string) Object {
+	if len(originId) == 0 {
+		originId = s.newUUID()
+	}
+	if len(principalName) == 0 {
+		principalName = originId + "@fake.onmicrosoft.com"
+	}
+	desc := descriptor("aad", originId)
+	return s.table("subjects", org).insert(Object{
+		"subjectKind":   "user",
+		"domain":        "fake.onmicrosoft.com",
+		"principalName": principalName,
+		"mailAddress":   principalName,
+		"origin":        "aad",
+		"originId":      originId,
+		"displayName":   strings.Split(principalName, "@")[0],
+		"descriptor":    desc,
+		"url":           fmt.Sprintf("%s/%s/_apis/graph/users/%s", s.URL, org, desc),
+	})
+}
+
+func (s *Server) addGroup(org string, prj Object, displayName, description string) Object {
+	id := s.newUUID()
+	domain := "vstfs:///Framework/IdentityDomain/" + org
+	principalName := fmt.Sprintf("[%s]\\%s", org, displayName)
+	if prj != nil {
+		domain = "vstfs:///Classification/TeamProject/" + String(prj["id"])
+		principalName = fmt.Sprintf("[%s]\\%s", String(prj["name"]), displayName)
+
+		s.table("identities", org).insert(Object{
+			"id":                  id,
+			"descriptor":          "Microsoft.TeamFoundation.Identity;" + id,
+			"subjectDescriptor":   descriptor("vssgp", id),
+			"providerDisplayName": principalName,
+			"isActive":            true,
+		})
+	}
+	desc := descriptor("vssgp", id)
+	return s.table("subjects", org).insert(Object{
+		"subjectKind":   "group",
+		"description":   description,
+		"domain":        domain,
+		"principalName": principalName,
+		"origin":        "vsts",
+		"originId":      id,
+		"displayName":   displayName,
+		"descriptor":    desc,
+		"url":           fmt.Sprintf("%s/%s/_apis/graph/groups/%s", s.URL, org, desc),
+	})
+}
+
+// addTeamGroup stores the group subject backing a project team
+// (teams are security groups whose origin id is the team id).
+func (s *Server) addTeamGroup(org string, prj, team Object) Object {
+	id := String(team["id"])
+	desc := descriptor("vssgp", id)
+	return s.table("subjects", org).insert(Object{
+		"subjectKind":   "group",
+		"description":   team["description"],
+		"domain":        "vstfs:///Classification/TeamProject/" + String(prj["id"]),
+		"principalName": fmt.Sprintf("[%s]\\%s", String(prj["name"]), String(team["name"])),
+		"origin":        "vsts",
+		"originId":      id,
+		"displayName":   team["name"],
+		"descriptor":    desc,
+		"url":           fmt.Sprintf("%s/%s/_apis/graph/groups/%s", s.URL, org, desc),
+	})
+}
+
+// removeSubject removes the subject with the specified origin id, if any.
+func (s *Server) removeSubject(org, originId string) {
+	t := s.table("subjects", org)
+	if idx, el := t.byField("originId", originId); el != nil {
+		t.remove(idx)
+	}
+}
+
+// addBuildService stores the build service identity of the project.
+func (s *Server) addBuildService(org string, prj Object) {
+	id := s.newUUID()
+	s.table("identities", org).insert(Object{
+		"id":                  id,
+		"descriptor":          "Microsoft.TeamFoundation.ServiceIdentity;" + id,
+		"subjectDescriptor":   descriptor("svc", id),
+		"providerDisplayName": prj["id"],
+		"isActive":            true,
+	})
+}
+
+func (s *Server) registerGraph() {
+	subjects := func(c *Call) *table {
+		return c.table("subjects", c.Param("org"))
+	}
+	memberships := func(c *Call) *table {
+		return c.table("memberships", c.Param("org"))
+	}
+	ofKind := func(kind string) func(Object) bool {
+		return func(el Object) bool { return el["subjectKind"] == kind }
+	}
+	addMemberships := func(c *Call, member Object) bool {
+		for _, el := range strings.Split(c.Query("groupDescriptors"), ",") {
+			if len(el) == 0 {
+				continue
+			}
+			if _, grp := subjects(c).byField("descriptor", el); grp == nil {
+				c.NotFound("group " + el)
+				return false
+			}
+			memberships(c).insert(Object{
+				"memberDescriptor":    member["descriptor"],
+				"containerDescriptor": el,
+			})
+		}
+		return true
+	}
+	get := func(kind string) HandlerFunc {
+		return func(c *Call) {
+			_, el := subjects(c).byField("descriptor", c.Param("descriptor"))
+			if el == nil || el["subjectKind"] != kind {
+				c.NotFound(kind + " " + c.Param("descriptor"))
+				return
+			}
+			c.JSON(http.StatusOK, el)
+		}
+	}
+	remove := func(kind string) HandlerFunc {
+		return func(c *Call) {
+			idx, el := subjects(c).byField("descriptor", c.Param("descriptor"))
+			if el == nil || el["subjectKind"] != kind {
+				c.NotFound(kind + " " + c.Param("descriptor"))
+				return
+			}
+			subjects(c).remove(idx)
+			for {
+				idx, _ := memberships(c).find(func(m Object) bool {
+					return m["memberDescriptor"] == el["descriptor"] || m["containerDescriptor"] == el["descriptor"]
+				})
+				if idx < 0 {
+					break
+				}
+				memberships(c).remove(idx)
+			}
+			c.Status(http.StatusNoContent)
+		}
+	}
+
+	s.handle(http.MethodGet, "{org}/_apis/graph/users", func(c *Call) {
+		c.List(subjects(c).list(ofKind("user")))
+	})
+	s.handle(http.MethodGet, "{org}/_apis/graph/users/{descriptor}", get("user"))
+	s.handle(http.MethodDelete, "{org}/_apis/graph/users/{descriptor}", remove("user"))
+	s.handle(http.MethodPost, "{org}/_apis/graph/users", func(c *Call) {
+		var body struct {
+			PrincipalName string `json:"principalName"`
+			OriginId      string `json:"originId"`
+		}
+		if !c.Decode(&body) {
+			return
+		}
+		if len(body.PrincipalName) == 0 && len(body.OriginId) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "principalName or originId is required")
+			return
+		}
+		_, usr := subjects(c).find(func(el Object) bool {
+			return el["subjectKind"] == "user" &&
+				((len(body.PrincipalName) > 0 && strings.EqualFold(String(el["principalName"]), body.PrincipalName)) ||
+					(len(body.OriginId) > 0 && el["originId"] == body.OriginId))
+		})
+		if usr == nil {
+			usr = c.addUser(c.Param("org"), body.PrincipalName, body.OriginId)
+		}
+		if addMemberships(c, usr) {
+			c.JSON(http.StatusCreated, usr)
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/graph/groups", func(c *Call) {
+		c.List(subjects(c).list(ofKind("group")))
+	})
+	s.handle(http.MethodGet, "{org}/_apis/graph/groups/{descriptor}", get("group"))
+	s.handle(http.MethodDelete, "{org}/_apis/graph/groups/{descriptor}", remove("group"))
+	s.handle(http.MethodPost, "{org}/_apis/graph/groups", func(c *Call) {
+		var body struct {
+			DisplayName string `json:"displayName"`
+			Description string `json:"description"`
+			OriginId    string `json:"originId"`
+		}
+		if !c.Decode(&body) {
+			return
+		}
+
+		var prj Object
+		if scope := c.Query("scopeDescriptor"); len(scope) > 0 {
+			id, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(scope, "scp."))
+			if err == nil {
+				_, prj = c.table("projects", c.Param("org")).byID(string(id))
+			}
+			if prj == nil {
+				c.NotFound("scope " + scope)
+				return
+			}
+		}
+
+		var grp Object
+		if len(body.OriginId) > 0 {
+			_, grp = subjects(c).byField("originId", body.OriginId)
+			if grp == nil {
+				c.NotFound("group " + body.OriginId)
+				return
+			}
+		} else {
+			_, grp = subjects(c).find(func(el Object) bool {
+				return el["subjectKind"] == "group" && strings.EqualFold(String(el["displayName"]), body.DisplayName) &&
+					(prj == nil || strings.HasSuffix(String(el["domain"]), String(prj["id"])))
+			})
+			if grp != nil {
+				c.Error(http.StatusConflict, "GroupAlreadyExistsException",
+					fmt.Sprintf("group '%s' already exists", body.DisplayName))
+				return
+			}
+			grp = c.addGroup(c.Param("org"), prj, body.DisplayName, body.Description)
+		}
+		if addMemberships(c, grp) {
+			c.JSON(http.StatusCreated, grp)
+		}
+	})
+
+	// Resolves the storage key (project or subject id) to its descriptor.
+	s.handle(http.MethodGet, "{org}/_apis/graph/descriptors/{id}", func(c *Call) {
+		id := c.Param("id")
+		if _, prj := c.table("projects", c.Param("org")).byID(id); prj != nil {
+			c.JSON(http.StatusOK, Object{"value": descriptor("scp", id)})
+			return
+		}
+		if _, el := subjects(c).byField("originId", id); el != nil {
+			c.JSON(http.StatusOK, Object{"value": el["descriptor"]})
+			return
+		}
+		c.NotFound("storage key " + id)
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/graph/membershipstates/{descriptor}", func(c *Call) {
+		if _, el := subjects(c).byField("descriptor", c.Param("descriptor")); el == nil {
+			c.NotFound("subject " + c.Param("descriptor"))
+			return
+		}
+		c.JSON(http.StatusOK, Object{"active": true})
+	})
+
+	membership := func(c *Call) (int, Object) {
+		return memberships(c).find(func(el Object) bool {
+			return el["memberDescriptor"] == c.Param("subject") && el["containerDescriptor"] == c.Param("container")
+		})
+	}
+
+	s.handle(http.MethodGet, "{org}/_apis/graph/memberships/{subject}/{container}", func(c *Call) {
+		if _, el := membership(c); el != nil {
+			c.JSON(http.StatusOK, el)
+			return
+		}
+		c.NotFound("membership")
+	})
+
+	s.handle(http.MethodPut, "{org}/_apis/graph/memberships/{subject}/{container}", func(c *Call) {
+		for _, key := range []string{"subject", "container"} {
+			if _, el := subjects(c).byField("descriptor", c.Param(key)); el == nil {
+				c.NotFound(key + " " + c.Param(key))
+				return
+			}
+		}
+		_, el := membership(c)
+		if el == nil {
+			el = memberships(c).insert(Object{
+				"memberDescriptor":    c.Param("subject"),
+				"containerDescriptor": c.Param("container"),
+			})
+		}
+		c.JSON(http.StatusCreated, el)
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/identities", func(c *Call) {
+		filter := c.Query("filterValue")
+		c.List(c.table("identities", c.Param("org")).list(func(el Object) bool {
+			return len(filter) == 0 || strings.EqualFold(String(el["providerDisplayName"]), filter)
+		}))
+	})
+}
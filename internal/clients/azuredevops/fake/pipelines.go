@@ -0,0 +1,631 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CompleteRun sets the run as completed with the specified result.
+func (s *Server) CompleteRun(org, project string, pipelineId, runId int, result string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return false
+	}
+	_, run := s.table("runs", org, String(prj["id"]), String(pipelineId)).byID(String(runId))
+	if run == nil {
+		return false
+	}
+	run["state"] = "completed"
+	run["result"] = result
+	run["finishedDate"] = time.Now().UTC().Format(time.RFC3339)
+	return true
+}
+
+// PublishArtifact stores a pipeline artifact published by the run.
+func (s *Server) PublishArtifact(org, project string, runId int, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return false
+	}
+	t := s.table("artifacts", org, String(prj["id"]), String(runId))
+	t.insert(Object{
+		"id":     len(t.items) + 1,
+		"name":   name,
+		"source": s.newUUID(),
+		"resource": Object{
+			"type": "PipelineArtifact",
+			"data": name,
+			"downloadUrl": fmt.Sprintf("https://artifacts.dev.azure.com/%s/%s/_apis/artifacts/%d/%s?format=zip",
+				org, String(prj["id"]), runId, name),
+		},
+	})
+	return true
+}
+
+// AddPipeline stores a pipeline in the root folder and returns it.
+func (s *Server) AddPipeline(org, project, name string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return s.addPipeline(org, prj, Object{"name": name, "folder": "\\"})
+}
+
+// AddQueue stores an agent queue of the pool in the project and returns it.
+func (s *Server) AddQueue(org, project, name string, pool Object) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	return s.table("queues", org, String(prj["id"])).insert(Object{
+		"id":        s.nextInt(),
+		"name":      name,
+		"projectId": prj["id"],
+		"pool":      Object{"id": pool["id"], "name": pool["name"], "isHosted": pool["isHosted"]},
+	})
+}
+
+// Pipeline returns the stored pipeline.
+func (s *Server) Pipeline(org, project, id string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prj := s.findProject(org, project)
+	if prj == nil {
+		return nil
+	}
+	_, pip := s.table("pipelines", org, String(prj["id"])).byID(id)
+	return pip
+}
+
+func (s *Server) addPipeline(org string, prj, pip Object) Object {
+	id := s.nextInt()
+	pip["id"] = id
+	pip["revision"] = 1
+	pip["url"] = fmt.Sprintf("%s/%s/%s/_apis/pipelines/%d", s.URL, org, prj["id"], id)
+	// The settings only known to the build definitions API.
+	pip["_definition"] = Object{
+		"triggers": []any{Object{
+			"triggerType":                  "continuousIntegration",
+			"settingsSourceType":           2,
+			"branchFilters":                []any{},
+			"pathFilters":                  []any{},
+			"batchChanges":                 false,
+			"maxConcurrentBuildsPerBranch": 1,
+		}},
+		"queue":                 Object{"name": "Azure Pipelines"},
+		"badgeEnabled":          false,
+		"jobAuthorizationScope": "projectCollection",
+		"repository": Object{
+			"properties":         Object{"reportBuildStatus": "true", "fetchDepth": "1"},
+			"clean":              "false",
+			"checkoutSubmodules": false,
+		},
+	}
+	return s.table("pipelines", org, String(prj["id"])).insert(pip)
+}
+
+// Repository types of the pipelines and of the build definitions.
+var definitionRepositoryTypes = map[string]string{
+	"azureReposGit":    "TfsGit",
+	"gitHub":           "GitHub",
+	"gitHubEnterprise": "GitHubEnterprise",
+}
+
+// The fields of the build definitions stored by the pipelines.
+var definitionCoreFields = []string{"id", "name", "path", "revision", "url"}
+
+// definition returns the build definition view of a pipeline.
+func (s *Server) definition(org string, prj, pip Object) Object {
+	res := Object{
+		"id":       pip["id"],
+		"name":     pip["name"],
+		"path":     pip["folder"],
+		"revision": pip["revision"],
+		"url":      fmt.Sprintf("%s/%s/%s/_apis/build/Definitions/%s", s.URL, org, prj["id"], String(pip["id"])),
+	}
+	settings, _ := pip["_definition"].(Object)
+	for k, v := range settings {
+		res[k] = v
+	}
+	// The values of the secret variables are never returned.
+	if vars, ok := settings["variables"].(map[string]any); ok {
+		masked := Object{}
+		for k, v := range vars {
+			val, _ := v.(map[string]any)
+			el := Object{}
+			for vk, vv := range val {
+				el[vk] = vv
+			}
+			if isSecret, _ := val["isSecret"].(bool); isSecret {
+				el["value"] = nil
+			}
+			masked[k] = el
+		}
+		res["variables"] = masked
+	}
+	// The process and the repository follow the pipeline configuration
+	// keeping the other stored fields.
+	if conf, ok := pip["configuration"].(map[string]any); ok {
+		proc, _ := settings["process"].(map[string]any)
+		res["process"] = merge(merge(Object{}, proc), Object{"type": 2, "yamlFilename": conf["path"]})
+		if repo, ok := conf["repository"].(map[string]any); ok {
+			stored, _ := settings["repository"].(map[string]any)
+			res["repository"] = merge(merge(Object{}, stored), Object{
+				"id":            repo["id"],
+				"name":          repo["name"],
+				"type":          definitionRepositoryTypes[String(repo["type"])],
+				"defaultBranch": "refs/heads/main",
+			})
+		}
+	}
+	return res
+}
+
+func (s *Server) registerPipelines() {
+	pipelines := func(c *Call, prj Object) *table {
+		return c.table("pipelines", c.Param("org"), String(prj["id"]))
+	}
+	runs := func(c *Call, prj Object) *table {
+		return c.table("runs", c.Param("org"), String(prj["id"]), c.Param("pipeline"))
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.List(publicList(pipelines(c, prj).list(nil)))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/{pipeline}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, pip := pipelines(c, prj).byID(c.Param("pipeline"))
+		if pip == nil {
+			c.NotFound("pipeline " + c.Param("pipeline"))
+			return
+		}
+		c.JSON(http.StatusOK, public(pip))
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/pipelines", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		pip, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, ok := pip["folder"]; !ok {
+			pip["folder"] = "\\"
+		}
+		t := pipelines(c, prj)
+		if _, el := t.find(func(el Object) bool {
+			return strings.EqualFold(String(el["name"]), String(pip["name"])) &&
+				String(el["folder"]) == String(pip["folder"])
+		}); el != nil {
+			c.Error(http.StatusConflict, "DefinitionExistsException",
+				fmt.Sprintf("pipeline '%s' already exists", String(pip["name"])))
+			return
+		}
+
+		c.JSON(http.StatusOK, public(c.addPipeline(c.Param("org"), prj, pip)))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/build/definitions/{pipeline}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, pip := pipelines(c, prj).byID(c.Param("pipeline"))
+		if pip == nil {
+			c.NotFound("definition " + c.Param("pipeline"))
+			return
+		}
+		c.JSON(http.StatusOK, c.definition(c.Param("org"), prj, pip))
+	})
+
+	// The definitions are replaced as a whole: the fields missing
+	// in the request are lost.
+	s.handle(http.MethodPut, "{org}/{project}/_apis/build/definitions/{pipeline}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		t := pipelines(c, prj)
+		_, pip := t.byID(c.Param("pipeline"))
+		if pip == nil {
+			c.NotFound("definition " + c.Param("pipeline"))
+			return
+		}
+		def, ok := c.Object()
+		if !ok {
+			return
+		}
+		if String(def["revision"]) != String(pip["revision"]) {
+			c.Error(http.StatusConflict, "DefinitionUpdateConflictException",
+				fmt.Sprintf("the definition revision %s is not the latest %s", String(def["revision"]), String(pip["revision"])))
+			return
+		}
+		if len(String(def["path"])) == 0 {
+			def["path"] = "\\"
+		}
+		if _, el := t.find(func(el Object) bool {
+			return String(el["id"]) != String(pip["id"]) &&
+				strings.EqualFold(String(el["name"]), String(def["name"])) &&
+				strings.EqualFold(String(el["folder"]), String(def["path"]))
+		}); el != nil {
+			c.Error(http.StatusConflict, "DefinitionExistsException",
+				fmt.Sprintf("pipeline '%s' already exists", String(def["name"])))
+			return
+		}
+
+		if ref, ok := def["queue"].(map[string]any); ok && ref["id"] != nil {
+			_, q := c.table("queues", c.Param("org"), String(prj["id"])).byID(String(ref["id"]))
+			if q == nil {
+				c.Error(http.StatusBadRequest, "DefinitionQueueNotFoundException",
+					fmt.Sprintf("queue '%s' not found", String(ref["id"])))
+				return
+			}
+			def["queue"] = Object{"id": q["id"], "name": q["name"], "pool": q["pool"]}
+		}
+
+		// The secret variables sent without value keep the stored one.
+		old, _ := pip["_definition"].(Object)
+		if vars, ok := def["variables"].(map[string]any); ok {
+			oldVars, _ := old["variables"].(map[string]any)
+			for k, v := range vars {
+				val, _ := v.(map[string]any)
+				if isSecret, _ := val["isSecret"].(bool); isSecret && val["value"] == nil {
+					if prev, ok := oldVars[k].(map[string]any); ok {
+						val["value"] = prev["value"]
+					}
+				}
+			}
+		}
+		settings := Object{}
+		for k, v := range def {
+			settings[k] = v
+		}
+		for _, k := range definitionCoreFields {
+			delete(settings, k)
+		}
+
+		pip["name"] = def["name"]
+		pip["folder"] = def["path"]
+		pip["revision"] = pip["revision"].(int) + 1
+		pip["_definition"] = settings
+		conf := Object{"type": "yaml"}
+		if proc, ok := def["process"].(map[string]any); ok {
+			conf["path"] = proc["yamlFilename"]
+		}
+		if repo, ok := def["repository"].(map[string]any); ok {
+			typ := "azureReposGit"
+			for k, v := range definitionRepositoryTypes {
+				if v == String(repo["type"]) {
+					typ = k
+				}
+			}
+			conf["repository"] = Object{"id": repo["id"], "name": repo["name"], "type": typ}
+		}
+		pip["configuration"] = conf
+
+		c.JSON(http.StatusOK, c.definition(c.Param("org"), prj, pip))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/build/definitions/{pipeline}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		idx, pip := pipelines(c, prj).byID(c.Param("pipeline"))
+		if pip == nil {
+			c.NotFound("definition " + c.Param("pipeline"))
+			return
+		}
+		pipelines(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/pipelines/{pipeline}/runs", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, pip := pipelines(c, prj).byID(c.Param("pipeline"))
+		if pip == nil {
+			c.NotFound("pipeline " + c.Param("pipeline"))
+			return
+		}
+		params, ok := c.Object()
+		if !ok {
+			return
+		}
+
+		id := c.nextInt()
+		run := Object{
+			"id":          id,
+			"name":        fmt.Sprintf("%s.%d", time.Now().UTC().Format("20060102"), id),
+			"state":       "inProgress",
+			"createdDate": time.Now().UTC().Format(time.RFC3339),
+			"pipeline": Object{
+				"id":       pip["id"],
+				"name":     pip["name"],
+				"folder":   pip["folder"],
+				"revision": pip["revision"],
+			},
+			"url": fmt.Sprintf("%s/%s/%s/_apis/pipelines/%s/runs/%d",
+				c.URL, c.Param("org"), prj["id"], c.Param("pipeline"), id),
+		}
+		for _, k := range []string{"templateParameters", "variables", "resources"} {
+			if v, ok := params[k]; ok {
+				run[k] = v
+			}
+		}
+		if preview, _ := params["previewRun"].(bool); preview {
+			run["finalYaml"] = "steps: []"
+			c.JSON(http.StatusOK, run)
+			return
+		}
+		c.JSON(http.StatusOK, runs(c, prj).insert(run))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/{pipeline}/runs", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.List(runs(c, prj).list(nil))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/{pipeline}/runs/{run}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, run := runs(c, prj).byID(c.Param("run"))
+		if run == nil {
+			c.NotFound("run " + c.Param("run"))
+			return
+		}
+		c.JSON(http.StatusOK, run)
+	})
+
+	s.registerBuilds()
+	s.registerPipelinePermissions()
+	s.registerChecks()
+}
+
+// Runs are builds of the pipelines: the build endpoints find
+// the run by id among the runs of all the project pipelines.
+func (s *Server) registerBuilds() {
+	findRun := func(c *Call, prj Object) Object {
+		for _, pip := range c.table("pipelines", c.Param("org"), String(prj["id"])).list(nil) {
+			if _, run := c.table("runs", c.Param("org"), String(prj["id"]), String(pip["id"])).byID(c.Param("id")); run != nil {
+				return run
+			}
+		}
+		c.NotFound("build " + c.Param("id"))
+		return nil
+	}
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/build/builds/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		run := findRun(c, prj)
+		if run == nil {
+			return
+		}
+		params, ok := c.Object()
+		if !ok {
+			return
+		}
+		if !strings.EqualFold(String(params["status"]), "cancelling") {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "only the build cancellation is supported")
+			return
+		}
+		// The agents stop the run immediately.
+		if String(run["state"]) != "completed" {
+			run["state"] = "completed"
+			run["result"] = "canceled"
+			run["finishedDate"] = time.Now().UTC().Format(time.RFC3339)
+		}
+		c.JSON(http.StatusOK, Object{"id": run["id"], "status": "completed", "result": run["result"]})
+	})
+
+	// The timeline has a single 'Build' stage with the state of the run;
+	// the failed runs have a failed 'Run tests' task with the log 1.
+	s.handle(http.MethodGet, "{org}/{project}/_apis/build/builds/{id}/timeline", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		run := findRun(c, prj)
+		if run == nil {
+			return
+		}
+		records := []Object{
+			{"id": "stage-1", "type": "Stage", "name": "Build", "identifier": "Build", "order": 1,
+				"state": run["state"], "result": run["result"]},
+			{"id": "job-1", "parentId": "stage-1", "type": "Job", "name": "Job", "order": 1,
+				"state": run["state"], "result": run["result"]},
+		}
+		if String(run["result"]) == "failed" {
+			records = append(records, Object{
+				"id": "task-1", "parentId": "job-1", "type": "Task", "name": "Run tests", "order": 1,
+				"state": "completed", "result": "failed", "errorCount": 1,
+				"issues": []Object{{"type": "error", "category": "General", "message": failedTaskError}},
+				"log":    Object{"id": 1},
+			})
+		}
+		c.JSON(http.StatusOK, Object{"id": c.newUUID(), "records": records})
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/build/builds/{id}/logs/{logId}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		run := findRun(c, prj)
+		if run == nil {
+			return
+		}
+		if String(run["result"]) != "failed" || c.Param("logId") != "1" {
+			c.NotFound("log " + c.Param("logId"))
+			return
+		}
+		c.w.Header().Set("Content-Type", "text/plain")
+		c.w.WriteHeader(http.StatusOK)
+		for i := 1; i <= failedTaskLogLines-1; i++ {
+			fmt.Fprintf(c.w, "test %d passed\r\n", i)
+		}
+		fmt.Fprintf(c.w, "##[error]%s\r\n", failedTaskError)
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/build/builds/{id}/artifacts", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		if run := findRun(c, prj); run != nil {
+			c.List(c.table("artifacts", c.Param("org"), String(prj["id"]), c.Param("id")).list(nil))
+		}
+	})
+}
+
+// The error and the log length of the failed task of the failed runs.
+const (
+	failedTaskError    = "Process completed with exit code 1."
+	failedTaskLogLines = 100
+)
+
+func (s *Server) registerPipelinePermissions() {
+	permissions := func(c *Call, prj Object) Object {
+		t := c.table("pipelinepermissions", c.Param("org"), String(prj["id"]), c.Param("type"))
+		_, perm := t.byField("_id", c.Param("id"))
+		if perm == nil {
+			perm = t.insert(Object{
+				"_id":          c.Param("id"),
+				"allPipelines": Object{"authorized": false},
+				"pipelines":    []any{},
+				"resource": Object{
+					"type": c.Param("type"),
+					"id":   c.Param("id"),
+				},
+			})
+		}
+		return perm
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/pipelinepermissions/{type}/{id}", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.JSON(http.StatusOK, public(permissions(c, prj)))
+		}
+	})
+
+	s.handle(http.MethodPatch, "{org}/{project}/_apis/pipelines/pipelinepermissions/{type}/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		upd, ok := c.Object()
+		if !ok {
+			return
+		}
+		perm := permissions(c, prj)
+		if all, ok := upd["allPipelines"].(Object); ok {
+			perm["allPipelines"] = Object{"authorized": all["authorized"] == true}
+		}
+
+		cur, _ := perm["pipelines"].([]any)
+		items, _ := upd["pipelines"].([]any)
+		for _, el := range items {
+			pip, _ := el.(Object)
+			replaced := false
+			for i, old := range cur {
+				if String(old.(Object)["id"]) == String(pip["id"]) {
+					cur[i], replaced = pip, true
+				}
+			}
+			if !replaced {
+				cur = append(cur, pip)
+			}
+		}
+		perm["pipelines"] = cur
+
+		c.JSON(http.StatusOK, public(perm))
+	})
+}
+
+func (s *Server) registerChecks() {
+	checks := func(c *Call, prj Object) *table {
+		return c.table("checks", c.Param("org"), String(prj["id"]))
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/checks/configurations", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		resType, resId := c.Query("resourceType"), c.Query("resourceId")
+		c.List(checks(c, prj).list(func(el Object) bool {
+			res, _ := el["resource"].(Object)
+			return (len(resType) == 0 || strings.EqualFold(String(res["type"]), resType)) &&
+				(len(resId) == 0 || String(res["id"]) == resId)
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/pipelines/checks/configurations/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		_, chk := checks(c, prj).byID(c.Param("id"))
+		if chk == nil {
+			c.NotFound("check configuration " + c.Param("id"))
+			return
+		}
+		c.JSON(http.StatusOK, chk)
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/pipelines/checks/configurations", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		chk, ok := c.Object()
+		if !ok {
+			return
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		chk["id"] = c.nextInt()
+		chk["createdOn"] = now
+		chk["modifiedOn"] = now
+		c.JSON(http.StatusOK, checks(c, prj).insert(chk))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/pipelines/checks/configurations/{id}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		idx, chk := checks(c, prj).byID(c.Param("id"))
+		if chk == nil {
+			c.NotFound("check configuration " + c.Param("id"))
+			return
+		}
+		checks(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,87 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func (s *Server) registerPolicies() {
+	policies := func(c *Call, prj Object) *table {
+		return c.table("policies", c.Param("org"), String(prj["id"]))
+	}
+	get := func(c *Call) (Object, int, Object) {
+		prj := c.project()
+		if prj == nil {
+			return nil, -1, nil
+		}
+		idx, pol := policies(c, prj).byID(c.Param("id"))
+		if pol == nil {
+			c.Error(http.StatusNotFound, "PolicyConfigurationNotFoundException",
+				fmt.Sprintf("policy configuration %s not found", c.Param("id")))
+		}
+		return prj, idx, pol
+	}
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/policy/configurations", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		policyType := c.Query("policyType")
+		c.List(policies(c, prj).list(func(el Object) bool {
+			t, _ := el["type"].(Object)
+			return len(policyType) == 0 || String(t["id"]) == policyType
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/{project}/_apis/policy/configurations/{id}", func(c *Call) {
+		if _, _, pol := get(c); pol != nil {
+			c.JSON(http.StatusOK, pol)
+		}
+	})
+
+	s.handle(http.MethodPost, "{org}/{project}/_apis/policy/configurations", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		pol, ok := c.Object()
+		if !ok {
+			return
+		}
+		if t, _ := pol["type"].(Object); len(String(t["id"])) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "policy type is required")
+			return
+		}
+		id := c.nextInt()
+		pol["id"] = id
+		pol["revision"] = 1
+		pol["isDeleted"] = false
+		pol["url"] = fmt.Sprintf("%s/%s/%s/_apis/policy/configurations/%d", c.URL, c.Param("org"), prj["id"], id)
+		c.JSON(http.StatusOK, policies(c, prj).insert(pol))
+	})
+
+	s.handle(http.MethodPut, "{org}/{project}/_apis/policy/configurations/{id}", func(c *Call) {
+		_, _, pol := get(c)
+		if pol == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		obj["id"] = pol["id"]
+		obj["url"] = pol["url"]
+		obj["revision"] = c.nextInt()
+		c.JSON(http.StatusOK, merge(pol, obj))
+	})
+
+	s.handle(http.MethodDelete, "{org}/{project}/_apis/policy/configurations/{id}", func(c *Call) {
+		prj, idx, pol := get(c)
+		if pol == nil {
+			return
+		}
+		policies(c, prj).remove(idx)
+		c.Status(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,192 @@
+package fake
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AddProject stores a well formed project and returns it.
+func (s *Server) AddProject(org, name string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addProject(org, Object{"name": name, "state": "wellFormed"})
+}
+
+// Project returns the project with the specified id or name, if any.
+func (s *Server) Project(org, idOrName string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findProject(org, idOrName)
+}
+
+func (s *Server) addProject(org string, prj Object) Object {
+	prj["id"] = s.newUUID()
+	prj["revision"] = s.nextInt()
+	if _, ok := prj["visibility"]; !ok {
+		prj["visibility"] = "private"
+	}
+	prj = s.table("projects", org).insert(prj)
+
+	// Every project has a default team and a default repository.
+	s.addTeamGroup(org, prj, s.table("teams", org, String(prj["id"])).insert(Object{
+		"id":          s.newUUID(),
+		"name":        String(prj["name"]) + " Team",
+		"projectId":   prj["id"],
+		"projectName": prj["name"],
+	}))
+	s.addRepository(org, prj, Object{"name": prj["name"]})
+	s.addBuildService(org, prj)
+
+	return prj
+}
+
+func (s *Server) findProject(org, idOrName string) Object {
+	t := s.table("projects", org)
+	if _, prj := t.byID(idOrName); prj != nil {
+		return prj
+	}
+	_, prj := t.byField("name", idOrName)
+	return prj
+}
+
+// project resolves the '{project}' segment writing a 404 if not found.
+func (c *Call) project() Object {
+	prj := c.findProject(c.Param("org"), c.Param("project"))
+	if prj == nil {
+		c.NotFound("project " + c.Param("project"))
+	}
+	return prj
+}
+
+func (s *Server) registerProjects() {
+	s.handle(http.MethodGet, "{org}/_apis/projects", func(c *Call) {
+		state := c.Query("stateFilter")
+		c.List(c.table("projects", c.Param("org")).list(func(el Object) bool {
+			return len(state) == 0 || strings.EqualFold(state, "all") ||
+				strings.EqualFold(state, String(el["state"]))
+		}))
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/projects/{project}", func(c *Call) {
+		if prj := c.project(); prj != nil {
+			c.JSON(http.StatusOK, prj)
+		}
+	})
+
+	s.handle(http.MethodPost, "{org}/_apis/projects", func(c *Call) {
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		name := String(obj["name"])
+		if len(name) == 0 {
+			c.Error(http.StatusBadRequest, "InvalidArgumentValueException", "project name is required")
+			return
+		}
+		if c.findProject(c.Param("org"), name) != nil {
+			c.Error(http.StatusConflict, "ProjectAlreadyExistsException",
+				"project '"+name+"' already exists")
+			return
+		}
+
+		obj["state"] = "createPending"
+		prj := c.addProject(c.Param("org"), obj)
+		c.JSON(http.StatusAccepted, c.startOperation(func() {
+			prj["state"] = "wellFormed"
+		}))
+	})
+
+	s.handle(http.MethodPatch, "{org}/_apis/projects/{project}", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusAccepted, c.startOperation(func() {
+			for _, k := range []string{"name", "description", "visibility"} {
+				if v, ok := obj[k]; ok && v != "" {
+					prj[k] = v
+				}
+			}
+			prj["revision"] = c.nextInt()
+		}))
+	})
+
+	s.handle(http.MethodDelete, "{org}/_apis/projects/{project}", func(c *Call) {
+		idx, prj := c.table("projects", c.Param("org")).byID(c.Param("project"))
+		if prj == nil {
+			c.NotFound("project " + c.Param("project"))
+			return
+		}
+		c.table("projects", c.Param("org")).remove(idx)
+		c.JSON(http.StatusAccepted, c.startOperation(nil))
+	})
+
+	s.registerTeams()
+}
+
+func (s *Server) registerTeams() {
+	teams := func(c *Call) *table {
+		return c.table("teams", c.Param("org"), String(c.project()["id"]))
+	}
+
+	s.handle(http.MethodGet, "{org}/_apis/projects/{project}/teams", func(c *Call) {
+		if c.project() != nil {
+			c.List(teams(c).list(nil))
+		}
+	})
+
+	s.handle(http.MethodGet, "{org}/_apis/projects/{project}/teams/{team}", func(c *Call) {
+		if c.project() == nil {
+			return
+		}
+		t := teams(c)
+		_, team := t.byID(c.Param("team"))
+		if team == nil {
+			_, team = t.byField("name", c.Param("team"))
+		}
+		if team == nil {
+			c.NotFound("team " + c.Param("team"))
+			return
+		}
+		c.JSON(http.StatusOK, team)
+	})
+
+	s.handle(http.MethodPost, "{org}/_apis/projects/{project}/teams", func(c *Call) {
+		prj := c.project()
+		if prj == nil {
+			return
+		}
+		obj, ok := c.Object()
+		if !ok {
+			return
+		}
+		if _, el := teams(c).byField("name", String(obj["name"])); el != nil {
+			c.Error(http.StatusConflict, "TeamAlreadyExistsException",
+				"team '"+String(obj["name"])+"' already exists")
+			return
+		}
+		obj["id"] = c.newUUID()
+		obj["projectId"] = prj["id"]
+		obj["projectName"] = prj["name"]
+		c.addTeamGroup(c.Param("org"), prj, teams(c).insert(obj))
+		c.JSON(http.StatusCreated, obj)
+	})
+
+	s.handle(http.MethodDelete, "{org}/_apis/projects/{project}/teams/{team}", func(c *Call) {
+		if c.project() == nil {
+			return
+		}
+		idx, team := teams(c).byID(c.Param("team"))
+		if team == nil {
+			c.NotFound("team " + c.Param("team"))
+			return
+		}
+		teams(c).remove(idx)
+		c.removeSubject(c.Param("org"), String(team["id"]))
+		c.Status(http.StatusNoContent)
+	})
+}
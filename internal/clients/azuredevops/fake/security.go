@@ -0,0 +1,53 @@
+package fake
+
+import (
+	"net/http"
+)
+
+// AccessControlEntry returns the allow and deny bits of the descriptor
+// for the token of the security namespace.
+func (s *Server) AccessControlEntry(org, namespace, token, descriptor string) (allow, deny int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ace := s.table("accesscontrolentries", org, namespace, token).byField("descriptor", descriptor)
+	if ace == nil {
+		return 0, 0
+	}
+	return ace["allow"].(int), ace["deny"].(int)
+}
+
+func (s *Server) registerSecurity() {
+	s.handle(http.MethodPost, "{org}/_apis/accesscontrolentries/{namespace}", func(c *Call) {
+		var upd struct {
+			Merge                bool   `json:"merge"`
+			Token                string `json:"token"`
+			AccessControlEntries []struct {
+				Descriptor string `json:"descriptor"`
+				Allow      int    `json:"allow"`
+				Deny       int    `json:"deny"`
+			} `json:"accessControlEntries"`
+		}
+		if !c.Decode(&upd) {
+			return
+		}
+
+		t := c.table("accesscontrolentries", c.Param("org"), c.Param("namespace"), upd.Token)
+		res := []Object{}
+		for _, el := range upd.AccessControlEntries {
+			_, ace := t.byField("descriptor", el.Descriptor)
+			if ace == nil {
+				ace = t.insert(Object{"descriptor": el.Descriptor, "allow": 0, "deny": 0})
+			}
+			if upd.Merge {
+				ace["allow"] = (ace["allow"].(int) | el.Allow) &^ el.Deny
+				ace["deny"] = (ace["deny"].(int) | el.Deny) &^ el.Allow
+			} else {
+				ace["allow"] = el.Allow
+				ace["deny"] = el.Deny
+			}
+			res = append(res, ace)
+		}
+
+		c.JSON(http.StatusOK, Object{"count": len(res), "value": res})
+	})
+}
@@ -0,0 +1,420 @@
+// Package fake implements an in-memory Azure DevOps REST API server
+// to run the client packages and the controllers tests offline.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+)
+
+const (
+	// Token is the personal access token accepted by the server.
+	Token = "fake-pat"
+	// Organization is the organization created by NewServer.
+	Organization = "krateo"
+)
+
+// Object is a REST resource as decoded from JSON.
+type Object = map[string]any
+
+// HandlerFunc handles a matched request.
+type HandlerFunc func(c *Call)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// Server is an httptest.Server implementing the subset of the Azure DevOps
+// REST API used by this provider, storing the resources in memory.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   []route
+	tables   map[string]*table
+	seq      int
+	ops      map[string]*operation
+	requests []Request
+	failOps  bool
+
+	// OperationPolls is the number of times an async operation is
+	// reported as inProgress before succeeding (default: 0).
+	OperationPolls int
+}
+
+// NewServer starts a new fake server; call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		tables: map[string]*table{},
+		ops:    map[string]*operation{},
+	}
+	s.registerCore()
+	s.registerProjects()
+	s.registerGit()
+	s.registerPipelines()
+	s.registerDistributedTask()
+	s.registerGraph()
+	s.registerEndpoints()
+	s.registerFeeds()
+	s.registerPolicies()
+	s.registerSecurity()
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ClientOptions returns the options to connect to the server.
+func (s *Server) ClientOptions() azuredevops.ClientOptions {
+	maxRetries := 0
+	return azuredevops.ClientOptions{
+		Token: Token,
+		UriMap: &map[azuredevops.URIKey]string{
+			azuredevops.Default: s.URL,
+			azuredevops.Feeds:   s.URL,
+			azuredevops.Vssps:   s.URL,
+		},
+		MaxRetries: &maxRetries,
+	}
+}
+
+// Client returns a client connected to the server.
+func (s *Server) Client() *azuredevops.Client {
+	return azuredevops.NewClient(s.ClientOptions())
+}
+
+// Handle registers a handler that takes precedence over the built-in ones;
+// pattern segments in curly braces (i.e. '{org}') match any value.
+func (s *Server) Handle(method, pattern string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append([]route{newRoute(method, pattern, h)}, s.routes...)
+}
+
+// HandleOnce registers a handler like Handle that is removed after
+// handling a request (i.e. to make a single call fail).
+func (s *Server) HandleOnce(method, pattern string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt := newRoute(method, pattern, h)
+	rt.once = true
+	s.routes = append([]route{rt}, s.routes...)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// CountRequests returns the number of requests with the specified
+// method whose path contains the specified fragment.
+func (s *Server) CountRequests(method, fragment string) int {
+	count := 0
+	for _, el := range s.Requests() {
+		if el.Method == method && strings.Contains(el.Path, fragment) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) handle(method, pattern string, h HandlerFunc) {
+	s.routes = append(s.routes, newRoute(method, pattern, h))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   body,
+	})
+
+	if !authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	segs := splitPath(r.URL.Path)
+	for i, rt := range s.routes {
+		params, ok := rt.match(r.Method, segs)
+		if !ok {
+			continue
+		}
+		if rt.once {
+			s.routes = append(s.routes[:i:i], s.routes[i+1:]...)
+		}
+		rt.handler(&Call{
+			Server: s,
+			w:      w,
+			r:      r,
+			body:   body,
+			params: params,
+		})
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "NotFoundException",
+		fmt.Sprintf("no fake handler for %s %s", r.Method, r.URL.Path))
+}
+
+func authorized(r *http.Request) bool {
+	if _, pwd, ok := r.BasicAuth(); ok {
+		return pwd == Token
+	}
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+type route struct {
+	method  string
+	segs    []string
+	handler HandlerFunc
+	once    bool
+}
+
+func newRoute(method, pattern string, h HandlerFunc) route {
+	return route{method: method, segs: splitPath(pattern), handler: h}
+}
+
+func (rt route) match(method string, segs []string) (map[string]string, bool) {
+	if rt.method != method || len(rt.segs) != len(segs) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, el := range rt.segs {
+		if strings.HasPrefix(el, "{") && strings.HasSuffix(el, "}") {
+			params[el[1:len(el)-1]] = segs[i]
+			continue
+		}
+		if !strings.EqualFold(el, segs[i]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	var res []string
+	for _, el := range strings.Split(p, "/") {
+		if len(el) > 0 {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+// Call is a request being handled.
+type Call struct {
+	*Server
+	w      http.ResponseWriter
+	r      *http.Request
+	body   []byte
+	params map[string]string
+}
+
+// Param returns the value of a pattern segment.
+func (c *Call) Param(name string) string {
+	return c.params[name]
+}
+
+// Query returns a query string parameter.
+func (c *Call) Query(name string) string {
+	return c.r.URL.Query().Get(name)
+}
+
+// Request returns the HTTP request.
+func (c *Call) Request() *http.Request {
+	return c.r
+}
+
+// Decode unmarshals the request body.
+func (c *Call) Decode(v any) bool {
+	if err := json.Unmarshal(c.body, v); err != nil {
+		c.Error(http.StatusBadRequest, "InvalidArgumentValueException", err.Error())
+		return false
+	}
+	return true
+}
+
+// Object decodes the request body as a JSON object.
+func (c *Call) Object() (Object, bool) {
+	obj := Object{}
+	if len(c.body) == 0 {
+		return obj, true
+	}
+	return obj, c.Decode(&obj)
+}
+
+// JSON writes the value with the specified status code.
+func (c *Call) JSON(status int, v any) {
+	c.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	c.w.WriteHeader(status)
+	json.NewEncoder(c.w).Encode(v)
+}
+
+// Status writes an empty response with the specified status code.
+func (c *Call) Status(status int) {
+	c.w.WriteHeader(status)
+}
+
+// Error writes an Azure DevOps error response.
+func (c *Call) Error(status int, typeKey, msg string) {
+	writeError(c.w, status, typeKey, msg)
+}
+
+// NotFound writes a 404 error response.
+func (c *Call) NotFound(what string) {
+	c.Error(http.StatusNotFound, "NotFoundException", fmt.Sprintf("%s not found", what))
+}
+
+// List writes the '{count, value}' envelope honoring $top, $skip and
+// continuationToken; the continuation token is the next item index.
+func (c *Call) List(items []Object) {
+	start := 0
+	if val, err := strconv.Atoi(c.Query("continuationToken")); err == nil {
+		start = val
+	} else if val, err := strconv.Atoi(c.Query("$skip")); err == nil {
+		start = val
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := len(items)
+	if top, err := strconv.Atoi(c.Query("$top")); err == nil && top > 0 && start+top < end {
+		end = start + top
+		c.w.Header().Set(azuredevops.HeaderContinuationToken, strconv.Itoa(end))
+	}
+
+	page := items[start:end]
+	if page == nil {
+		page = []Object{}
+	}
+	c.JSON(http.StatusOK, map[string]any{
+		"count": len(page),
+		"value": page,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, typeKey, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(azuredevops.APIError{
+		Message: msg,
+		TypeKey: typeKey,
+		EventID: 3000,
+	})
+}
+
+// table is an ordered collection of resources.
+type table struct {
+	items []Object
+}
+
+func (s *Server) table(kind string, scope ...string) *table {
+	key := strings.ToLower(kind + "|" + strings.Join(scope, "/"))
+	t, ok := s.tables[key]
+	if !ok {
+		t = &table{}
+		s.tables[key] = t
+	}
+	return t
+}
+
+func (t *table) find(match func(Object) bool) (int, Object) {
+	for i, el := range t.items {
+		if match(el) {
+			return i, el
+		}
+	}
+	return -1, nil
+}
+
+func (t *table) byField(field, val string) (int, Object) {
+	return t.find(func(el Object) bool {
+		return strings.EqualFold(String(el[field]), val)
+	})
+}
+
+func (t *table) byID(id string) (int, Object) {
+	return t.byField("id", id)
+}
+
+func (t *table) insert(obj Object) Object {
+	t.items = append(t.items, obj)
+	return obj
+}
+
+func (t *table) remove(idx int) {
+	t.items = append(t.items[:idx], t.items[idx+1:]...)
+}
+
+func (t *table) list(match func(Object) bool) []Object {
+	res := []Object{}
+	for _, el := range t.items {
+		if match == nil || match(el) {
+			res = append(res, el)
+		}
+	}
+	return res
+}
+
+func (s *Server) nextInt() int {
+	s.seq++
+	return s.seq
+}
+
+func (s *Server) newUUID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextInt())
+}
+
+// String returns the string representation of a JSON value.
+func String(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// merge copies the src fields into dst.
+func merge(dst, src Object) Object {
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// sortedKeys returns the keys of the map in lexical order.
+func sortedKeys[T any](m map[string]T) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -2,9 +2,7 @@ package feeds
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"path"
 	"reflect"
@@ -236,7 +234,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Feed,
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Feed{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -244,7 +241,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Feed,
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -267,14 +264,32 @@ type ListOptions struct {
 	IncludeUrls bool
 }
 
-type ListResult struct {
-	Count int    `json:"count"`
-	Feeds []Feed `json:"value,omitempty"`
-}
-
 // Get all feeds in an account where you have the provided role access.
 // GET https://feeds.dev.azure.com/{organization}/{project}/_apis/packaging/feeds?api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]Feed, error) {
+	return newPager(cli, opts).All(ctx)
+}
+
+type FindOptions struct {
+	// Name of the organization
+	Organization string
+	Project      string
+	FeedName     string
+}
+
+func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*Feed, error) {
+	pager := newPager(cli, ListOptions{
+		Organization: opts.Organization,
+		Project:      opts.Project,
+		IncludeUrls:  true,
+	})
+
+	return pager.Find(ctx, func(el Feed) bool {
+		return el.Name == opts.FeedName
+	})
+}
+
+func newPager(cli *azuredevops.Client, opts ListOptions) *azuredevops.Pager[Feed] {
 	var fullPath string
 	if len(opts.Project) == 0 {
 		fullPath = path.Join(opts.Organization, "_apis/packaging/feeds")
@@ -294,76 +309,15 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) ([]Fee
 	}
 	params = append(params, "includeUrls", fmt.Sprintf("%t", opts.IncludeUrls))
 
-	uri, err := httplib.NewURLBuilder(
-		httplib.URLBuilderOptions{
-			BaseURL: cli.BaseURL(azuredevops.Feeds),
-			Path:    fullPath,
-			Params:  params,
-		}).Build()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := httplib.Get(uri.String())
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
-	val := []Feed{}
-
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		AuthMethod: cli.AuthMethod(),
-		Verbose:    cli.Verbose(),
-		ResponseHandler: func(res *http.Response) error {
-			data, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-
-			all := &ListResult{}
-			if err = json.Unmarshal(data, &all); err != nil {
-				return err
-			}
-
-			val = append(val, all.Feeds...)
-
-			return nil
-		},
-
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
-
-	return val, err
-}
-
-type FindOptions struct {
-	// Name of the organization
-	Organization string
-	Project      string
-	FeedName     string
-}
-
-func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*Feed, error) {
-	all, err := List(ctx, cli, ListOptions{
-		Organization: opts.Organization,
-		Project:      opts.Project,
-		IncludeUrls:  true,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	for _, el := range all {
-		if el.Name == opts.FeedName {
-			return &el, nil
-		}
-	}
-
-	return nil, nil
+	return azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[Feed], error) {
+			return azuredevops.FetchPage[Feed](ctx, cli, azuredevops.FetchPageOptions{
+				URIKey: azuredevops.Feeds,
+				Path:   fullPath,
+				Params: params,
+				Page:   pr,
+			})
+		})
 }
 
 // Options for the Create feed function
@@ -408,14 +362,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Feed{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
@@ -454,14 +407,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Feed{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -507,7 +459,7 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
@@ -0,0 +1,72 @@
+package feeds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestFeedsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	feed, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Feed:         &Feed{Name: "packages", Description: helpers.StringPtr("project feed")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Project == nil || feed.Project.Id != projectId {
+		t.Fatalf("expected project scoped feed, got: %+v", feed.Project)
+	}
+
+	if _, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, Feed: &Feed{Name: "packages"}}); err != nil {
+		t.Fatalf("expected organization scoped feed with the same name, got: %v", err)
+	}
+	if _, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, Project: projectId, Feed: &Feed{Name: "packages"}}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: projectId, FeedName: "packages"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || helpers.String(found.Id) != helpers.String(feed.Id) {
+		t.Fatalf("expected feed %s, got: %v", helpers.String(feed.Id), found)
+	}
+
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		FeedId:       helpers.String(feed.Id),
+		FeedUpdate:   &FeedUpdate{Description: helpers.StringPtr("updated")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, FeedId: helpers.String(feed.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(got.Description) != "updated" {
+		t.Fatalf("expected updated description, got: %s", helpers.String(got.Description))
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, Project: projectId, FeedId: helpers.String(feed.Id)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, FeedId: helpers.String(feed.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
@@ -82,7 +82,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*FeedPe
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &FeedPermissionResponse{
 		Value: []feeds.FeedPermission{},
 	}
@@ -92,7 +91,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*FeedPe
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -130,7 +129,6 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &FeedPermissionResponse{
 		Value: []feeds.FeedPermission{},
 	}
@@ -139,7 +137,7 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -0,0 +1,74 @@
+package feedpermissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/feeds"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestFeedPermissionsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	feed, err := feeds.Create(ctx, cli, feeds.CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Feed:         &feeds.Feed{Name: "packages"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feedId := helpers.String(feed.Id)
+
+	descriptor := "Microsoft.TeamFoundation.Identity;S-1-9-1551374245-1"
+	res, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceId:   feedId,
+		FeedPermissions: []*feeds.FeedPermission{
+			{IdentityDescriptor: helpers.StringPtr(descriptor), Role: helpers.StringPtr("contributor")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 {
+		t.Fatalf("expected 1 permission, got: %d", res.Count)
+	}
+
+	res, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, FeedId: feedId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 || helpers.String(res.Value[0].Role) != "contributor" {
+		t.Fatalf("expected contributor role, got: %+v", res.Value)
+	}
+
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceId:   feedId,
+		FeedPermissions: []*feeds.FeedPermission{
+			{IdentityDescriptor: helpers.StringPtr(descriptor), Role: helpers.StringPtr("none")},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, FeedId: feedId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 0 {
+		t.Fatalf("expected the 'none' role to remove the permission, got: %+v", res.Value)
+	}
+}
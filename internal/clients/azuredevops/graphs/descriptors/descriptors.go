@@ -62,6 +62,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Descri
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	res := &DescriptorResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -69,7 +70,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Descri
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
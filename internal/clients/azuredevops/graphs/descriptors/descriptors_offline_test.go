@@ -0,0 +1,40 @@
+package descriptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestGetDescriptorOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	grp := srv.AddGroup(fake.Organization, "Readers")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	desc, err := GetDescriptor(ctx, cli, GetOptions{Organization: fake.Organization, ResourceID: fake.String(prj["id"])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(helpers.String(desc)) == 0 {
+		t.Fatalf("expected project scope descriptor")
+	}
+
+	desc, err = GetDescriptor(ctx, cli, GetOptions{Organization: fake.Organization, ResourceID: fake.String(grp["originId"])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(desc) != fake.String(grp["descriptor"]) {
+		t.Fatalf("expected descriptor %s, got: %s", fake.String(grp["descriptor"]), helpers.String(desc))
+	}
+
+	if _, err := GetDescriptor(ctx, cli, GetOptions{Organization: fake.Organization, ResourceID: "missing"}); err == nil {
+		t.Fatalf("expected error for an unknown storage key")
+	}
+}
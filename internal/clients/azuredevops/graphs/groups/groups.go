@@ -107,6 +107,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*GroupR
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	res := &GroupResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -114,7 +115,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*GroupR
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if reflect.DeepEqual(*res, GroupResponse{}) {
@@ -152,6 +153,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*Grou
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	res := &GroupListResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -159,7 +161,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*Grou
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -173,27 +175,31 @@ type FindGroupByNameOptions struct {
 }
 
 func FindGroupByName(ctx context.Context, cli *azuredevops.Client, opts FindGroupByNameOptions) (*GroupResponse, error) {
-	var continuationToken *string
-	opts.ListOptions.ContinuationToken = continuationToken
-	for {
-		groups, err := List(ctx, cli, opts.ListOptions)
-		if err != nil {
-			return nil, err
-		}
-		for _, group := range groups.Value {
-			domain := path.Base(group.Domain)
-			if strings.EqualFold(group.DisplayName, opts.GroupName) &&
-				(opts.ProjectID == nil || //if projectID is not provided, return the first group with the name - organization is from the api endpoint
-					strings.EqualFold(domain, helpers.String(opts.ProjectID))) {
-				return &group, nil
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[GroupResponse], error) {
+			lo := opts.ListOptions
+			lo.ContinuationToken = nil
+			if len(pr.ContinuationToken) > 0 {
+				lo.ContinuationToken = helpers.StringPtr(pr.ContinuationToken)
 			}
-		}
-		if groups.ContinuationToken == nil {
-			break
-		}
-		opts.ListOptions.ContinuationToken = groups.ContinuationToken
-	}
-	return nil, nil
+			res, err := List(ctx, cli, lo)
+			if err != nil {
+				return nil, err
+			}
+
+			return &azuredevops.Page[GroupResponse]{
+				Count:             res.Count,
+				Value:             res.Value,
+				ContinuationToken: helpers.String(res.ContinuationToken),
+			}, nil
+		})
+
+	return pager.Find(ctx, func(group GroupResponse) bool {
+		domain := path.Base(group.Domain)
+		return strings.EqualFold(group.DisplayName, opts.GroupName) &&
+			(opts.ProjectID == nil || //if projectID is not provided, return the first group with the name - organization is from the api endpoint
+				strings.EqualFold(domain, helpers.String(opts.ProjectID)))
+	})
 }
 
 // Create a new Azure DevOps group.
@@ -236,7 +242,7 @@ func Create[T GroupData](ctx context.Context, cli *azuredevops.Client, opts Crea
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusCreated),
 		},
 	})
 
@@ -273,12 +279,13 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 
@@ -0,0 +1,76 @@
+package groups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/graphs/descriptors"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestGroupsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	parent := srv.AddGroup(fake.Organization, "Everyone")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	scope, err := descriptors.GetDescriptor(ctx, cli, descriptors.GetOptions{Organization: fake.Organization, ResourceID: projectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grp, err := Create(ctx, cli, CreateOptions[GroupDescription]{
+		Organization:     fake.Organization,
+		ScopeDescriptor:  scope,
+		GroupDescriptors: []string{fake.String(parent["descriptor"])},
+		GroupData:        GroupDescription{DisplayName: "Developers", Description: "project developers"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !srv.IsMember(fake.Organization, grp.Descriptor, fake.String(parent["descriptor"])) {
+		t.Fatalf("expected group to be member of the parent group")
+	}
+
+	if _, err := Create(ctx, cli, CreateOptions[GroupDescription]{
+		Organization:    fake.Organization,
+		ScopeDescriptor: scope,
+		GroupData:       GroupDescription{DisplayName: "Developers"},
+	}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	found, err := FindGroupByName(ctx, cli, FindGroupByNameOptions{
+		ListOptions: ListOptions{Organization: fake.Organization},
+		GroupName:   "developers",
+		ProjectID:   helpers.StringPtr(projectId),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.Descriptor != grp.Descriptor {
+		t.Fatalf("expected group %s, got: %v", grp.Descriptor, found)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, GroupDescriptor: grp.Descriptor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "project developers" {
+		t.Fatalf("unexpected group: %+v", got)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, GroupDescriptor: grp.Descriptor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, GroupDescriptor: grp.Descriptor}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
@@ -58,6 +58,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Member
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	res := &MembershipStateResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -65,7 +66,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Member
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -100,12 +101,13 @@ func CheckMembership(ctx context.Context, cli *azuredevops.Client, opts CheckMem
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	return err
@@ -140,7 +142,7 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CheckMembershipOp
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return err
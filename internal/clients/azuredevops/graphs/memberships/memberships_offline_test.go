@@ -0,0 +1,43 @@
+package memberships
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestMembershipsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	usr := srv.AddUser(fake.Organization, "jane@fake.onmicrosoft.com")
+	grp := srv.AddGroup(fake.Organization, "Readers")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	state, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, SubjectDescriptor: fake.String(usr["descriptor"])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Active {
+		t.Fatalf("expected active membership state")
+	}
+
+	opts := CheckMembershipOptions{
+		Organization:        fake.Organization,
+		SubjectDescriptor:   fake.String(usr["descriptor"]),
+		ContainerDescriptor: fake.String(grp["descriptor"]),
+	}
+	if err := CheckMembership(ctx, cli, opts); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	if err := Create(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckMembership(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -69,7 +69,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*UserRe
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &UserResource{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -77,7 +76,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*UserRe
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if err != nil {
@@ -129,7 +128,6 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ListResponse{
 		Value: []UserResource{},
 	}
@@ -150,7 +148,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 			return nil
 		},
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if err != nil {
@@ -169,24 +167,28 @@ type FindUserByNameOptions struct {
 }
 
 func FindUserByName(ctx context.Context, cli *azuredevops.Client, opts FindUserByNameOptions) (*UserResource, error) {
-	var continuationToken *string
-	opts.ListOptions.ContinuationToken = continuationToken
-	for {
-		users, err := List(ctx, cli, opts.ListOptions)
-		if err != nil {
-			return nil, err
-		}
-		for _, user := range users.Value {
-			if strings.EqualFold(user.PrincipalName, opts.PrincipalName) || strings.EqualFold(user.DisplayName, opts.PrincipalName) {
-				return &user, nil
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[UserResource], error) {
+			lo := opts.ListOptions
+			lo.ContinuationToken = nil
+			if len(pr.ContinuationToken) > 0 {
+				lo.ContinuationToken = helpers.StringPtr(pr.ContinuationToken)
 			}
-		}
-		if users.ContinuationToken == nil {
-			break
-		}
-		opts.ListOptions.ContinuationToken = users.ContinuationToken
-	}
-	return nil, nil
+			res, err := List(ctx, cli, lo)
+			if err != nil {
+				return nil, err
+			}
+
+			return &azuredevops.Page[UserResource]{
+				Count:             res.Count,
+				Value:             res.Value,
+				ContinuationToken: helpers.String(res.ContinuationToken),
+			}, nil
+		})
+
+	return pager.Find(ctx, func(user UserResource) bool {
+		return strings.EqualFold(user.PrincipalName, opts.PrincipalName) || strings.EqualFold(user.DisplayName, opts.PrincipalName)
+	})
 }
 
 type Identifiers interface {
@@ -240,7 +242,6 @@ func Create[T Identifiers](ctx context.Context, cli *azuredevops.Client, opts Cr
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &UserResource{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -248,7 +249,7 @@ func Create[T Identifiers](ctx context.Context, cli *azuredevops.Client, opts Cr
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusCreated),
 		},
 	})
 	if err != nil {
@@ -287,13 +288,11 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
-
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 	if err != nil {
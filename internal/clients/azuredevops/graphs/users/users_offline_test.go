@@ -0,0 +1,73 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestUsersOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	grp := srv.AddGroup(fake.Organization, "Readers")
+	groupDescriptor := fake.String(grp["descriptor"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	usr, err := Create(ctx, cli, CreateOptions[PrincipalName]{
+		Organization:     fake.Organization,
+		GroupDescriptors: []string{groupDescriptor},
+		Identifier:       PrincipalName{PrincipalName: "jane@fake.onmicrosoft.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !srv.IsMember(fake.Organization, usr.Descriptor, groupDescriptor) {
+		t.Fatalf("expected user to be member of the group")
+	}
+
+	// Adding the same principal again returns the existing user.
+	again, err := Create(ctx, cli, CreateOptions[PrincipalName]{
+		Organization: fake.Organization,
+		Identifier:   PrincipalName{PrincipalName: "JANE@fake.onmicrosoft.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Descriptor != usr.Descriptor {
+		t.Fatalf("expected user %s, got: %s", usr.Descriptor, again.Descriptor)
+	}
+
+	found, err := FindUserByName(ctx, cli, FindUserByNameOptions{
+		ListOptions:   ListOptions{Organization: fake.Organization},
+		PrincipalName: "jane@fake.onmicrosoft.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.Descriptor != usr.Descriptor {
+		t.Fatalf("expected user %s, got: %v", usr.Descriptor, found)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, UserDescriptor: usr.Descriptor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PrincipalName != "jane@fake.onmicrosoft.com" {
+		t.Fatalf("unexpected user: %s", got.PrincipalName)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, UserDescriptor: usr.Descriptor}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, UserDescriptor: usr.Descriptor}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+	if srv.IsMember(fake.Organization, usr.Descriptor, groupDescriptor) {
+		t.Fatalf("expected membership to be removed with the user")
+	}
+}
@@ -131,7 +131,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Identi
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &IdentityResponse{
 		Value: []Identity{},
 	}
@@ -141,7 +140,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Identi
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -0,0 +1,64 @@
+package identities
+
+import (
+	"context"
+	"testing"
+
+	teamprojects "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/graphs/descriptors"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/graphs/groups"
+)
+
+func TestIdentitiesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	scope, err := descriptors.GetDescriptor(ctx, cli, descriptors.GetOptions{Organization: fake.Organization, ResourceID: fake.String(prj["id"])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groups.Create(ctx, cli, groups.CreateOptions[groups.GroupDescription]{
+		Organization:    fake.Organization,
+		ScopeDescriptor: scope,
+		GroupData:       groups.GroupDescription{DisplayName: "Developers"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	teamProject := &teamprojects.TeamProject{}
+	teamProject.Spec.Name = "demo"
+	teamProject.Status.Id = fake.String(prj["id"])
+
+	tests := []IdentityParams{
+		{Type: BuildService, Project: teamProject},
+		{Type: AzureGroup, Project: teamProject, Name: "Developers"},
+	}
+	for _, params := range tests {
+		res, err := Get(ctx, cli, GetOptions{IdentityParams: params, Organization: fake.Organization})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := res.IdentityMatch(&params)
+		if err != nil {
+			t.Fatalf("%s: %v", params.Type, err)
+		}
+		if len(id.Descriptor) == 0 {
+			t.Fatalf("%s: expected identity descriptor", params.Type)
+		}
+	}
+
+	params := IdentityParams{Type: AzureGroup, Project: teamProject, Name: "Missing"}
+	res, err := Get(ctx, cli, GetOptions{IdentityParams: params, Organization: fake.Organization})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := res.IdentityMatch(&params); err == nil {
+		t.Fatalf("expected identity not found")
+	}
+}
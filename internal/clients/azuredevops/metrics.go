@@ -0,0 +1,103 @@
+package azuredevops
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "azuredevops"
+	metricsSubsystem = "api"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "requests_total",
+		Help:      "Total number of requests sent to the Azure DevOps REST API.",
+	}, []string{"uri_key", "area", "organization", "method", "code"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests sent to the Azure DevOps REST API.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"uri_key", "area", "organization", "method"})
+)
+
+func init() {
+	// Exposed by the controller-runtime metrics server.
+	ctrlmetrics.Registry.MustRegister(apiRequests, apiRequestDuration)
+}
+
+// metricsTransport is an http.RoundTripper that records the count, the
+// status code and the latency of each request sent to Azure DevOps.
+type metricsTransport struct {
+	next   http.RoundTripper
+	uriMap map[URIKey]string
+}
+
+func newMetricsTransport(next http.RoundTripper, uriMap map[URIKey]string) *metricsTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &metricsTransport{
+		next:   next,
+		uriMap: uriMap,
+	}
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := uriKeyOf(t.uriMap, req)
+	p := strings.TrimPrefix(req.URL.Path, basePath(t.uriMap[key]))
+	org := strings.ToLower(organizationFromPath(p))
+	area := areaFromPath(p)
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	apiRequestDuration.WithLabelValues(string(key), area, org, req.Method).
+		Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	apiRequests.WithLabelValues(string(key), area, org, req.Method, code).Inc()
+
+	return res, err
+}
+
+// uriKeyOf returns the URIKey whose base URL (the longest matching) serves
+// the request; in CollectionPathMode all the keys share the same URL and
+// Default is used.
+func uriKeyOf(uriMap map[URIKey]string, req *http.Request) URIKey {
+	uri := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	res, size := Default, 0
+	for _, k := range []URIKey{Default, Feeds, Vssps} {
+		base := strings.TrimSuffix(uriMap[k], "/")
+		if len(base) > size && strings.HasPrefix(uri, base) {
+			res, size = k, len(base)
+		}
+	}
+	return res
+}
+
+// areaFromPath returns the API area of the URL path (without the base
+// URL path), that is the segment following '_apis' (i.e. 'git').
+func areaFromPath(p string) string {
+	segs := strings.Split(strings.Trim(p, "/"), "/")
+	for i, el := range segs {
+		if strings.EqualFold(el, "_apis") && i+1 < len(segs) {
+			return strings.ToLower(segs[i+1])
+		}
+	}
+	return "other"
+}
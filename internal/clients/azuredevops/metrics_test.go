@@ -0,0 +1,79 @@
+package azuredevops
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRecordRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics-org/_apis/git/repositories/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	if err := fireGet(t, cli, srv.URL+"/Metrics-Org/demo/_apis/git/repositories"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fireGet(t, cli, srv.URL+"/metrics-org/_apis/git/repositories/missing"); err == nil {
+		t.Fatal("expected not found error")
+	}
+
+	if got := testutil.ToFloat64(apiRequests.WithLabelValues("default", "git", "metrics-org", http.MethodGet, "200")); got != 1 {
+		t.Fatalf("expected 1 successful request, got: %v", got)
+	}
+	if got := testutil.ToFloat64(apiRequests.WithLabelValues("default", "git", "metrics-org", http.MethodGet, "404")); got != 1 {
+		t.Fatalf("expected 1 failed request, got: %v", got)
+	}
+}
+
+func TestMetricsCountThrottlingRetries(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls < 2 {
+			w.Header().Set(HeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := NewClient(ClientOptions{
+		UriMap: &map[URIKey]string{
+			Default: srv.URL,
+			Vssps:   srv.URL + "/vssps",
+		},
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: 100 * time.Millisecond,
+	})
+	if err := fireGet(t, cli, srv.URL+"/vssps/retry-metrics-org/_apis/graph/users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(apiRequests.WithLabelValues("vssps", "graph", "retry-metrics-org", http.MethodGet, "429")); got != 1 {
+		t.Fatalf("expected 1 throttled request, got: %v", got)
+	}
+}
+
+func TestAreaFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/org/project/_apis/git/repositories": "git",
+		"/org/_apis/Projects":                 "projects",
+		"/org/_apis":                          "other",
+		"/org/project/_git/repo":              "other",
+	}
+	for p, want := range tests {
+		if got := areaFromPath(p); got != want {
+			t.Errorf("%s: expected %q, got %q", p, want, got)
+		}
+	}
+}
@@ -88,7 +88,6 @@ func (c *Client) GetOperation(ctx context.Context, opts GetOperationOpts) (*Oper
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &APIError{}
 	val := &Operation{}
 
 	err = httplib.Fire(c.httpClient, req, httplib.FireOptions{
@@ -96,7 +95,7 @@ func (c *Client) GetOperation(ctx context.Context, opts GetOperationOpts) (*Oper
 		AuthMethod:      c.authMethod,
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			DecodeError(http.StatusOK),
 		},
 	})
 
@@ -0,0 +1,228 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/lucasepe/httplib"
+)
+
+const (
+	HeaderContinuationToken = "X-Ms-Continuationtoken"
+	DefaultPageSize         = 100
+)
+
+// PagingMode tells how an endpoint splits its results in pages.
+type PagingMode int
+
+const (
+	// ContinuationTokenPaging pages using the 'continuationToken' query
+	// parameter and the 'X-Ms-Continuationtoken' response header.
+	ContinuationTokenPaging PagingMode = iota
+	// TopSkipPaging pages using the '$top' and '$skip' query parameters.
+	TopSkipPaging
+)
+
+// PageRequest identifies the page to fetch.
+type PageRequest struct {
+	Mode              PagingMode
+	Top               int
+	Skip              int
+	ContinuationToken string
+}
+
+// Params returns the query parameters needed to fetch the page.
+func (pr PageRequest) Params() []string {
+	var params []string
+	if pr.Top > 0 {
+		params = append(params, "$top", strconv.Itoa(pr.Top))
+	}
+	switch pr.Mode {
+	case TopSkipPaging:
+		if pr.Skip > 0 {
+			params = append(params, "$skip", strconv.Itoa(pr.Skip))
+		}
+	default:
+		if len(pr.ContinuationToken) > 0 {
+			params = append(params, "continuationToken", pr.ContinuationToken)
+		}
+	}
+	return params
+}
+
+// Page is a single page of a list response.
+type Page[T any] struct {
+	Count             int    `json:"count"`
+	Value             []T    `json:"value"`
+	ContinuationToken string `json:"continuationToken,omitempty"`
+}
+
+// PageFunc fetches the requested page.
+type PageFunc[T any] func(ctx context.Context, pr PageRequest) (*Page[T], error)
+
+// Pager iterates over the pages of a list endpoint.
+type Pager[T any] struct {
+	fetch PageFunc[T]
+	next  PageRequest
+	done  bool
+}
+
+// NewPager returns a Pager that fetches pages of top items (0 means
+// server default) using the specified paging mode.
+func NewPager[T any](mode PagingMode, top int, fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		fetch: fetch,
+		next: PageRequest{
+			Mode: mode,
+			Top:  top,
+		},
+	}
+}
+
+// More returns true if there are more pages to fetch.
+func (p *Pager[T]) More() bool {
+	return !p.done
+}
+
+// NextPage fetches the next page of items.
+func (p *Pager[T]) NextPage(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	page, err := p.fetch(ctx, p.next)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		p.done = true
+		return nil, nil
+	}
+
+	switch p.next.Mode {
+	case TopSkipPaging:
+		if len(page.Value) == 0 || p.next.Top <= 0 || len(page.Value) < p.next.Top {
+			p.done = true
+		}
+		p.next.Skip += len(page.Value)
+	default:
+		// Stop also when the server returns the same token again
+		// to avoid looping forever on misbehaving endpoints.
+		if len(page.ContinuationToken) == 0 || page.ContinuationToken == p.next.ContinuationToken {
+			p.done = true
+		}
+		p.next.ContinuationToken = page.ContinuationToken
+	}
+
+	return page.Value, nil
+}
+
+// All fetches all the remaining pages and returns their items.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	all := []T{}
+	for p.More() {
+		items, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// Find returns the first item that satisfies match, fetching only
+// the pages needed to find it. It returns nil if no item matches.
+func (p *Pager[T]) Find(ctx context.Context, match func(el T) bool) (*T, error) {
+	for p.More() {
+		items, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if match(items[i]) {
+				return &items[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// FetchPageOptions describes the list endpoint to call.
+type FetchPageOptions struct {
+	// URIKey selects the base URL (default: Default).
+	URIKey URIKey
+	// Path of the list endpoint.
+	Path string
+	// Params are the query parameters (api-version included).
+	Params []string
+	// Page is the page to fetch.
+	Page PageRequest
+	// Validators overrides the default response validators.
+	Validators []httplib.HandleResponseFunc
+}
+
+// FetchPage calls a list endpoint returning the standard
+// '{ "count": n, "value": [...] }' envelope and reads the continuation
+// token from the 'X-Ms-Continuationtoken' header.
+func FetchPage[T any](ctx context.Context, cli *Client, opts FetchPageOptions) (*Page[T], error) {
+	uriKey := opts.URIKey
+	if len(uriKey) == 0 {
+		uriKey = Default
+	}
+
+	var params []string
+	params = append(params, opts.Params...)
+	params = append(params, opts.Page.Params()...)
+
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(uriKey),
+		Path:    opts.Path,
+		Params:  params,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	validators := opts.Validators
+	if len(validators) == 0 {
+		validators = []httplib.HandleResponseFunc{
+			DecodeError(http.StatusOK),
+		}
+	}
+
+	val := &Page[T]{Value: []T{}}
+
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod: cli.AuthMethod(),
+		Verbose:    cli.Verbose(),
+		ResponseHandler: func(res *http.Response) error {
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				return err
+			}
+			if err = json.Unmarshal(data, val); err != nil {
+				return err
+			}
+
+			if token := res.Header.Get(HeaderContinuationToken); len(token) > 0 {
+				val.ContinuationToken = token
+			}
+			return nil
+		},
+		Validators: validators,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
@@ -0,0 +1,151 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type pagerTestItem struct {
+	Name string `json:"name"`
+}
+
+func pagerTestItems(names ...string) []pagerTestItem {
+	res := make([]pagerTestItem, len(names))
+	for i, el := range names {
+		res[i] = pagerTestItem{Name: el}
+	}
+	return res
+}
+
+func writePage(w http.ResponseWriter, items []pagerTestItem) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"count": len(items),
+		"value": items,
+	})
+}
+
+func TestPagerContinuationToken(t *testing.T) {
+	pages := map[string][]pagerTestItem{
+		"":   pagerTestItems("a", "b"),
+		"t1": pagerTestItems("c", "d"),
+		"t2": pagerTestItems("e"),
+	}
+	next := map[string]string{"": "t1", "t1": "t2"}
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		token := r.URL.Query().Get("continuationToken")
+		if tok, ok := next[token]; ok {
+			w.Header().Set(HeaderContinuationToken, tok)
+		}
+		writePage(w, pages[token])
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	pager := NewPager(ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr PageRequest) (*Page[pagerTestItem], error) {
+			return FetchPage[pagerTestItem](ctx, cli, FetchPageOptions{
+				Path: "pager-org/_apis/items",
+				Page: pr,
+			})
+		})
+
+	all, err := pager.All(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 items, got: %d", len(all))
+	}
+	if all[4].Name != "e" {
+		t.Fatalf("expected last item 'e', got: %s", all[4].Name)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 calls, got: %d", got)
+	}
+}
+
+func TestPagerStopsOnRepeatedToken(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(HeaderContinuationToken, "same")
+		writePage(w, pagerTestItems("a"))
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	pager := NewPager(ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr PageRequest) (*Page[pagerTestItem], error) {
+			return FetchPage[pagerTestItem](ctx, cli, FetchPageOptions{
+				Path: "pager-org/_apis/items",
+				Page: pr,
+			})
+		})
+
+	if _, err := pager.All(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 calls, got: %d", got)
+	}
+}
+
+func TestPagerTopSkipFind(t *testing.T) {
+	items := pagerTestItems("a", "b", "c", "d", "e", "f", "g")
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		top, _ := strconv.Atoi(r.URL.Query().Get("$top"))
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		end := min(skip+top, len(items))
+		writePage(w, items[min(skip, len(items)):end])
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	newPager := func() *Pager[pagerTestItem] {
+		return NewPager(TopSkipPaging, 3,
+			func(ctx context.Context, pr PageRequest) (*Page[pagerTestItem], error) {
+				return FetchPage[pagerTestItem](ctx, cli, FetchPageOptions{
+					Path: "pager-org/_apis/items",
+					Page: pr,
+				})
+			})
+	}
+
+	res, err := newPager().Find(context.TODO(), func(el pagerTestItem) bool {
+		return el.Name == "e"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res == nil || res.Name != "e" {
+		t.Fatalf("expected to find 'e', got: %v", res)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 calls, got: %d", got)
+	}
+
+	res, err = newPager().Find(context.TODO(), func(el pagerTestItem) bool {
+		return el.Name == "z"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != nil {
+		t.Fatalf("expected no match, got: %v", res)
+	}
+	if got := atomic.LoadInt32(&calls); got != 5 {
+		t.Fatalf("expected 5 calls, got: %d", got)
+	}
+}
@@ -0,0 +1,351 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/lucasepe/httplib"
+)
+
+// Process types of the build definitions.
+const (
+	ProcessDesigner = 1
+	ProcessYaml     = 2
+)
+
+// Repository types of the build definitions.
+const (
+	DefinitionRepositoryTfsGit           = "TfsGit"
+	DefinitionRepositoryGitHub           = "GitHub"
+	DefinitionRepositoryGitHubEnterprise = "GitHubEnterprise"
+)
+
+// DefinitionRepositoryType returns the build definition repository type
+// of the pipeline repository type.
+func DefinitionRepositoryType(t BuildRepositoryType) string {
+	switch t {
+	case BuildRepositoryGitHub:
+		return DefinitionRepositoryGitHub
+	case BuildRepositoryGitHubEnterprise:
+		return DefinitionRepositoryGitHubEnterprise
+	default:
+		return DefinitionRepositoryTfsGit
+	}
+}
+
+// The repository of a build definition; the fields not declared here
+// (properties, clean, checkoutSubmodules...) are preserved as read.
+type DefinitionRepository struct {
+	Id            *string `json:"id,omitempty"`
+	Name          *string `json:"name,omitempty"`
+	Type          *string `json:"type,omitempty"`
+	DefaultBranch *string `json:"defaultBranch,omitempty"`
+	Url           *string `json:"url,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+type definitionRepository DefinitionRepository
+
+// The JSON fields declared by DefinitionRepository.
+var definitionRepositoryFields = []string{"id", "name", "type", "defaultBranch", "url"}
+
+func (r *DefinitionRepository) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*definitionRepository)(r)); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, definitionRepositoryFields)
+	r.extra = extra
+	return err
+}
+
+func (r DefinitionRepository) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(definitionRepository(r))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(data, r.extra)
+}
+
+// Trigger types of the build definitions.
+const (
+	TriggerContinuousIntegration = "continuousIntegration"
+	TriggerPullRequest           = "pullRequest"
+)
+
+// Settings sources of the triggers: the YAML pipelines read them from
+// the YAML file unless overridden by the definition.
+const (
+	SettingsSourceDefinition = 1
+	SettingsSourceProcess    = 2
+)
+
+// DefinitionTrigger is a trigger of a build definition. The triggers
+// have type specific fields (filters, schedules, forks...): they are
+// kept as read and changed by key.
+type DefinitionTrigger map[string]any
+
+// TriggerType returns the type of the trigger (i.e. 'continuousIntegration').
+func (t DefinitionTrigger) TriggerType() string {
+	res, _ := t["triggerType"].(string)
+	return res
+}
+
+// A variable of a build definition; the values of the secret
+// variables are never returned.
+type DefinitionVariable struct {
+	Value         *string `json:"value,omitempty"`
+	IsSecret      bool    `json:"isSecret,omitempty"`
+	AllowOverride bool    `json:"allowOverride,omitempty"`
+}
+
+// The agent pool of a queue.
+type AgentPoolReference struct {
+	Id       *int    `json:"id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	IsHosted *bool   `json:"isHosted,omitempty"`
+}
+
+// The default agent queue of a build definition.
+type AgentQueueReference struct {
+	Id   *int                `json:"id,omitempty"`
+	Name *string             `json:"name,omitempty"`
+	Pool *AgentPoolReference `json:"pool,omitempty"`
+}
+
+// A retention rule of the runs of a build definition.
+type RetentionRule struct {
+	Branches              []string `json:"branches,omitempty"`
+	Artifacts             []string `json:"artifacts,omitempty"`
+	ArtifactTypesToDelete []string `json:"artifactTypesToDelete,omitempty"`
+	DaysToKeep            int      `json:"daysToKeep"`
+	MinimumToKeep         int      `json:"minimumToKeep"`
+	DeleteBuildRecord     bool     `json:"deleteBuildRecord"`
+	DeleteTestResults     bool     `json:"deleteTestResults"`
+}
+
+// The process of a build definition: the YAML file of the YAML pipelines.
+// The fields not declared here (the phases of the designer pipelines...)
+// are preserved as read.
+type DefinitionProcess struct {
+	Type         int     `json:"type"`
+	YamlFilename *string `json:"yamlFilename,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+type definitionProcess DefinitionProcess
+
+// The JSON fields declared by DefinitionProcess.
+var definitionProcessFields = []string{"type", "yamlFilename"}
+
+func (p *DefinitionProcess) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*definitionProcess)(p)); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, definitionProcessFields)
+	p.extra = extra
+	return err
+}
+
+func (p DefinitionProcess) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(definitionProcess(p))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(data, p.extra)
+}
+
+// BuildDefinition is the full definition of a pipeline.
+//
+// The definitions are updated replacing them as a whole: the fields not
+// declared here (options, demands, job settings...) are preserved as read.
+type BuildDefinition struct {
+	Id             *int                          `json:"id,omitempty"`
+	Name           string                        `json:"name"`
+	Path           string                        `json:"path,omitempty"`
+	Revision       *int                          `json:"revision,omitempty"`
+	Repository     *DefinitionRepository         `json:"repository,omitempty"`
+	Process        *DefinitionProcess            `json:"process,omitempty"`
+	Triggers       []DefinitionTrigger           `json:"triggers,omitempty"`
+	Variables      map[string]DefinitionVariable `json:"variables,omitempty"`
+	Queue          *AgentQueueReference          `json:"queue,omitempty"`
+	BadgeEnabled   *bool                         `json:"badgeEnabled,omitempty"`
+	RetentionRules []RetentionRule               `json:"retentionRules,omitempty"`
+	Url            *string                       `json:"url,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+type buildDefinition BuildDefinition
+
+// The JSON fields declared by BuildDefinition.
+var definitionFields = []string{
+	"id", "name", "path", "revision", "repository", "process",
+	"triggers", "variables", "queue", "badgeEnabled", "retentionRules", "url",
+}
+
+func (d *BuildDefinition) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*buildDefinition)(d)); err != nil {
+		return err
+	}
+	extra, err := unmarshalExtra(data, definitionFields)
+	d.extra = extra
+	return err
+}
+
+func (d BuildDefinition) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(buildDefinition(d))
+	if err != nil {
+		return nil, err
+	}
+	return marshalExtra(data, d.extra)
+}
+
+// unmarshalExtra returns the fields of the JSON object not in the
+// declared ones.
+func unmarshalExtra(data []byte, declared []string) (map[string]json.RawMessage, error) {
+	res := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	for _, key := range declared {
+		delete(res, key)
+	}
+	return res, nil
+}
+
+// marshalExtra adds the extra fields, unless already set, to the
+// marshaled JSON object.
+func marshalExtra(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+	res := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	for key, val := range extra {
+		if _, ok := res[key]; !ok {
+			res[key] = val
+		}
+	}
+	return json.Marshal(res)
+}
+
+func getDefinitionsAPIVersion(cli *azuredevops.Client) (apiVersionParams []string, isNone bool) {
+	if cli.ApiVersionConfig != nil {
+		apiVersion := cli.ApiVersionConfig.Definitions
+		if apiVersion != nil {
+			if strings.EqualFold(*apiVersion, "none") {
+				apiVersionParams = nil
+				isNone = true
+			} else {
+				apiVersionParams = []string{azuredevops.ApiVersionKey, helpers.String(apiVersion)}
+			}
+		}
+	}
+	return apiVersionParams, isNone
+}
+
+// Options for the GetDefinition function
+type GetDefinitionOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The definition (pipeline) ID
+	DefinitionId string
+}
+
+// GetDefinition gets the build definition of a pipeline.
+// GET https://dev.azure.com/{organization}/{project}/_apis/build/definitions/{definitionId}?api-version=7.0
+func GetDefinition(ctx context.Context, cli *azuredevops.Client, opts GetDefinitionOptions) (*BuildDefinition, error) {
+	apiVersionParams, isNone := getDefinitionsAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/definitions", opts.DefinitionId),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &BuildDefinition{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Options for the UpdateDefinition function
+type UpdateDefinitionOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The definition as read by GetDefinition with the changes;
+	// the revision must be the current one.
+	Definition *BuildDefinition
+}
+
+// UpdateDefinition replaces the build definition of a pipeline.
+// PUT https://dev.azure.com/{organization}/{project}/_apis/build/definitions/{definitionId}?api-version=7.0
+func UpdateDefinition(ctx context.Context, cli *azuredevops.Client, opts UpdateDefinitionOptions) (*BuildDefinition, error) {
+	apiVersionParams, isNone := getDefinitionsAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/definitions", strconv.Itoa(helpers.Int(opts.Definition.Id))),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Put(uri.String(), httplib.ToJSON(opts.Definition))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	val := &BuildDefinition{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod:      cli.AuthMethod(),
+		Verbose:         cli.Verbose(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
@@ -33,6 +33,7 @@ const (
 	BuildRepositoryGitHub                  BuildRepositoryType = "gitHub"
 	BuildRepositoryAzureReposGit           BuildRepositoryType = "azureReposGit"
 	BuildRepositoryAzureReposGitHyphenated BuildRepositoryType = "azureReposGitHyphenated"
+	BuildRepositoryGitHubEnterprise        BuildRepositoryType = "gitHubEnterprise"
 )
 
 type BuildRepository struct {
@@ -115,14 +116,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Pipeline{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	return val, err
@@ -167,7 +167,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Pipeli
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &Pipeline{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -175,7 +174,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Pipeli
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -244,7 +243,6 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ListPipelinesResponseValue{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -263,7 +261,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 			return nil
 		},
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -278,29 +276,33 @@ type FindOptions struct {
 
 // Find utility method to look for a specific pipeline.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*Pipeline, error) {
-	var continutationToken string
-	for {
-		top := int(30)
-		res, err := List(ctx, cli, ListOptions{
-			Organization:      opts.Organization,
-			Project:           opts.Project,
-			Top:               &top,
-			ContinuationToken: &continutationToken,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		for _, el := range res.Value {
-			if strings.EqualFold(el.Name, opts.Name) {
-				return &el, nil
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 30,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[Pipeline], error) {
+			res, err := List(ctx, cli, ListOptions{
+				Organization:      opts.Organization,
+				Project:           opts.Project,
+				Top:               &pr.Top,
+				ContinuationToken: &pr.ContinuationToken,
+			})
+			if err != nil {
+				return nil, err
 			}
-		}
 
-		continutationToken = *res.ContinuationToken
-		if continutationToken == "" {
-			break
-		}
+			return &azuredevops.Page[Pipeline]{
+				Count:             res.Count,
+				Value:             res.Value,
+				ContinuationToken: helpers.String(res.ContinuationToken),
+			}, nil
+		})
+
+	res, err := pager.Find(ctx, func(el Pipeline) bool {
+		return strings.EqualFold(el.Name, opts.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		return res, nil
 	}
 
 	return nil, &httplib.StatusError{
@@ -0,0 +1,190 @@
+package pipelines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestPipelinesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	repo := srv.Repository(fake.Organization, "demo", "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	for i := 0; i < 3; i++ {
+		_, err := Create(ctx, cli, CreateOptions{
+			Organization: fake.Organization,
+			Project:      projectId,
+			Pipeline: Pipeline{
+				Name: fmt.Sprintf("ci-%d", i),
+				Configuration: &PipelineConfiguration{
+					Type: ConfigurationYaml,
+					Path: helpers.StringPtr("azure-pipelines.yml"),
+					Repository: &BuildRepository{
+						Id:   fake.String(repo["id"]),
+						Name: fake.String(repo["name"]),
+						Type: BuildRepositoryAzureReposGit,
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Pipeline:     Pipeline{Name: "ci-0"},
+	})
+	if !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	top := 2
+	res, err := List(ctx, cli, ListOptions{Organization: fake.Organization, Project: projectId, Top: &top})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 2 || helpers.String(res.ContinuationToken) == "" {
+		t.Fatalf("expected a first page of 2 pipelines, got: %d (%s)", res.Count, helpers.String(res.ContinuationToken))
+	}
+
+	pip, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: projectId, Name: "ci-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pip == nil || pip.Id == nil {
+		t.Fatalf("expected pipeline 'ci-2', got: %v", pip)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		PipelineId:   fmt.Sprint(*pip.Id),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "ci-2" || got.Folder != "\\" {
+		t.Fatalf("unexpected pipeline: %s (folder: %s)", got.Name, got.Folder)
+	}
+
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, PipelineId: "999"}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestDefinitionsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	projectId := fake.String(srv.AddProject(fake.Organization, "demo")["id"])
+	repo := srv.Repository(fake.Organization, projectId, "demo")
+	other := srv.AddRepository(fake.Organization, projectId, "other")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	pip, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Pipeline: Pipeline{
+			Name: "ci",
+			Configuration: &PipelineConfiguration{
+				Type: ConfigurationYaml,
+				Path: helpers.StringPtr("azure-pipelines.yml"),
+				Repository: &BuildRepository{
+					Id:   fake.String(repo["id"]),
+					Name: fake.String(repo["name"]),
+					Type: BuildRepositoryAzureReposGit,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := fmt.Sprint(*pip.Id)
+
+	def, err := GetDefinition(ctx, cli, GetDefinitionOptions{Organization: fake.Organization, Project: projectId, DefinitionId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Path != "\\" || def.Process == nil || helpers.String(def.Process.YamlFilename) != "azure-pipelines.yml" ||
+		def.Repository == nil || helpers.String(def.Repository.Type) != DefinitionRepositoryTfsGit {
+		t.Fatalf("unexpected definition: %+v", def)
+	}
+
+	def.Name = "ci-renamed"
+	def.Path = "\\builds"
+	def.Process.YamlFilename = helpers.StringPtr("ci/pipeline.yml")
+	def.Process.extra = map[string]json.RawMessage{"phases": json.RawMessage(`[{"name":"build"}]`)}
+	def.Repository.Id = helpers.StringPtr(fake.String(other["id"]))
+	def.Repository.Name = helpers.StringPtr("other")
+	upd, err := UpdateDefinition(ctx, cli, UpdateDefinitionOptions{Organization: fake.Organization, Project: projectId, Definition: def})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upd.Name != "ci-renamed" || upd.Path != "\\builds" || helpers.Int(upd.Revision) != helpers.Int(def.Revision)+1 {
+		t.Fatalf("unexpected updated definition: %+v", upd)
+	}
+	// The fields not declared by BuildDefinition are sent back.
+	if _, ok := upd.extra["jobAuthorizationScope"]; !ok {
+		t.Fatalf("expected job authorization scope to be preserved, got: %v", upd.extra)
+	}
+	// And so are the nested ones.
+	if _, ok := upd.Repository.extra["properties"]; !ok || helpers.String(upd.Repository.Name) != "other" {
+		t.Fatalf("expected repository properties to be preserved, got: %+v", upd.Repository)
+	}
+	if _, ok := upd.Process.extra["phases"]; !ok || helpers.String(upd.Process.YamlFilename) != "ci/pipeline.yml" {
+		t.Fatalf("expected process phases to be preserved, got: %+v", upd.Process)
+	}
+	if len(upd.Triggers) != 1 || upd.Triggers[0].TriggerType() != TriggerContinuousIntegration {
+		t.Fatalf("expected triggers to be preserved, got: %v", upd.Triggers)
+	}
+
+	// The values of the secret variables are never returned.
+	upd.Variables = map[string]DefinitionVariable{
+		"env":   {Value: helpers.StringPtr("dev"), AllowOverride: true},
+		"token": {Value: helpers.StringPtr("s3cr3t"), IsSecret: true},
+	}
+	upd.BadgeEnabled = helpers.BoolPtr(true)
+	upd, err = UpdateDefinition(ctx, cli, UpdateDefinitionOptions{Organization: fake.Organization, Project: projectId, Definition: upd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(upd.Variables["env"].Value) != "dev" || !upd.Variables["env"].AllowOverride ||
+		upd.Variables["token"].Value != nil || !upd.Variables["token"].IsSecret || !helpers.Bool(upd.BadgeEnabled) {
+		t.Fatalf("unexpected variables: %+v", upd.Variables)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, PipelineId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "ci-renamed" || got.Folder != "\\builds" || helpers.String(got.Configuration.Path) != "ci/pipeline.yml" ||
+		got.Configuration.Repository.Id != fake.String(other["id"]) {
+		t.Fatalf("unexpected pipeline: %+v", got)
+	}
+
+	// The revision is stale.
+	if _, err := UpdateDefinition(ctx, cli, UpdateDefinitionOptions{Organization: fake.Organization, Project: projectId, Definition: def}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+	if _, err := GetDefinition(ctx, cli, GetDefinitionOptions{Organization: fake.Organization, Project: projectId, DefinitionId: "999"}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
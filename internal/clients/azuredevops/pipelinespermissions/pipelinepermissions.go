@@ -87,7 +87,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Resour
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ResourcePipelinePermissions{
 		AllPipelines: &Permission{},
 		Pipelines:    []PipelinePermission{},
@@ -99,7 +98,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Resour
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -152,7 +151,6 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ResourcePipelinePermissions{
 		AllPipelines: &Permission{},
 		Pipelines:    []PipelinePermission{},
@@ -163,7 +161,7 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -0,0 +1,68 @@
+package pipelinespermissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestPipelinePermissionsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	repo := srv.Repository(fake.Organization, "demo", "demo")
+	projectId, repoId := fake.String(prj["id"]), fake.String(repo["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	res, err := Get(ctx, cli, GetOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceType: "repository",
+		ResourceId:   repoId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AllPipelines.Authorized || len(res.Pipelines) != 0 {
+		t.Fatalf("expected no authorized pipelines, got: %+v", res)
+	}
+
+	res, err = Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceType: "repository",
+		ResourceId:   repoId,
+		ResourceAuthorization: &ResourcePipelinePermissions{
+			AllPipelines: &Permission{Authorized: true},
+			Pipelines: []PipelinePermission{
+				{Id: 7, Authorized: true},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.AllPipelines.Authorized {
+		t.Fatalf("expected all pipelines to be authorized")
+	}
+
+	res, err = Get(ctx, cli, GetOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		ResourceType: "repository",
+		ResourceId:   repoId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pipelines) != 1 || res.Pipelines[0].GetId() != "7" || !res.Pipelines[0].Authorized {
+		t.Fatalf("expected pipeline 7 to be authorized, got: %+v", res.Pipelines)
+	}
+	if res.Resource == nil || res.Resource.Id == nil || *res.Resource.Id != repoId {
+		t.Fatalf("unexpected resource: %+v", res.Resource)
+	}
+}
@@ -149,7 +149,6 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ListPolicies{
 		Value: []*PolicyBody{},
 	}
@@ -170,7 +169,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 			return nil
 		},
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -206,7 +205,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Policy
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PolicyBody{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -214,7 +212,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Policy
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if reflect.DeepEqual(*val, PolicyBody{}) {
@@ -252,14 +250,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PolicyBody{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	return val, err
@@ -295,14 +292,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PolicyBody{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -336,12 +332,11 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 	return err
@@ -357,18 +352,35 @@ type FindOptions struct {
 
 // Find utility method to look for a specific project.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*PolicyBody, error) {
-	policies, err := List(ctx, cli, ListOptions{
-		Organization: opts.Organization,
-		ProjectId:    opts.ProjectId,
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[*PolicyBody], error) {
+			lo := ListOptions{
+				Organization: opts.Organization,
+				ProjectId:    opts.ProjectId,
+			}
+			if len(pr.ContinuationToken) > 0 {
+				lo.ContinuationToken = helpers.StringPtr(pr.ContinuationToken)
+			}
+			res, err := List(ctx, cli, lo)
+			if err != nil {
+				return nil, err
+			}
+
+			return &azuredevops.Page[*PolicyBody]{
+				Count:             res.Count,
+				Value:             res.Value,
+				ContinuationToken: helpers.String(res.ContinuationToken),
+			}, nil
+		})
+
+	res, err := pager.Find(ctx, func(el *PolicyBody) bool {
+		return el.ID == opts.ConfigurationId
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	for _, policy := range policies.Value {
-		if policy.ID == opts.ConfigurationId {
-			return policy, nil
-		}
+	if res != nil {
+		return *res, nil
 	}
 
 	return nil, &httplib.StatusError{
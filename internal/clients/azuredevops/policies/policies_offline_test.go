@@ -0,0 +1,79 @@
+package policies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestPoliciesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	repo := srv.Repository(fake.Organization, "demo", "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	// Minimum number of reviewers.
+	body := &PolicyBody{
+		Type:       PolicyType{Id: "fa4e907d-c16b-4a4c-9dfa-4906e5d171dd"},
+		IsBlocking: true,
+		IsEnabled:  true,
+		Settings: PolicySettings{
+			MinimumApproverCount: 1,
+			Scope: []Scope{
+				{RefName: "refs/heads/main", MatchKind: "exact", RepositoryId: fake.String(repo["id"])},
+			},
+		},
+	}
+
+	pol, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, ProjectId: projectId, PolicyBody: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pol.ID == 0 || pol.Revision != 1 {
+		t.Fatalf("unexpected policy: %+v", pol)
+	}
+
+	if _, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, ProjectId: projectId, PolicyBody: &PolicyBody{}}); err == nil {
+		t.Fatalf("expected error creating a policy without type")
+	}
+
+	res, err := List(ctx, cli, ListOptions{Organization: fake.Organization, ProjectId: projectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 {
+		t.Fatalf("expected 1 policy, got: %d", res.Count)
+	}
+
+	body.Settings.MinimumApproverCount = 2
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization:    fake.Organization,
+		ProjectId:       projectId,
+		ConfigurationId: pol.ID,
+		PolicyBody:      body,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, ProjectId: projectId, ConfigurationId: pol.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Settings.MinimumApproverCount != 2 {
+		t.Fatalf("expected 2 approvers, got: %d", found.Settings.MinimumApproverCount)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, ProjectId: projectId, ConfigurationId: pol.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectId: projectId, ConfigurationId: pol.ID}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
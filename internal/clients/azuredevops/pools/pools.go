@@ -116,7 +116,6 @@ func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) ([]Tas
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := []TaskAgentPool{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -139,7 +138,7 @@ func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) ([]Tas
 		},
 
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
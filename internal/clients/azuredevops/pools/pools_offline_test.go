@@ -0,0 +1,40 @@
+package pools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestFindPoolsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	srv.AddPool(fake.Organization, "Default")
+	srv.AddPool(fake.Organization, "Azure Pipelines")
+
+	res, err := Find(context.TODO(), srv.Client(), FindOptions{
+		Organization: fake.Organization,
+		PoolName:     "default",
+		PoolType:     helpers.StringPtr("automation"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || helpers.String(res[0].Name) != "Default" {
+		t.Fatalf("expected pool 'Default', got: %+v", res)
+	}
+
+	res, err = Find(context.TODO(), srv.Client(), FindOptions{
+		Organization: fake.Organization,
+		PoolName:     "missing",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Fatalf("expected no pools, got: %d", len(res))
+	}
+}
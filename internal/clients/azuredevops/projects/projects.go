@@ -158,7 +158,6 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &ListProjectsResponseValue{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -177,7 +176,7 @@ func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*List
 			return nil
 		},
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -211,7 +210,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TeamPr
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &TeamProject{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -219,7 +217,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TeamPr
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if val != nil && reflect.DeepEqual(*val, TeamProject{}) {
@@ -257,14 +255,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &azuredevops.OperationReference{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -299,14 +296,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &azuredevops.OperationReference{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -339,14 +335,13 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) (*
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &azuredevops.OperationReference{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -360,30 +355,32 @@ type FindOptions struct {
 
 // Find utility method to look for a specific project.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*TeamProject, error) {
-	var continutationToken string
-	for {
-		top := int(30)
-		//filter := StateWellFormed
-		res, err := List(ctx, cli, ListOptions{
-			Organization: opts.Organization,
-			//StateFilter:       &filter,
-			Top:               &top,
-			ContinuationToken: &continutationToken,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		for _, el := range res.Value {
-			if strings.EqualFold(el.Name, opts.Name) {
-				return el, nil
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 30,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[*TeamProject], error) {
+			res, err := List(ctx, cli, ListOptions{
+				Organization:      opts.Organization,
+				Top:               &pr.Top,
+				ContinuationToken: &pr.ContinuationToken,
+			})
+			if err != nil {
+				return nil, err
 			}
-		}
 
-		continutationToken = *res.ContinuationToken
-		if continutationToken == "" {
-			break
-		}
+			return &azuredevops.Page[*TeamProject]{
+				Count:             res.Count,
+				Value:             res.Value,
+				ContinuationToken: helpers.String(res.ContinuationToken),
+			}, nil
+		})
+
+	res, err := pager.Find(ctx, func(el *TeamProject) bool {
+		return strings.EqualFold(el.Name, opts.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		return *res, nil
 	}
 
 	return nil, &httplib.StatusError{
@@ -0,0 +1,111 @@
+package projects
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestProjectsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.OperationPolls = 1
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	op, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		TeamProject: &TeamProject{
+			Name:        "demo",
+			Description: helpers.StringPtr("demo project"),
+			Visibility:  VisibilityPrivate,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cli.GetOperation(ctx, azuredevops.GetOperationOpts{Organization: fake.Organization, OperationId: op.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != azuredevops.StatusInProgress {
+		t.Fatalf("expected operation in progress, got: %s", res.Status)
+	}
+	res, err = cli.GetOperation(ctx, azuredevops.GetOperationOpts{Organization: fake.Organization, OperationId: op.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != azuredevops.StatusSucceeded {
+		t.Fatalf("expected operation succeeded, got: %s", res.Status)
+	}
+
+	prj, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Name: "demo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prj.State == nil || *prj.State != StateWellFormed {
+		t.Fatalf("expected well formed project, got: %v", prj.State)
+	}
+
+	if _, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		TeamProject:  &TeamProject{Name: "demo"},
+	}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	srv.OperationPolls = 0
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		ProjectId:    helpers.String(prj.Id),
+		TeamProject:  &TeamProject{Name: "demo", Description: helpers.StringPtr("updated")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	prj, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectId: helpers.String(prj.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(prj.Description) != "updated" {
+		t.Fatalf("expected updated description, got: %s", helpers.String(prj.Description))
+	}
+
+	if _, err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, ProjectId: helpers.String(prj.Id)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectId: helpers.String(prj.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestListProjectsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	for _, name := range []string{"one", "two", "three"} {
+		srv.AddProject(fake.Organization, name)
+	}
+
+	top := 2
+	res, err := List(context.TODO(), srv.Client(), ListOptions{Organization: fake.Organization, Top: &top})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 2 || helpers.String(res.ContinuationToken) == "" {
+		t.Fatalf("expected a first page of 2 projects, got: %d (%s)", res.Count, helpers.String(res.ContinuationToken))
+	}
+
+	prj, err := Find(context.TODO(), srv.Client(), FindOptions{Organization: fake.Organization, Name: "three"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prj.Name != "three" {
+		t.Fatalf("unexpected project: %s", prj.Name)
+	}
+}
@@ -2,9 +2,7 @@ package pullrequests
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"path"
 	"reflect"
@@ -149,50 +147,31 @@ func getAPIVersion(cli *azuredevops.Client) (apiVersionParams []string, isNone b
 // Retrieve all pull requests matching a specified criteria.
 // GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/pullrequests?api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListProjectsResponseValue, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-	uri, err := httplib.NewURLBuilder(
-		httplib.URLBuilderOptions{
-			BaseURL: cli.BaseURL(azuredevops.Default),
-			Path:    path.Join(opts.Organization, opts.ProjectId, "_apis/git/repositories", opts.RepositoryId, "pullrequests"),
-			Params:  apiVersionParams,
-		}).Build()
+	all, err := newPager(cli, opts).All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := httplib.Get(uri.String())
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
+	return &ListProjectsResponseValue{
+		Value: all,
+		Count: len(all),
+	}, nil
+}
 
-	apiErr := &azuredevops.APIError{}
-	val := &ListProjectsResponseValue{
-		Value: []*PullRequest{},
+func newPager(cli *azuredevops.Client, opts ListOptions) *azuredevops.Pager[*PullRequest] {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
 
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		AuthMethod: cli.AuthMethod(),
-		Verbose:    cli.Verbose(),
-		ResponseHandler: func(res *http.Response) error {
-			data, err := io.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-			if err = json.Unmarshal(data, val); err != nil {
-				return err
-			}
-			return nil
-		},
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
-
-	return val, err
+	return azuredevops.NewPager(azuredevops.TopSkipPaging, azuredevops.DefaultPageSize,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[*PullRequest], error) {
+			return azuredevops.FetchPage[*PullRequest](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   path.Join(opts.Organization, opts.ProjectId, "_apis/git/repositories", opts.RepositoryId, "pullrequests"),
+				Params: apiVersionParams,
+				Page:   pr,
+			})
+		})
 }
 
 type GetOptions struct {
@@ -224,7 +203,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*PullRe
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PullRequest{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -232,7 +210,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*PullRe
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if val != nil && reflect.DeepEqual(*val, PullRequest{}) {
@@ -272,14 +250,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PullRequest{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusCreated, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -326,14 +303,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PullRequest{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -356,19 +332,20 @@ type FindOptions struct {
 
 // Find utility method to look for a specific project.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*PullRequest, error) {
-	projects, err := List(ctx, cli, ListOptions{
+	pager := newPager(cli, ListOptions{
 		Organization: opts.Organization,
 		ProjectId:    opts.ProjectId,
 		RepositoryId: opts.RepositoryId,
 	})
+
+	res, err := pager.Find(ctx, func(el *PullRequest) bool {
+		return el.Title == opts.Title && el.SourceRefName == opts.SourceRefName && el.TargetRefName == opts.TargetRefName
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	for _, project := range projects.Value {
-		if project.Title == opts.Title && project.SourceRefName == opts.SourceRefName && project.TargetRefName == opts.TargetRefName {
-			return project, nil
-		}
+	if res != nil {
+		return *res, nil
 	}
 
 	return nil, &httplib.StatusError{
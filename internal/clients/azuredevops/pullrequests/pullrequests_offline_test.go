@@ -0,0 +1,89 @@
+package pullrequests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestPullRequestsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+	repo := srv.AddRepository(fake.Organization, projectId, "app")
+	repoId := fake.String(repo["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	for _, ref := range []string{"refs/heads/main", "refs/heads/feature"} {
+		_, err := repositories.CreatePush(ctx, cli, repositories.GitPushOptions{
+			Organization: fake.Organization,
+			Project:      projectId,
+			RepositoryId: repoId,
+			Push: &repositories.GitPush{
+				RefUpdates: &[]repositories.GitRefUpdate{{Name: helpers.StringPtr(ref)}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pr, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		ProjectId:    projectId,
+		RepositoryId: repoId,
+		PullRequest: &PullRequest{
+			Title:         "Add feature",
+			SourceRefName: "refs/heads/feature",
+			TargetRefName: "refs/heads/main",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != "active" {
+		t.Fatalf("expected active pull request, got: %s", pr.Status)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{
+		Organization:  fake.Organization,
+		ProjectId:     projectId,
+		RepositoryId:  repoId,
+		Title:         "Add feature",
+		SourceRefName: "refs/heads/feature",
+		TargetRefName: "refs/heads/main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.PullRequestId != pr.PullRequestId {
+		t.Fatalf("expected pull request %d, got: %d", pr.PullRequestId, found.PullRequestId)
+	}
+
+	id := strconv.Itoa(pr.PullRequestId)
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization:  fake.Organization,
+		ProjectId:     projectId,
+		RepositoryId:  repoId,
+		PullRequestId: id,
+		PullRequest:   &PullRequest{Status: "abandoned"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectId: projectId, RepositoryId: repoId, PullRequestId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != "abandoned" || pr.Title != "Add feature" {
+		t.Fatalf("unexpected pull request: %s (%s)", pr.Title, pr.Status)
+	}
+}
@@ -278,7 +278,6 @@ func Add(ctx context.Context, cli *azuredevops.Client, opts AddOptions) (*TaskAg
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &TaskAgentQueue{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -286,7 +285,7 @@ func Add(ctx context.Context, cli *azuredevops.Client, opts AddOptions) (*TaskAg
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -344,7 +343,6 @@ func FindByNames(ctx context.Context, cli *azuredevops.Client, opts FindByNamesO
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := []TaskAgentQueue{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -367,7 +365,7 @@ func FindByNames(ctx context.Context, cli *azuredevops.Client, opts FindByNamesO
 		},
 
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -422,7 +420,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TaskAg
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &TaskAgentQueue{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -430,7 +427,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TaskAg
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if val != nil && reflect.DeepEqual(*val, TaskAgentQueue{}) {
@@ -481,7 +478,7 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
@@ -0,0 +1,77 @@
+package queues
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestQueuesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	pool := srv.AddPool(fake.Organization, "Default")
+	projectId := fake.String(prj["id"])
+	poolId := pool["id"].(int)
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	q, err := Add(ctx, cli, AddOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Queue: &TaskAgentQueue{
+			Name: "builds",
+			Pool: &TaskAgentPoolReference{Id: helpers.IntPtr(poolId)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Pool == nil || q.Pool.Name != "Default" {
+		t.Fatalf("expected queue on pool 'Default', got: %+v", q.Pool)
+	}
+
+	_, err = Add(ctx, cli, AddOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Queue: &TaskAgentQueue{
+			Name: "orphan",
+			Pool: &TaskAgentPoolReference{Id: helpers.IntPtr(999)},
+		},
+	})
+	if err == nil {
+		t.Fatalf("expected error adding a queue on a missing pool")
+	}
+
+	res, err := FindByNames(ctx, cli, FindByNamesOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		QueueNames:   []string{"builds"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || helpers.Int(res[0].Id) != helpers.Int(q.Id) {
+		t.Fatalf("expected queue %d, got: %+v", helpers.Int(q.Id), res)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, QueueId: helpers.Int(q.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "builds" {
+		t.Fatalf("unexpected queue: %s", got.Name)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, Project: projectId, QueueId: helpers.Int(q.Id)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, QueueId: helpers.Int(q.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
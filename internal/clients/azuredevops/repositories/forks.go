@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/lucasepe/httplib"
+)
+
+// The key of a repository of any project of the organization.
+type GlobalGitRepositoryKey struct {
+	CollectionId *string `json:"collectionId,omitempty"`
+	ProjectId    *string `json:"projectId,omitempty"`
+	RepositoryId *string `json:"repositoryId,omitempty"`
+}
+
+// The progress of a fork sync request.
+type GitForkOperationStatusDetail struct {
+	// All the steps of the sync.
+	AllSteps []string `json:"allSteps,omitempty"`
+	// The current step, starting from 1.
+	CurrentStep *int `json:"currentStep,omitempty"`
+	// The error of the failed syncs.
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+}
+
+// A request to sync the refs of a fork with its source repository.
+type GitForkSyncRequest struct {
+	OperationId    *int                          `json:"operationId,omitempty"`
+	Source         *GlobalGitRepositoryKey       `json:"source,omitempty"`
+	Status         GitAsyncOperationStatus       `json:"status,omitempty"`
+	DetailedStatus *GitForkOperationStatusDetail `json:"detailedStatus,omitempty"`
+}
+
+// IsDone returns true if the sync is no longer queued or in progress.
+func (r *GitForkSyncRequest) IsDone() bool {
+	return r.Status != AsyncOperationQueued && r.Status != AsyncOperationInProgress
+}
+
+// Options for the ListForkSyncRequests function
+type ListForkSyncRequestsOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the fork.
+	RepositoryId string
+	// Includes the abandoned fork sync requests.
+	IncludeAbandoned bool
+}
+
+// ListForkSyncRequests lists the fork sync requests of a fork: the
+// first one copies the refs of the parent repository on creation.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryNameOrId}/forkSyncRequests?includeAbandoned={includeAbandoned}&api-version=7.0
+func ListForkSyncRequests(ctx context.Context, cli *azuredevops.Client, opts ListForkSyncRequestsOptions) ([]GitForkSyncRequest, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	params := append(apiVersionParams, "includeAbandoned", strconv.FormatBool(opts.IncludeAbandoned))
+
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/repositories", opts.RepositoryId, "forkSyncRequests"),
+		Params:  params,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &struct {
+		Count int                  `json:"count"`
+		Value []GitForkSyncRequest `json:"value,omitempty"`
+	}{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Value, nil
+}
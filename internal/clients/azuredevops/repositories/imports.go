@@ -0,0 +1,221 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/lucasepe/httplib"
+)
+
+// The states of the asynchronous Git operations: the import and the
+// fork sync requests.
+type GitAsyncOperationStatus string
+
+const (
+	AsyncOperationQueued     GitAsyncOperationStatus = "queued"
+	AsyncOperationInProgress GitAsyncOperationStatus = "inProgress"
+	AsyncOperationCompleted  GitAsyncOperationStatus = "completed"
+	AsyncOperationFailed     GitAsyncOperationStatus = "failed"
+	AsyncOperationAbandoned  GitAsyncOperationStatus = "abandoned"
+)
+
+// The external Git repository to import.
+type GitImportGitSource struct {
+	// The clone URL of the repository.
+	Url *string `json:"url,omitempty"`
+}
+
+// Parameters of an import request.
+type GitImportRequestParameters struct {
+	// The external Git repository.
+	GitSource *GitImportGitSource `json:"gitSource,omitempty"`
+	// The service endpoint with the credentials of the external repository.
+	ServiceEndpointId *string `json:"serviceEndpointId,omitempty"`
+	// Deletes the service endpoint when the import is done.
+	DeleteServiceEndpointAfterImportIsDone *bool `json:"deleteServiceEndpointAfterImportIsDone,omitempty"`
+}
+
+// The progress of an import request.
+type GitImportStatusDetail struct {
+	// All the steps of the import.
+	AllSteps []string `json:"allSteps,omitempty"`
+	// The current step, starting from 1.
+	CurrentStep *int `json:"currentStep,omitempty"`
+	// The error of the failed imports.
+	ErrorMessage *string `json:"errorMessage,omitempty"`
+}
+
+// A request to import an external Git repository into an empty repository.
+type GitImportRequest struct {
+	ImportRequestId *int                        `json:"importRequestId,omitempty"`
+	Parameters      *GitImportRequestParameters `json:"parameters,omitempty"`
+	Repository      *GitRepository              `json:"repository,omitempty"`
+	Status          GitAsyncOperationStatus     `json:"status,omitempty"`
+	DetailedStatus  *GitImportStatusDetail      `json:"detailedStatus,omitempty"`
+	Url             *string                     `json:"url,omitempty"`
+}
+
+// IsDone returns true if the import is no longer queued or in progress.
+func (r *GitImportRequest) IsDone() bool {
+	return r.Status != AsyncOperationQueued && r.Status != AsyncOperationInProgress
+}
+
+// Options for the CreateImportRequest function
+type CreateImportRequestOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the empty repository.
+	RepositoryId string
+	// (required) The parameters of the import.
+	Parameters *GitImportRequestParameters
+}
+
+// CreateImportRequest starts importing an external Git repository
+// into an empty repository.
+// POST https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/importRequests?api-version=7.0
+func CreateImportRequest(ctx context.Context, cli *azuredevops.Client, opts CreateImportRequestOptions) (*GitImportRequest, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/repositories", opts.RepositoryId, "importRequests"),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Post(uri.String(), httplib.ToJSON(&GitImportRequest{
+		Parameters: opts.Parameters,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	val := &GitImportRequest{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod:      cli.AuthMethod(),
+		Verbose:         cli.Verbose(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Options for the GetImportRequest function
+type GetImportRequestOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the repository.
+	RepositoryId string
+	// (required) The ID of the import request.
+	ImportRequestId int
+}
+
+// GetImportRequest gets an import request.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/importRequests/{importRequestId}?api-version=7.0
+func GetImportRequest(ctx context.Context, cli *azuredevops.Client, opts GetImportRequestOptions) (*GitImportRequest, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path: path.Join(opts.Organization, opts.Project, "_apis/git/repositories", opts.RepositoryId,
+			"importRequests", strconv.Itoa(opts.ImportRequestId)),
+		Params: apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &GitImportRequest{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Options for the ListImportRequests function
+type ListImportRequestsOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the repository.
+	RepositoryId string
+	// Includes the abandoned import requests.
+	IncludeAbandoned bool
+}
+
+// ListImportRequests lists the import requests of a repository.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/importRequests?includeAbandoned={includeAbandoned}&api-version=7.0
+func ListImportRequests(ctx context.Context, cli *azuredevops.Client, opts ListImportRequestsOptions) ([]GitImportRequest, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	params := append(apiVersionParams, "includeAbandoned", strconv.FormatBool(opts.IncludeAbandoned))
+
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/repositories", opts.RepositoryId, "importRequests"),
+		Params:  params,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &struct {
+		Count int                `json:"count"`
+		Value []GitImportRequest `json:"value,omitempty"`
+	}{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Value, nil
+}
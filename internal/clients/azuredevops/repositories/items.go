@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/lucasepe/httplib"
+)
+
+// An item (file or folder) of a git repository.
+type GitItem struct {
+	ObjectId      *string `json:"objectId,omitempty"`
+	GitObjectType *string `json:"gitObjectType,omitempty"`
+	Path          *string `json:"path,omitempty"`
+	IsFolder      *bool   `json:"isFolder,omitempty"`
+	Url           *string `json:"url,omitempty"`
+}
+
+// Options for the ListItems function
+type ListItemsOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The name or ID of the repository.
+	RepositoryId string
+	// The branch of the items (default: the default branch).
+	Branch string
+	// The folder of the items (default: the repository root).
+	ScopePath string
+}
+
+// ListItems lists the files and the folders of a folder of a branch
+// and of all its subfolders.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/items?scopePath=/&recursionLevel=Full&api-version=7.0
+func ListItems(ctx context.Context, cli *azuredevops.Client, opts ListItemsOptions) ([]GitItem, error) {
+	scopePath := opts.ScopePath
+	if len(scopePath) == 0 {
+		scopePath = "/"
+	}
+	params := []string{"scopePath", scopePath, "recursionLevel", "Full"}
+
+	uri, err := itemsURL(cli, opts.Organization, opts.Project, opts.RepositoryId, opts.Branch, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &struct {
+		Count int       `json:"count"`
+		Value []GitItem `json:"value,omitempty"`
+	}{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Value, nil
+}
+
+// Options for the GetItemContent function
+type GetItemContentOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The name or ID of the repository.
+	RepositoryId string
+	// (required) The path of the file (i.e. '/README.md').
+	Path string
+	// The branch of the file (default: the default branch).
+	Branch string
+}
+
+// GetItemContent downloads the content of a file of a branch.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}/items?path={path}&$format=octetStream&api-version=7.0
+func GetItemContent(ctx context.Context, cli *azuredevops.Client, opts GetItemContentOptions) ([]byte, error) {
+	params := []string{"path", opts.Path, "$format", "octetStream"}
+
+	uri, err := itemsURL(cli, opts.Organization, opts.Project, opts.RepositoryId, opts.Branch, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/octet-stream")
+	req = req.WithContext(ctx)
+
+	var data []byte
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:    cli.Verbose(),
+		AuthMethod: cli.AuthMethod(),
+		ResponseHandler: func(res *http.Response) (err error) {
+			data, err = io.ReadAll(res.Body)
+			return err
+		},
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	return data, err
+}
+
+func itemsURL(cli *azuredevops.Client, org, project, repositoryId, branch string, params []string) (string, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	params = append(params, apiVersionParams...)
+	if len(branch) > 0 {
+		params = append(params,
+			"versionDescriptor.version", strings.TrimPrefix(branch, "refs/heads/"),
+			"versionDescriptor.versionType", "branch")
+	}
+
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(org, project, "_apis/git/repositories", repositoryId, "items"),
+		Params:  params,
+	}).Build()
+	if err != nil {
+		return "", err
+	}
+	return uri.String(), nil
+}
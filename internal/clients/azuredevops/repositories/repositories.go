@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/projects"
@@ -16,13 +17,27 @@ import (
 )
 
 type GitRepository struct {
-	Id            *string               `json:"id,omitempty"`
-	Name          *string               `json:"name,omitempty"`
-	Project       *projects.TeamProject `json:"project,omitempty"`
-	DefaultBranch *string               `json:"defaultBranch,omitempty"`
-	RemoteUrl     *string               `json:"remoteUrl,omitempty"`
-	SshUrl        *string               `json:"sshUrl,omitempty"`
-	Url           *string               `json:"url,omitempty"`
+	Id               *string               `json:"id,omitempty"`
+	Name             *string               `json:"name,omitempty"`
+	Project          *projects.TeamProject `json:"project,omitempty"`
+	DefaultBranch    *string               `json:"defaultBranch,omitempty"`
+	IsDisabled       *bool                 `json:"isDisabled,omitempty"`
+	IsFork           *bool                 `json:"isFork,omitempty"`
+	ParentRepository *GitRepositoryRef     `json:"parentRepository,omitempty"`
+	RemoteUrl        *string               `json:"remoteUrl,omitempty"`
+	SshUrl           *string               `json:"sshUrl,omitempty"`
+	Url              *string               `json:"url,omitempty"`
+}
+
+// A reference to a repository: the parent repository of a fork.
+type GitRepositoryRef struct {
+	Id        *string               `json:"id,omitempty"`
+	Name      *string               `json:"name,omitempty"`
+	Project   *projects.TeamProject `json:"project,omitempty"`
+	IsFork    *bool                 `json:"isFork,omitempty"`
+	RemoteUrl *string               `json:"remoteUrl,omitempty"`
+	SshUrl    *string               `json:"sshUrl,omitempty"`
+	Url       *string               `json:"url,omitempty"`
 }
 
 type GitRefUpdate struct {
@@ -147,7 +162,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*GitRep
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &GitRepository{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -155,7 +169,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*GitRep
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -170,19 +184,30 @@ type CreateOptions struct {
 	Organization string
 	ProjectId    string
 	Name         string
+	// The repository to fork: its ID and the ID of its project, which
+	// can be another project of the organization.
+	ParentRepository *GitRepositoryRef
+	// The only ref of the parent repository copied to the fork
+	// (i.e. 'refs/heads/main'); all the refs are copied if empty.
+	SourceRef string
 }
 
-// Create creates a git repository in a team project.
-// POST https://dev.azure.com/{organization}/{project}/_apis/git/repositories?api-version=7.0
+// Create creates a git repository, or a fork of the parent repository,
+// in a team project.
+// POST https://dev.azure.com/{organization}/{project}/_apis/git/repositories?sourceRef={sourceRef}&api-version=7.0
 func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*GitRepository, error) {
 	apiVersionParams, isNone := getAPIVersion(cli)
 	if len(apiVersionParams) == 0 && !isNone {
 		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
+	params := apiVersionParams
+	if len(opts.SourceRef) > 0 {
+		params = append(params, "sourceRef", opts.SourceRef)
+	}
 	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
 		BaseURL: cli.BaseURL(azuredevops.Default),
 		Path:    path.Join(opts.Organization, opts.ProjectId, "_apis/git/repositories"),
-		Params:  apiVersionParams,
+		Params:  params,
 	}).Build()
 	if err != nil {
 		return nil, err
@@ -193,6 +218,7 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 		Project: &projects.TeamProject{
 			Id: helpers.StringPtr(opts.ProjectId),
 		},
+		ParentRepository: opts.ParentRepository,
 	}))
 	if err != nil {
 		return nil, err
@@ -200,19 +226,78 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &GitRepository{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
 }
 
+// Options for the Update function
+type UpdateOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the repository.
+	RepositoryId string
+	// The new name of the repository.
+	Name *string
+	// The new default branch (i.e. 'refs/heads/main'); it must exist.
+	DefaultBranch *string
+	// Disables or enables the repository: it must be updated alone, and
+	// the disabled repositories must be enabled to be updated.
+	IsDisabled *bool
+}
+
+// Update renames a git repository, changes its default branch or
+// disables (enables) it.
+// PATCH https://dev.azure.com/{organization}/{project}/_apis/git/repositories/{repositoryId}?api-version=7.0
+func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*GitRepository, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/repositories", opts.RepositoryId),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Patch(uri.String(), httplib.ToJSON(&GitRepository{
+		Name:          opts.Name,
+		DefaultBranch: opts.DefaultBranch,
+		IsDisabled:    opts.IsDisabled,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	val := &GitRepository{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod:      cli.AuthMethod(),
+		Verbose:         cli.Verbose(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
 type DeleteOptions struct {
 	Organization string
 	Project      string
@@ -244,7 +329,7 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
@@ -275,65 +360,179 @@ func DeleteFromRecycleBin(ctx context.Context, cli *azuredevops.Client, opts Del
 		AuthMethod: cli.AuthMethod(),
 		Verbose:    cli.Verbose(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.CheckStatus(http.StatusOK, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
 		},
 	})
 }
 
-type ListResponseValue struct {
-	Count int              `json:"count"`
-	Value []*GitRepository `json:"value,omitempty"`
+// A soft-deleted repository in the recycle bin: its name cannot be
+// reused until it is destroyed.
+type GitDeletedRepository struct {
+	Id          *string               `json:"id,omitempty"`
+	Name        *string               `json:"name,omitempty"`
+	Project     *projects.TeamProject `json:"project,omitempty"`
+	CreatedDate *azuredevops.Time     `json:"createdDate,omitempty"`
+	DeletedDate *azuredevops.Time     `json:"deletedDate,omitempty"`
 }
 
-type ListOptions struct {
-	Organization  string
-	Project       string
-	IncludeHidden bool
+// Options for the ListRecycleBin function
+type ListRecycleBinOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
 }
 
-// List all repositorires in the organization that the authenticated user has access to.
-// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories?api-version=7.0
-func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListResponseValue, error) {
+// ListRecycleBin lists the soft-deleted repositories of a team project.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/recycleBin/repositories?api-version=7.0
+func ListRecycleBin(ctx context.Context, cli *azuredevops.Client, opts ListRecycleBinOptions) ([]GitDeletedRepository, error) {
 	apiVersionParams, isNone := getAPIVersion(cli)
 	if len(apiVersionParams) == 0 && !isNone {
 		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/recycleBin/repositories"),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
 
-	var params []string
-	params = append(params, apiVersionParams...)
-	if opts.IncludeHidden {
-		params = append(params, "includeHidden", strconv.FormatBool(opts.IncludeHidden))
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
 	}
+	req = req.WithContext(ctx)
 
-	uri, err := httplib.NewURLBuilder(
-		httplib.URLBuilderOptions{
-			BaseURL: cli.BaseURL(azuredevops.Default),
-			Path:    path.Join(opts.Organization, opts.Project, "_apis/git/repositories"),
-			Params:  params,
-		}).Build()
+	val := &struct {
+		Count int                    `json:"count"`
+		Value []GitDeletedRepository `json:"value,omitempty"`
+	}{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
+	return val.Value, nil
+}
 
-	req, err := httplib.Get(uri.String())
+// FindDeleted finds the soft-deleted repository with the specified name
+// (case insensitive, like the repository names); if the name was deleted
+// more than once the last deleted repository is returned.
+func FindDeleted(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*GitDeletedRepository, error) {
+	all, err := ListRecycleBin(ctx, cli, ListRecycleBinOptions{
+		Organization: opts.Organization,
+		Project:      opts.Project,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var found *GitDeletedRepository
+	for i := range all {
+		if !strings.EqualFold(helpers.String(all[i].Name), opts.Name) {
+			continue
+		}
+		if found == nil || deletedDate(&all[i]).After(deletedDate(found)) {
+			found = &all[i]
+		}
+	}
+	if found != nil {
+		return found, nil
+	}
+
+	return nil, &httplib.StatusError{
+		StatusCode: http.StatusNotFound,
+		Inner: fmt.Errorf("deleted GitRepository not found (organization: %s, project: %s, name: %s)",
+			opts.Organization, opts.Project, opts.Name),
+	}
+}
+
+func deletedDate(repo *GitDeletedRepository) time.Time {
+	if repo.DeletedDate == nil {
+		return time.Time{}
+	}
+	return repo.DeletedDate.Time
+}
+
+// Options for the RestoreFromRecycleBin function
+type RestoreFromRecycleBinOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The ID of the soft-deleted repository.
+	RepositoryId string
+}
+
+// RestoreFromRecycleBin restores a soft-deleted Git repository.
+// PATCH https://dev.azure.com/{organization}/{project}/_apis/git/recycleBin/repositories/{repositoryId}?api-version=7.0
+func RestoreFromRecycleBin(ctx context.Context, cli *azuredevops.Client, opts RestoreFromRecycleBinOptions) (*GitRepository, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/git/recycleBin/repositories", opts.RepositoryId),
+		Params:  apiVersionParams,
+	}).Build()
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
-	val := &ListResponseValue{}
+	req, err := httplib.Patch(uri.String(), httplib.ToJSON(map[string]bool{"deleted": false}))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
 
+	val := &GitRepository{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
 
-	return val, err
+type ListResponseValue struct {
+	Count int              `json:"count"`
+	Value []*GitRepository `json:"value,omitempty"`
+}
+
+type ListOptions struct {
+	Organization  string
+	Project       string
+	IncludeHidden bool
+}
+
+// List all repositorires in the organization that the authenticated user has access to.
+// GET https://dev.azure.com/{organization}/{project}/_apis/git/repositories?api-version=7.0
+func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListResponseValue, error) {
+	all, err := newPager(cli, opts).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListResponseValue{
+		Count: len(all),
+		Value: all,
+	}, nil
 }
 
 type FindOptions struct {
@@ -343,19 +542,20 @@ type FindOptions struct {
 }
 
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*GitRepository, error) {
-	all, err := List(ctx, cli, ListOptions{
+	pager := newPager(cli, ListOptions{
 		Organization:  opts.Organization,
 		Project:       opts.Project,
 		IncludeHidden: true,
 	})
+
+	res, err := pager.Find(ctx, func(el *GitRepository) bool {
+		return helpers.String(el.Name) == opts.Name
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	for _, el := range all.Value {
-		if helpers.String(el.Name) == opts.Name {
-			return el, nil
-		}
+	if res != nil {
+		return *res, nil
 	}
 
 	return nil, &httplib.StatusError{
@@ -365,6 +565,28 @@ func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*GitR
 	}
 }
 
+func newPager(cli *azuredevops.Client, opts ListOptions) *azuredevops.Pager[*GitRepository] {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+
+	var params []string
+	params = append(params, apiVersionParams...)
+	if opts.IncludeHidden {
+		params = append(params, "includeHidden", strconv.FormatBool(opts.IncludeHidden))
+	}
+
+	return azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[*GitRepository], error) {
+			return azuredevops.FetchPage[*GitRepository](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   path.Join(opts.Organization, opts.Project, "_apis/git/repositories"),
+				Params: params,
+				Page:   pr,
+			})
+		})
+}
+
 // Arguments for the CreatePush function
 type GitPushOptions struct {
 	// (required)
@@ -401,14 +623,13 @@ func CreatePush(ctx context.Context, cli *azuredevops.Client, opts GitPushOption
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &GitPush{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
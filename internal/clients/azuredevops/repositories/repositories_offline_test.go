@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/projects"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestRepositoriesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	repo, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, ProjectId: projectId, Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, ProjectId: projectId, Name: "app"}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{Organization: fake.Organization, Project: "demo", Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(found.Id) != helpers.String(repo.Id) {
+		t.Fatalf("expected repository %s, got: %s", helpers.String(repo.Id), helpers.String(found.Id))
+	}
+
+	push, err := CreatePush(ctx, cli, GitPushOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		RepositoryId: helpers.String(repo.Id),
+		Push: &GitPush{
+			RefUpdates: &[]GitRefUpdate{
+				{Name: helpers.StringPtr("refs/heads/main"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+			},
+			Commits: &[]GitCommitRef{
+				{
+					Comment: helpers.StringPtr("Initial commit"),
+					Changes: []GitChange{
+						{
+							ChangeType: ChangeTypeAdd,
+							Item:       map[string]string{"path": "/README.md"},
+							NewContent: &ItemContent{Content: "# app", ContentType: ContentTypeRawText},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if push.PushId == nil {
+		t.Fatalf("expected push id")
+	}
+
+	repo, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, Repository: helpers.String(repo.Id)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(repo.DefaultBranch) != "refs/heads/main" {
+		t.Fatalf("expected default branch to be set by the first push, got: %s", helpers.String(repo.DefaultBranch))
+	}
+
+	upd := UpdateOptions{Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(repo.Id)}
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: upd.Organization, Project: upd.Project, RepositoryId: upd.RepositoryId,
+		DefaultBranch: helpers.StringPtr("refs/heads/missing"),
+	}); err == nil {
+		t.Fatal("expected missing default branch error")
+	}
+	renamed, err := Update(ctx, cli, UpdateOptions{
+		Organization: upd.Organization, Project: upd.Project, RepositoryId: upd.RepositoryId,
+		Name: helpers.StringPtr("app-renamed"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(renamed.Name) != "app-renamed" || helpers.String(renamed.Id) != helpers.String(repo.Id) {
+		t.Fatalf("unexpected renamed repository: %+v", renamed)
+	}
+	upd.IsDisabled = helpers.BoolPtr(true)
+	if disabled, err := Update(ctx, cli, upd); err != nil || !helpers.Bool(disabled.IsDisabled) {
+		t.Fatalf("expected disabled repository, got: %+v (%v)", disabled, err)
+	}
+	// The disabled repositories cannot be renamed.
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: upd.Organization, Project: upd.Project, RepositoryId: upd.RepositoryId,
+		Name: helpers.StringPtr("app"),
+	}); err == nil {
+		t.Fatal("expected disabled repository error")
+	}
+
+	res, err := List(ctx, cli, ListOptions{Organization: fake.Organization, Project: projectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The default project repository and the new one.
+	if res.Count != 2 {
+		t.Fatalf("expected 2 repositories, got: %d", res.Count)
+	}
+
+	opts := DeleteOptions{Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(repo.Id)}
+	if err := Delete(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteFromRecycleBin(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, Repository: helpers.String(repo.Id)}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestImportRequestsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+	ep := srv.AddServiceEndpoint(fake.Organization, projectId, "github", "git")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	repo, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, ProjectId: projectId, Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := CreateImportRequestOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		RepositoryId: helpers.String(repo.Id),
+		Parameters: &GitImportRequestParameters{
+			GitSource:         &GitImportGitSource{Url: helpers.StringPtr("https://github.com/org/app.git")},
+			ServiceEndpointId: helpers.StringPtr("missing"),
+		},
+	}
+	if _, err := CreateImportRequest(ctx, cli, opts); err == nil {
+		t.Fatal("expected missing service endpoint error")
+	}
+
+	opts.Parameters.ServiceEndpointId = helpers.StringPtr(fake.String(ep["id"]))
+	opts.Parameters.DeleteServiceEndpointAfterImportIsDone = helpers.BoolPtr(true)
+	req, err := CreateImportRequest(ctx, cli, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.IsDone() || req.DetailedStatus == nil || helpers.Int(req.DetailedStatus.CurrentStep) != 1 {
+		t.Fatalf("unexpected import request: %+v", req)
+	}
+
+	if !srv.CompleteImport(fake.Organization, projectId, "app", "") {
+		t.Fatal("expected import request to complete")
+	}
+	got, err := GetImportRequest(ctx, cli, GetImportRequestOptions{
+		Organization:    fake.Organization,
+		Project:         projectId,
+		RepositoryId:    helpers.String(repo.Id),
+		ImportRequestId: helpers.Int(req.ImportRequestId),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != AsyncOperationCompleted || !got.IsDone() {
+		t.Fatalf("expected completed import, got: %s", got.Status)
+	}
+	if srv.ServiceEndpoint(fake.Organization, fake.String(ep["id"])) != nil {
+		t.Fatal("expected service endpoint to be deleted after the import")
+	}
+
+	all, err := ListImportRequests(ctx, cli, ListImportRequestsOptions{
+		Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(repo.Id),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("expected 1 import request, got: %d", len(all))
+	}
+
+	// Only the empty repositories can be imported.
+	opts.Parameters.ServiceEndpointId = nil
+	opts.Parameters.DeleteServiceEndpointAfterImportIsDone = nil
+	if _, err := CreateImportRequest(ctx, cli, opts); err == nil {
+		t.Fatal("expected non empty repository error")
+	}
+}
+
+func TestForksOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	upstream := fake.String(srv.AddProject(fake.Organization, "upstream")["id"])
+	projectId := fake.String(srv.AddProject(fake.Organization, "demo")["id"])
+	parent := srv.AddRepository(fake.Organization, upstream, "lib")
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	_, err := CreatePush(ctx, cli, GitPushOptions{
+		Organization: fake.Organization,
+		Project:      upstream,
+		RepositoryId: fake.String(parent["id"]),
+		Push: &GitPush{
+			RefUpdates: &[]GitRefUpdate{
+				{Name: helpers.StringPtr("refs/heads/main"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+				{Name: helpers.StringPtr("refs/heads/release"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+			},
+			Commits: &[]GitCommitRef{{Comment: helpers.StringPtr("Initial commit")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := CreateOptions{
+		Organization: fake.Organization,
+		ProjectId:    projectId,
+		Name:         "lib-fork",
+		ParentRepository: &GitRepositoryRef{
+			Id:      helpers.StringPtr(fake.String(parent["id"])),
+			Project: &projects.TeamProject{Id: helpers.StringPtr(upstream)},
+		},
+		SourceRef: "refs/heads/missing",
+	}
+	if _, err := Create(ctx, cli, opts); err == nil {
+		t.Fatal("expected missing source ref error")
+	}
+
+	opts.SourceRef = "refs/heads/release"
+	fork, err := Create(ctx, cli, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !helpers.Bool(fork.IsFork) || fork.ParentRepository == nil ||
+		helpers.String(fork.ParentRepository.Id) != fake.String(parent["id"]) || fork.ParentRepository.Project.Name != "upstream" {
+		t.Fatalf("unexpected fork: %+v", fork)
+	}
+
+	list := ListForkSyncRequestsOptions{Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(fork.Id)}
+	all, err := ListForkSyncRequests(ctx, cli, list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].IsDone() || helpers.String(all[0].Source.RepositoryId) != fake.String(parent["id"]) {
+		t.Fatalf("unexpected fork sync requests: %+v", all)
+	}
+
+	if !srv.CompleteForkSync(fake.Organization, projectId, "lib-fork", "") {
+		t.Fatal("expected fork sync request to complete")
+	}
+	if all, err = ListForkSyncRequests(ctx, cli, list); err != nil || all[0].Status != AsyncOperationCompleted {
+		t.Fatalf("expected completed fork sync, got: %+v (%v)", all, err)
+	}
+	refs := srv.Refs(fake.Organization, projectId, "lib-fork")
+	if _, ok := refs["refs/heads/release"]; !ok || len(refs) != 1 {
+		t.Fatalf("expected only the source ref, got: %v", refs)
+	}
+}
+
+func TestRecycleBinOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	projectId := fake.String(srv.AddProject(fake.Organization, "demo")["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	create := CreateOptions{Organization: fake.Organization, ProjectId: projectId, Name: "app"}
+	repo, err := Create(ctx, cli, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DeleteOptions{Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(repo.Id)}
+	if err := Delete(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// The soft-deleted repository holds its name.
+	if _, err := Create(ctx, cli, create); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+	find := FindOptions{Organization: fake.Organization, Project: projectId, Name: "app"}
+	del, err := FindDeleted(ctx, cli, find)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(del.Id) != helpers.String(repo.Id) || del.DeletedDate == nil {
+		t.Fatalf("unexpected deleted repository: %+v", del)
+	}
+
+	restored, err := RestoreFromRecycleBin(ctx, cli, RestoreFromRecycleBinOptions{
+		Organization: fake.Organization, Project: projectId, RepositoryId: helpers.String(repo.Id),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(restored.Id) != helpers.String(repo.Id) || helpers.String(restored.Name) != "app" {
+		t.Fatalf("unexpected restored repository: %+v", restored)
+	}
+	if _, err := FindDeleted(ctx, cli, find); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected empty recycle bin, got: %v", err)
+	}
+}
+
+func TestFindDeletedOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	projectId := fake.String(srv.AddProject(fake.Organization, "demo")["id"])
+
+	// The name was deleted twice, with a different case.
+	srv.Handle(http.MethodGet, "{org}/{project}/_apis/git/recycleBin/repositories", func(c *fake.Call) {
+		c.List([]fake.Object{
+			{"id": "older", "name": "App", "deletedDate": "2024-01-01T10:00:00Z"},
+			{"id": "latest", "name": "app", "deletedDate": "2024-03-01T10:00:00Z"},
+			{"id": "other", "name": "other", "deletedDate": "2024-05-01T10:00:00Z"},
+		})
+	})
+
+	del, err := FindDeleted(context.TODO(), srv.Client(), FindOptions{Organization: fake.Organization, Project: projectId, Name: "APP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(del.Id) != "latest" {
+		t.Fatalf("expected the last deleted repository, got: %s", helpers.String(del.Id))
+	}
+}
@@ -212,7 +212,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Permis
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PermissionResponse{
 		Count: 0,
 		Value: []IdentityPermission{},
@@ -222,7 +221,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Permis
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	if val != nil && reflect.DeepEqual(*val, PermissionResponse{Count: 0, Value: []IdentityPermission{}}) {
@@ -262,7 +261,6 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &PermissionResponse{
 		Count: 0,
 		Value: []IdentityPermission{},
@@ -272,7 +270,7 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusAccepted),
+			azuredevops.DecodeError(http.StatusOK, http.StatusAccepted),
 		},
 	})
 	return val, err
@@ -0,0 +1,61 @@
+package repositoryspermissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestRepositoryPermissionsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	repo := srv.Repository(fake.Organization, "demo", "demo")
+	token := CreateToken(fake.String(prj["id"]), fake.String(repo["id"]))
+	descriptor := "Microsoft.TeamFoundation.Identity;S-1-9-1551374245-1"
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	allow := int(GenericRead | GenericContribute)
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		ResourceAuthorization: &AccessControlUpdate{
+			Merge: true,
+			Token: token,
+			AccessControlEntries: []AccessControlEntry{
+				{Descriptor: descriptor, Allow: allow, Deny: int(ForcePush)},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Descriptor: descriptor, Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 1 || res.Value[0].Allow != allow || res.Value[0].Deny != int(ForcePush) {
+		t.Fatalf("unexpected permissions: %+v", res.Value)
+	}
+
+	// Without merge the entry is replaced.
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization: fake.Organization,
+		ResourceAuthorization: &AccessControlUpdate{
+			Token: token,
+			AccessControlEntries: []AccessControlEntry{
+				{Descriptor: descriptor, Allow: int(GenericRead)},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	allow, deny := srv.AccessControlEntry(fake.Organization, securityNamespaceId, token, descriptor)
+	if allow != int(GenericRead) || deny != 0 {
+		t.Fatalf("unexpected access control entry: allow %d, deny %d", allow, deny)
+	}
+}
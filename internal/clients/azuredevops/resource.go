@@ -1,11 +1,5 @@
 package azuredevops
 
-import (
-	"net/http"
-
-	"github.com/lucasepe/httplib"
-)
-
 type Resource struct {
 	// Id of the resource.
 	Id *string `json:"id,omitempty"`
@@ -14,11 +8,3 @@ type Resource struct {
 	// Type of the resource.
 	Type *string `json:"type,omitempty"`
 }
-
-func IsAlreadyExists(err error) bool {
-	return httplib.HasStatusErr(err, http.StatusConflict)
-}
-
-func IsNotFound(err error) bool {
-	return httplib.HasStatusErr(err, http.StatusNotFound)
-}
@@ -2,9 +2,11 @@ package runs
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -211,14 +213,13 @@ func Run(ctx context.Context, cli *azuredevops.Client, opts RunOptions) (*RunInf
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &RunInfo{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	return val, err
@@ -258,7 +259,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*RunInf
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &RunInfo{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -266,7 +266,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*RunInf
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if err != nil {
@@ -278,3 +278,295 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*RunInf
 
 	return val, nil
 }
+
+// Options for the Cancel run function
+type CancelOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The run id
+	RunId int
+}
+
+// Cancel requests the cancellation of a run; the run state becomes
+// 'canceling' until the agents stop it.
+// PATCH https://dev.azure.com/{organization}/{project}/_apis/build/builds/{buildId}?api-version=7.0
+func Cancel(ctx context.Context, cli *azuredevops.Client, opts CancelOptions) error {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/builds", strconv.Itoa(opts.RunId)),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return err
+	}
+
+	req, err := httplib.Patch(uri.String(), httplib.ToJSON(map[string]string{
+		"status": "cancelling",
+	}))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	return httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		AuthMethod: cli.AuthMethod(),
+		Verbose:    cli.Verbose(),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+}
+
+// An issue (error or warning) reported by a timeline record.
+type Issue struct {
+	Type     *string `json:"type,omitempty"`
+	Category *string `json:"category,omitempty"`
+	Message  *string `json:"message,omitempty"`
+}
+
+// A reference to the log of a timeline record.
+type TaskLogReference struct {
+	Id  *int    `json:"id,omitempty"`
+	Url *string `json:"url,omitempty"`
+}
+
+// A record of the timeline of a run.
+type TimelineRecord struct {
+	Id         *string           `json:"id,omitempty"`
+	ParentId   *string           `json:"parentId,omitempty"`
+	Type       *string           `json:"type,omitempty"`
+	Name       *string           `json:"name,omitempty"`
+	Identifier *string           `json:"identifier,omitempty"`
+	Order      *int              `json:"order,omitempty"`
+	State      *string           `json:"state,omitempty"`
+	Result     *string           `json:"result,omitempty"`
+	StartTime  *azuredevops.Time `json:"startTime,omitempty"`
+	FinishTime *azuredevops.Time `json:"finishTime,omitempty"`
+	ErrorCount *int              `json:"errorCount,omitempty"`
+	Issues     []Issue           `json:"issues,omitempty"`
+	Log        *TaskLogReference `json:"log,omitempty"`
+}
+
+type timeline struct {
+	Records []TimelineRecord `json:"records,omitempty"`
+}
+
+// Options for the GetTimeline function
+type GetTimelineOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The run id
+	RunId int
+}
+
+// GetTimeline returns the records (stages, jobs and tasks) of the timeline of a run.
+// GET https://dev.azure.com/{organization}/{project}/_apis/build/builds/{buildId}/timeline?api-version=7.0
+func GetTimeline(ctx context.Context, cli *azuredevops.Client, opts GetTimelineOptions) ([]TimelineRecord, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/builds", strconv.Itoa(opts.RunId), "timeline"),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &timeline{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:    cli.Verbose(),
+		AuthMethod: cli.AuthMethod(),
+		ResponseHandler: func(res *http.Response) error {
+			// The timeline is empty until the run is queued.
+			if res.StatusCode == http.StatusNoContent {
+				return nil
+			}
+			return httplib.FromJSON(val)(res)
+		},
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK, http.StatusNoContent),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Records, nil
+}
+
+// Options for the ListStages function
+type ListStagesOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The run id
+	RunId int
+}
+
+// ListStages returns the stages of a run ordered by execution.
+func ListStages(ctx context.Context, cli *azuredevops.Client, opts ListStagesOptions) ([]TimelineRecord, error) {
+	records, err := GetTimeline(ctx, cli, GetTimelineOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return Stages(records), nil
+}
+
+// Stages filters the stages of the timeline records ordered by execution.
+func Stages(records []TimelineRecord) []TimelineRecord {
+	stages := []TimelineRecord{}
+	for _, el := range records {
+		if strings.EqualFold(helpers.String(el.Type), "Stage") {
+			stages = append(stages, el)
+		}
+	}
+	sort.SliceStable(stages, func(i, j int) bool {
+		return helpers.Int(stages[i].Order) < helpers.Int(stages[j].Order)
+	})
+	return stages
+}
+
+// Options for the GetLog function
+type GetLogOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The run id
+	RunId int
+	// (required) The log id
+	LogId int
+}
+
+// GetLog returns the lines of a log of a run.
+// GET https://dev.azure.com/{organization}/{project}/_apis/build/builds/{buildId}/logs/{logId}?api-version=7.0
+func GetLog(ctx context.Context, cli *azuredevops.Client, opts GetLogOptions) ([]string, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/builds", strconv.Itoa(opts.RunId), "logs", strconv.Itoa(opts.LogId)),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "text/plain")
+	req = req.WithContext(ctx)
+
+	var lines []string
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:    cli.Verbose(),
+		AuthMethod: cli.AuthMethod(),
+		ResponseHandler: func(res *http.Response) error {
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				return err
+			}
+			text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+			if len(text) > 0 {
+				lines = strings.Split(text, "\n")
+			}
+			return nil
+		},
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	return lines, err
+}
+
+// The resource of a published artifact.
+type ArtifactResource struct {
+	// The type of the resource (i.e. 'Container', 'PipelineArtifact').
+	Type *string `json:"type,omitempty"`
+	// Type-specific data about the artifact.
+	Data *string `json:"data,omitempty"`
+	// A link to download the resource.
+	DownloadUrl *string `json:"downloadUrl,omitempty"`
+	// The full http link to the resource.
+	Url *string `json:"url,omitempty"`
+}
+
+// An artifact published by a run.
+type Artifact struct {
+	Id       *int              `json:"id,omitempty"`
+	Name     *string           `json:"name,omitempty"`
+	Source   *string           `json:"source,omitempty"`
+	Resource *ArtifactResource `json:"resource,omitempty"`
+}
+
+// Options for the ListArtifacts function
+type ListArtifactsOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The run id
+	RunId int
+}
+
+// ListArtifacts returns the artifacts published by a run.
+// GET https://dev.azure.com/{organization}/{project}/_apis/build/builds/{buildId}/artifacts?api-version=7.0
+func ListArtifacts(ctx context.Context, cli *azuredevops.Client, opts ListArtifactsOptions) ([]Artifact, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/build/builds", strconv.Itoa(opts.RunId), "artifacts"),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := httplib.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	val := &struct {
+		Count int        `json:"count"`
+		Value []Artifact `json:"value"`
+	}{}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.Value, nil
+}
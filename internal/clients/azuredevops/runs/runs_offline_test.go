@@ -0,0 +1,154 @@
+package runs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/pipelines"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+func TestRunsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	pip, err := pipelines.Create(ctx, cli, pipelines.CreateOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		Pipeline:     pipelines.Pipeline{Name: "ci"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipelineId := int(*pip.Id)
+
+	run, err := Run(ctx, cli, RunOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		PipelineId:   pipelineId,
+		RunParameters: &RunPipelineParameters{
+			TemplateParameters: map[string]string{"env": "dev"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(run.State) != "inProgress" {
+		t.Fatalf("expected run in progress, got: %s", helpers.String(run.State))
+	}
+
+	if !srv.CompleteRun(fake.Organization, projectId, pipelineId, *run.Id, "succeeded") {
+		t.Fatalf("run %d not found", *run.Id)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{
+		Organization: fake.Organization,
+		Project:      projectId,
+		PipelineId:   pipelineId,
+		RunId:        *run.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(got.State) != "completed" || helpers.String(got.Result) != "succeeded" {
+		t.Fatalf("expected succeeded run, got: %s (%s)", helpers.String(got.State), helpers.String(got.Result))
+	}
+	if got.FinishedDate == nil {
+		t.Fatalf("expected finished date")
+	}
+	if got.TemplateParameters["env"] != "dev" {
+		t.Fatalf("expected template parameters, got: %v", got.TemplateParameters)
+	}
+
+	if _, err := Run(ctx, cli, RunOptions{Organization: fake.Organization, Project: projectId, PipelineId: 999}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+
+	run, err = Run(ctx, cli, RunOptions{Organization: fake.Organization, Project: projectId, PipelineId: pipelineId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages, err := ListStages(ctx, cli, ListStagesOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stages) != 1 || helpers.String(stages[0].Name) != "Build" || helpers.String(stages[0].State) != "inProgress" {
+		t.Fatalf("unexpected stages: %+v", stages)
+	}
+
+	if err := Cancel(ctx, cli, CancelOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, PipelineId: pipelineId, RunId: *run.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(got.Result) != "canceled" {
+		t.Fatalf("expected canceled run, got: %s", helpers.String(got.Result))
+	}
+
+	if err := Cancel(ctx, cli, CancelOptions{Organization: fake.Organization, Project: projectId, RunId: 999}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestRunFailureDetailsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	projectId := fake.String(srv.AddProject(fake.Organization, "demo")["id"])
+	pipelineId := srv.AddPipeline(fake.Organization, projectId, "ci")["id"].(int)
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	run, err := Run(ctx, cli, RunOptions{Organization: fake.Organization, Project: projectId, PipelineId: pipelineId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.CompleteRun(fake.Organization, projectId, pipelineId, *run.Id, "failed")
+	srv.PublishArtifact(fake.Organization, projectId, *run.Id, "drop")
+
+	records, err := GetTimeline(ctx, cli, GetTimelineOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var task *TimelineRecord
+	for i, el := range records {
+		if helpers.String(el.Type) == "Task" {
+			task = &records[i]
+		}
+	}
+	if task == nil || helpers.String(task.Result) != "failed" || len(task.Issues) != 1 || task.Log == nil {
+		t.Fatalf("expected failed task, got: %+v", records)
+	}
+
+	lines, err := GetLog(ctx, cli, GetLogOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id, LogId: *task.Log.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 100 || !strings.HasPrefix(lines[99], "##[error]") {
+		t.Fatalf("unexpected log: %d lines", len(lines))
+	}
+	if _, err := GetLog(ctx, cli, GetLogOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id, LogId: 99}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+
+	artifacts, err := ListArtifacts(ctx, cli, ListArtifactsOptions{Organization: fake.Organization, Project: projectId, RunId: *run.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifacts) != 1 || helpers.String(artifacts[0].Name) != "drop" || artifacts[0].Resource == nil ||
+		helpers.String(artifacts[0].Resource.DownloadUrl) == "" {
+		t.Fatalf("unexpected artifacts: %+v", artifacts)
+	}
+}
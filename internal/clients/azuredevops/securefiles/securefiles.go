@@ -1,7 +1,9 @@
 package securefiles
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"path"
 	"reflect"
@@ -64,7 +66,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Secure
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &SecureFileResource{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -72,15 +73,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Secure
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			func(resp *http.Response) error {
-				if resp.StatusCode == http.StatusOK {
-					return nil
-				}
-				if resp.StatusCode == http.StatusNotFound { // needed as sometimes the API returns 404 and HTML error page (not JSON)
-					return &httplib.StatusError{StatusCode: http.StatusNotFound}
-				}
-				return httplib.ErrorJSON(apiErr, http.StatusOK)(resp)
-			},
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if err != nil {
@@ -107,86 +100,157 @@ type ListResponse struct {
 
 // GET https://dev.azure.com/{{organization}}/{{project}}/_apis/distributedtask/securefiles?api-version={{api_version}}
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListResponse, error) {
+	page, err := listPage(ctx, cli, opts, azuredevops.PageRequest{
+		ContinuationToken: helpers.String(opts.ContinuationToken),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	val := &ListResponse{
+		Count: page.Count,
+		Value: page.Value,
+	}
+	if len(page.ContinuationToken) > 0 {
+		val.ContinuationToken = helpers.StringPtr(page.ContinuationToken)
+	}
+
+	return val, nil
+}
+
+type FindOptions struct {
+	ListOptions
+	SecureFileName string
+}
+
+func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*SecureFileResource, error) {
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[SecureFileResource], error) {
+			return listPage(ctx, cli, opts.ListOptions, pr)
+		})
+
+	return pager.Find(ctx, func(el SecureFileResource) bool {
+		return el.Name == opts.SecureFileName
+	})
+}
+
+func listPage(ctx context.Context, cli *azuredevops.Client, opts ListOptions, pr azuredevops.PageRequest) (*azuredevops.Page[SecureFileResource], error) {
 	apiVersionParams, isNone := getAPIVersion(cli)
 	if len(apiVersionParams) == 0 && !isNone {
 		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal + azuredevops.ApiPreviewFlag + ".1"}
 	}
 
-	var queryparams []string
-	queryparams = append(queryparams, apiVersionParams...)
-	if opts.ContinuationToken != nil {
-		queryparams = append(queryparams, "continuationToken", helpers.String(opts.ContinuationToken))
+	return azuredevops.FetchPage[SecureFileResource](ctx, cli, azuredevops.FetchPageOptions{
+		Path:   path.Join(opts.Organization, opts.Project, "_apis/distributedtask/securefiles"),
+		Params: apiVersionParams,
+		Page:   pr,
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+}
+
+type UploadOptions struct {
+	Organization string
+	Project      string
+	Name         string
+	Content      []byte
+}
+
+// Upload uploads a secure file.
+// POST https://dev.azure.com/{{organization}}/{{project}}/_apis/distributedtask/securefiles?name={{name}}&api-version={{api_version}}
+func Upload(ctx context.Context, cli *azuredevops.Client, opts UploadOptions) (*SecureFileResource, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal + azuredevops.ApiPreviewFlag + ".1"}
 	}
 	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
 		BaseURL: cli.BaseURL(azuredevops.Default),
 		Path:    path.Join(opts.Organization, opts.Project, "_apis/distributedtask/securefiles"),
-		Params:  queryparams,
+		Params:  append([]string{"name", opts.Name}, apiVersionParams...),
 	}).Build()
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := httplib.Get(uri.String())
+	req, err := httplib.Post(uri.String(), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(opts.Content)), nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add("Content-Type", "application/octet-stream")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
-	val := &ListResponse{
-		Value: []SecureFileResource{},
-	}
+	val := &SecureFileResource{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:         cli.Verbose(),
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			func(resp *http.Response) error {
-				if resp.StatusCode == http.StatusOK {
-					return nil
-				}
-				if resp.StatusCode == http.StatusNotFound { // needed as sometimes the API returns 404 and HTML error page (not JSON)
-					return &httplib.StatusError{StatusCode: http.StatusNotFound}
-				}
-				return httplib.ErrorJSON(apiErr, http.StatusOK)(resp)
-			},
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
-	if reflect.DeepEqual(*val, SecureFileResource{}) {
-		return nil, err
-	}
 
 	return val, nil
 }
 
-type FindOptions struct {
-	ListOptions
-	SecureFileName string
+type UpdateOptions struct {
+	// (required) The name of the Azure DevOps organization.
+	Organization string
+	// (required) Project ID or project name
+	Project string
+	// (required) The secure file id.
+	SecureFileId string
+	// (required) The new name of the secure file.
+	Name string
 }
 
-func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*SecureFileResource, error) {
-	var continuationToken *string
-	opts.ListOptions.ContinuationToken = continuationToken
-	for {
-		secureFiles, err := List(ctx, cli, opts.ListOptions)
-		if err != nil {
-			return nil, err
-		}
-		for _, secureFile := range secureFiles.Value {
-			if secureFile.Name == opts.SecureFileName {
-				return &secureFile, nil
-			}
-		}
-		if secureFiles.ContinuationToken == nil {
-			break
-		}
-		opts.ListOptions.ContinuationToken = secureFiles.ContinuationToken
+// Update renames a secure file: its content cannot be changed.
+// PATCH https://dev.azure.com/{{organization}}/{{project}}/_apis/distributedtask/securefiles/{{secureid}}?api-version={{api_version}}
+func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*SecureFileResource, error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal + azuredevops.ApiPreviewFlag + ".1"}
+	}
+	uri, err := httplib.NewURLBuilder(httplib.URLBuilderOptions{
+		BaseURL: cli.BaseURL(azuredevops.Default),
+		Path:    path.Join(opts.Organization, opts.Project, "_apis/distributedtask/securefiles", opts.SecureFileId),
+		Params:  apiVersionParams,
+	}).Build()
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+
+	req, err := httplib.Patch(uri.String(), httplib.ToJSON(&SecureFileResource{
+		ID:   opts.SecureFileId,
+		Name: opts.Name,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(ctx)
+
+	val := &SecureFileResource{}
+
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Verbose:         cli.Verbose(),
+		AuthMethod:      cli.AuthMethod(),
+		ResponseHandler: httplib.FromJSON(val),
+		Validators: []httplib.HandleResponseFunc{
+			azuredevops.DecodeError(http.StatusOK),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
 }
 
 type DeleteOptions struct {
@@ -216,13 +280,11 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
-
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 	if err != nil {
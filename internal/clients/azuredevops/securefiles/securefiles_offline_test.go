@@ -0,0 +1,61 @@
+package securefiles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestSecureFilesOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+	sf := srv.AddSecureFile(fake.Organization, projectId, "id_rsa", []byte("secret"))
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	found, err := Find(ctx, cli, FindOptions{
+		ListOptions:    ListOptions{Organization: fake.Organization, Project: projectId},
+		SecureFileName: "id_rsa",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.ID != fake.String(sf["id"]) {
+		t.Fatalf("expected secure file %s, got: %v", fake.String(sf["id"]), found)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, SecretFileId: found.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "id_rsa" {
+		t.Fatalf("unexpected secure file: %s", got.Name)
+	}
+
+	srv.AddSecureFile(fake.Organization, projectId, "id_ed25519", []byte("other"))
+	_, err = Update(ctx, cli, UpdateOptions{Organization: fake.Organization, Project: projectId, SecureFileId: found.ID, Name: "id_ed25519"})
+	if !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected already exists, got: %v", err)
+	}
+
+	got, err = Update(ctx, cli, UpdateOptions{Organization: fake.Organization, Project: projectId, SecureFileId: found.ID, Name: "deploy_key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != found.ID || got.Name != "deploy_key" {
+		t.Fatalf("unexpected renamed secure file: %+v", got)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, Project: projectId, SecureFileId: found.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, SecretFileId: found.ID}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
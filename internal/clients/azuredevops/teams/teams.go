@@ -89,6 +89,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TeamRe
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 
 	res := &TeamResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -96,7 +97,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TeamRe
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 	if reflect.DeepEqual(*res, TeamResponse{}) {
@@ -109,39 +110,15 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*TeamRe
 // Get a list of teams.
 // GET https://dev.azure.com/{organization}/_apis/projects/{projectId}/teams?api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*TeamListResponse, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-	ubo := httplib.URLBuilderOptions{
-		BaseURL: cli.BaseURL(azuredevops.Default),
-		Path:    path.Join(opts.Organization, "_apis/projects", opts.ProjectID, "teams"),
-		Params:  apiVersionParams,
-	}
-
-	uri, err := httplib.NewURLBuilder(ubo).Build()
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := httplib.Get(uri.String())
+	all, err := newPager(cli, opts).All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	res := &TeamListResponse{
-		Value: []TeamResponse{},
-	}
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		Verbose:         cli.Verbose(),
-		AuthMethod:      cli.AuthMethod(),
-		ResponseHandler: httplib.FromJSON(res),
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusOK),
-		},
-	})
-
-	return res, err
+	return &TeamListResponse{
+		Count: len(all),
+		Value: all,
+	}, nil
 }
 
 type FindTeamByNameOptions struct {
@@ -151,16 +128,25 @@ type FindTeamByNameOptions struct {
 }
 
 func FindTeamByName(ctx context.Context, cli *azuredevops.Client, opts FindTeamByNameOptions) (*TeamResponse, error) {
-	teams, err := List(ctx, cli, opts.ListOptions)
-	if err != nil {
-		return nil, err
-	}
-	for _, team := range teams.Value {
-		if strings.EqualFold(team.Name, opts.TeamName) && strings.EqualFold(team.ProjectID, opts.ProjectID) {
-			return &team, nil
-		}
+	return newPager(cli, opts.ListOptions).Find(ctx, func(team TeamResponse) bool {
+		return strings.EqualFold(team.Name, opts.TeamName) && strings.EqualFold(team.ProjectID, opts.ProjectID)
+	})
+}
+
+func newPager(cli *azuredevops.Client, opts ListOptions) *azuredevops.Pager[TeamResponse] {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
-	return nil, nil
+
+	return azuredevops.NewPager(azuredevops.TopSkipPaging, azuredevops.DefaultPageSize,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[TeamResponse], error) {
+			return azuredevops.FetchPage[TeamResponse](ctx, cli, azuredevops.FetchPageOptions{
+				Path:   path.Join(opts.Organization, "_apis/projects", opts.ProjectID, "teams"),
+				Params: apiVersionParams,
+				Page:   pr,
+			})
+		})
 }
 
 // Create a team in a team project.
@@ -194,7 +180,7 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(res),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusCreated),
 		},
 	})
 
@@ -232,12 +218,13 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(&azuredevops.APIError{}, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 
@@ -0,0 +1,119 @@
+package teams
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTeamsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	team, err := Create(ctx, cli, CreateOptions{
+		Organization: fake.Organization,
+		ProjectID:    projectId,
+		TeamData:     TeamData{Name: "devs", Description: helpers.StringPtr("developers")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := List(ctx, cli, ListOptions{Organization: fake.Organization, ProjectID: projectId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The default project team and the new one.
+	if res.Count != 2 {
+		t.Fatalf("expected 2 teams, got: %d", res.Count)
+	}
+
+	found, err := FindTeamByName(ctx, cli, FindTeamByNameOptions{
+		ListOptions: ListOptions{Organization: fake.Organization, ProjectID: projectId},
+		TeamName:    "devs",
+		ProjectID:   projectId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.ID != team.ID {
+		t.Fatalf("expected team %s, got: %v", team.ID, found)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectID: projectId, TeamID: team.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "devs" {
+		t.Fatalf("unexpected team: %s", got.Name)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, ProjectID: projectId, TeamID: team.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectID: projectId, TeamID: team.ID}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
+
+func TestTeamsTracing(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(prev)
+
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	team, err := Create(context.TODO(), cli, CreateOptions{
+		Organization: fake.Organization,
+		ProjectID:    projectId,
+		TeamData:     TeamData{Name: "devs"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := tracing.Start(context.TODO(), "Reconcile Test")
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, ProjectID: projectId, TeamID: team.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, ProjectID: projectId, TeamID: team.ID}); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	calls := 0
+	for _, span := range sr.Ended() {
+		if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+			continue
+		}
+		if span.SpanContext().SpanID() == parent.SpanContext().SpanID() {
+			continue
+		}
+		calls++
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected span %q to be a child of the reconcile span", span.Name())
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 call spans under the reconcile span, got: %d", calls)
+	}
+}
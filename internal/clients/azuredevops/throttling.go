@@ -0,0 +1,385 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Azure DevOps rate limiting response headers.
+	// https://learn.microsoft.com/en-us/azure/devops/integrate/concepts/rate-limits?view=azure-devops#api-client-experience
+	HeaderRetryAfter         = "Retry-After"
+	HeaderRateLimitResource  = "X-RateLimit-Resource"
+	HeaderRateLimitDelay     = "X-RateLimit-Delay"
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+
+	// LowRateLimitRatio is the fraction of the limit below which the
+	// remaining TSTUs are considered low.
+	LowRateLimitRatio = 0.2
+
+	DefaultMaxRetries   = 3
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 10 * time.Second
+)
+
+// RateLimit holds the last throttling information Azure DevOps
+// returned for an organization.
+type RateLimit struct {
+	// Resource the limit applies to (i.e. "ATCPU" or "DBCPU").
+	Resource string
+	// Delay applied by Azure DevOps to the last request.
+	Delay time.Duration
+	// Limit is the total number of TSTUs allowed before delays are imposed.
+	Limit int
+	// Remaining is the number of TSTUs remaining before being delayed.
+	Remaining int
+	// Reset is the time at which the usage would return to 0 TSTUs.
+	Reset time.Time
+	// RetryAfter is the time before which no request should be sent.
+	RetryAfter time.Time
+	// ObservedAt is the time the headers were received.
+	ObservedAt time.Time
+}
+
+// Blocked reports whether the caller should wait before sending
+// new requests and for how long: only after a 429 (Too Many Requests)
+// response with the Retry-After header.
+func (rl RateLimit) Blocked(now time.Time) (bool, time.Duration) {
+	if now.Before(rl.RetryAfter) {
+		return true, rl.RetryAfter.Sub(now)
+	}
+	return false, 0
+}
+
+// Low reports whether the remaining TSTUs are below LowRateLimitRatio
+// of the limit.
+func (rl RateLimit) Low() bool {
+	return rl.Limit > 0 && float64(rl.Remaining) < float64(rl.Limit)*LowRateLimitRatio
+}
+
+// PollInterval returns how long to wait before polling again the
+// organization: the interval is lengthened while the remaining TSTUs
+// are low, at least until the usage is reset.
+func (rl RateLimit) PollInterval(now time.Time, interval time.Duration) time.Duration {
+	if blocked, wait := rl.Blocked(now); blocked && wait > interval {
+		interval = wait
+	}
+	if !rl.Low() {
+		return interval
+	}
+	wait := 2 * interval
+	if reset := rl.Reset.Sub(now); reset > wait {
+		wait = reset
+	}
+	return wait
+}
+
+// ThrottledError is returned when a request is not sent (or not retried
+// anymore) because Azure DevOps asked the client to back off.
+type ThrottledError struct {
+	Organization string
+	RetryAfter   time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("azuredevops: organization '%s' is throttled, retry after %s",
+		e.Organization, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+var rateLimits = struct {
+	sync.RWMutex
+	m map[string]RateLimit
+}{m: map[string]RateLimit{}}
+
+// RateLimitStatus returns the last known throttling information
+// for the specified organization.
+func RateLimitStatus(organization string) (RateLimit, bool) {
+	rateLimits.RLock()
+	defer rateLimits.RUnlock()
+	rl, ok := rateLimits.m[strings.ToLower(organization)]
+	return rl, ok
+}
+
+type organizationsKey struct{}
+
+type organizations struct {
+	sync.Mutex
+	names []string
+	// retryAfter is the longest wait asked by the throttled requests.
+	retryAfter time.Duration
+}
+
+// WithOrganizationRecorder returns a context recording the organizations
+// the requests sent with it are addressed to and how long the throttled
+// ones asked to back off.
+func WithOrganizationRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, organizationsKey{}, &organizations{})
+}
+
+// RecordedOrganizations returns the organizations recorded by the context.
+func RecordedOrganizations(ctx context.Context) []string {
+	orgs, ok := ctx.Value(organizationsKey{}).(*organizations)
+	if !ok {
+		return nil
+	}
+	orgs.Lock()
+	defer orgs.Unlock()
+	return append([]string(nil), orgs.names...)
+}
+
+// RecordedRetryAfter returns the longest wait asked by the requests sent
+// with the context that failed because throttled; zero if none.
+func RecordedRetryAfter(ctx context.Context) time.Duration {
+	orgs, ok := ctx.Value(organizationsKey{}).(*organizations)
+	if !ok {
+		return 0
+	}
+	orgs.Lock()
+	defer orgs.Unlock()
+	return orgs.retryAfter
+}
+
+func recordRetryAfter(ctx context.Context, wait time.Duration) {
+	orgs, ok := ctx.Value(organizationsKey{}).(*organizations)
+	if !ok {
+		return
+	}
+	orgs.Lock()
+	defer orgs.Unlock()
+	if wait > orgs.retryAfter {
+		orgs.retryAfter = wait
+	}
+}
+
+func recordOrganization(ctx context.Context, organization string) {
+	orgs, ok := ctx.Value(organizationsKey{}).(*organizations)
+	if !ok || organization == "" {
+		return
+	}
+	orgs.Lock()
+	defer orgs.Unlock()
+	for _, el := range orgs.names {
+		if strings.EqualFold(el, organization) {
+			return
+		}
+	}
+	orgs.names = append(orgs.names, organization)
+}
+
+func storeRateLimit(organization string, rl RateLimit) {
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+	rateLimits.m[strings.ToLower(organization)] = rl
+}
+
+// throttlingTransport is an http.RoundTripper that honors Azure DevOps
+// throttling: it records the rate limit headers, fails fast while an
+// organization is blocked by a 429 (Too Many Requests) response and
+// retries idempotent requests on 429 and 503 (Service Unavailable).
+type throttlingTransport struct {
+	next         http.RoundTripper
+	basePath     string
+	maxRetries   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+	now          func() time.Time
+}
+
+func newThrottlingTransport(next http.RoundTripper, basePath string, opts ClientOptions) *throttlingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &throttlingTransport{
+		next:         next,
+		basePath:     basePath,
+		maxRetries:   DefaultMaxRetries,
+		retryWaitMin: DefaultRetryWaitMin,
+		retryWaitMax: DefaultRetryWaitMax,
+		now:          time.Now,
+	}
+	if opts.MaxRetries != nil {
+		t.maxRetries = *opts.MaxRetries
+	}
+	if opts.RetryWaitMin > 0 {
+		t.retryWaitMin = opts.RetryWaitMin
+	}
+	if opts.RetryWaitMax > 0 {
+		t.retryWaitMax = opts.RetryWaitMax
+	}
+	return t
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	org := organizationFromPath(strings.TrimPrefix(req.URL.Path, t.basePath))
+	recordOrganization(req.Context(), org)
+	if rl, ok := RateLimitStatus(org); ok {
+		if blocked, wait := rl.Blocked(t.now()); blocked {
+			recordRetryAfter(req.Context(), wait)
+			return nil, &ThrottledError{Organization: org, RetryAfter: wait}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		rl := t.observe(org, res)
+
+		if !isThrottled(res.StatusCode) {
+			return res, nil
+		}
+
+		wait := t.backoff(attempt)
+		if !rl.RetryAfter.IsZero() {
+			wait = rl.RetryAfter.Sub(t.now())
+		} else if val, ok := parseRetryAfter(res.Header.Get(HeaderRetryAfter), t.now()); ok {
+			wait = val
+		}
+		if !isIdempotent(req) || attempt >= t.maxRetries || wait > t.retryWaitMax {
+			// Not retried or too long to wait inside a reconcile loop: let
+			// the caller requeue; the next calls will fail fast until RetryAfter.
+			recordRetryAfter(req.Context(), wait)
+			return res, nil
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return res, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return res, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		drain(res)
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// observe records the rate limit headers returned with the response.
+func (t *throttlingTransport) observe(org string, res *http.Response) RateLimit {
+	now := t.now()
+
+	rl, _ := RateLimitStatus(org)
+	rl.ObservedAt = now
+	rl.RetryAfter = time.Time{}
+
+	found := false
+	if val := res.Header.Get(HeaderRateLimitResource); val != "" {
+		rl.Resource, found = val, true
+	}
+	if val, err := strconv.ParseFloat(res.Header.Get(HeaderRateLimitDelay), 64); err == nil {
+		rl.Delay, found = time.Duration(val*float64(time.Second)), true
+	}
+	if val, err := strconv.Atoi(res.Header.Get(HeaderRateLimitLimit)); err == nil {
+		rl.Limit, found = val, true
+	}
+	if val, err := strconv.Atoi(res.Header.Get(HeaderRateLimitRemaining)); err == nil {
+		rl.Remaining, found = val, true
+	}
+	if val, err := strconv.ParseInt(res.Header.Get(HeaderRateLimitReset), 10, 64); err == nil {
+		rl.Reset, found = time.Unix(val, 0), true
+	}
+	if wait, ok := parseRetryAfter(res.Header.Get(HeaderRetryAfter), now); ok && res.StatusCode == http.StatusTooManyRequests {
+		rl.RetryAfter, found = now.Add(wait), true
+	}
+
+	if found && org != "" {
+		storeRateLimit(org, rl)
+	}
+
+	return rl
+}
+
+// backoff computes an exponential wait for the specified attempt.
+func (t *throttlingTransport) backoff(attempt int) time.Duration {
+	val := float64(t.retryWaitMin) * math.Pow(2, float64(attempt))
+	if val > float64(t.retryWaitMax) {
+		return t.retryWaitMax
+	}
+	return time.Duration(val)
+}
+
+func isThrottled(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter accepts both delay-seconds and HTTP-date formats.
+func parseRetryAfter(val string, now time.Time) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(val)); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(val); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// organizationFromPath returns the first segment of the URL path (without
+// the base URL path) that is the organization or collection name.
+func organizationFromPath(p string) string {
+	p = strings.TrimPrefix(p, "/")
+	if idx := strings.Index(p, "/"); idx >= 0 {
+		p = p[:idx]
+	}
+	return p
+}
+
+func drain(res *http.Response) {
+	const maxDiscardSize = 64 << 10
+	io.CopyN(io.Discard, res.Body, maxDiscardSize)
+	res.Body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,227 @@
+package azuredevops
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lucasepe/httplib"
+)
+
+func newThrottlingTestClient(srv *httptest.Server) *Client {
+	return NewClient(ClientOptions{
+		UriMap: &map[URIKey]string{
+			Default: srv.URL,
+		},
+		RetryWaitMin: time.Millisecond,
+		RetryWaitMax: 100 * time.Millisecond,
+	})
+}
+
+func fireGet(t *testing.T, cli *Client, uri string) error {
+	t.Helper()
+	req, err := httplib.Get(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httplib.Fire(cli.HTTPClient(), req.WithContext(context.TODO()), httplib.FireOptions{
+		AuthMethod: cli.AuthMethod(),
+		Validators: []httplib.HandleResponseFunc{
+			httplib.CheckStatus(http.StatusOK),
+		},
+	})
+}
+
+func TestThrottlingRetriesIdempotentRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set(HeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set(HeaderRateLimitResource, "ATCPU")
+		w.Header().Set(HeaderRateLimitLimit, "200")
+		w.Header().Set(HeaderRateLimitRemaining, "150")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	if err := fireGet(t, cli, srv.URL+"/retry-org/_apis/projects"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 calls, got: %d", got)
+	}
+
+	rl, ok := RateLimitStatus("retry-org")
+	if !ok {
+		t.Fatal("expected rate limit status to be recorded")
+	}
+	if rl.Resource != "ATCPU" || rl.Limit != 200 || rl.Remaining != 150 {
+		t.Fatalf("unexpected rate limit status: %+v", rl)
+	}
+}
+
+func TestThrottlingDoesNotRetryPost(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(HeaderRetryAfter, "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+
+	req, err := httplib.Post(srv.URL+"/post-org/_apis/projects", httplib.ToJSON(map[string]string{"name": "x"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
+		Validators: []httplib.HandleResponseFunc{
+			httplib.CheckStatus(http.StatusOK),
+		},
+	})
+	if !IsThrottled(err) {
+		t.Fatalf("expected throttled error, got: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected 1 call, got: %d", got)
+	}
+}
+
+func TestThrottlingFailsFastWhileBlocked(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(HeaderRetryAfter, strconv.Itoa(3600))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+
+	err := fireGet(t, cli, srv.URL+"/blocked-org/_apis/projects")
+	if !IsThrottled(err) {
+		t.Fatalf("expected throttled error, got: %v", err)
+	}
+
+	err = fireGet(t, cli, srv.URL+"/blocked-org/_apis/projects")
+	if !IsThrottled(err) {
+		t.Fatalf("expected throttled error, got: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected 1 call, got: %d", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{"garbage", 0, false},
+	}
+
+	for i, tc := range table {
+		got, ok := parseRetryAfter(tc.in, now)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("[%d] expected (%v, %t), got (%v, %t)", i, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestThrottlingDoesNotBlockOnLowRemaining(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(HeaderRateLimitLimit, "200")
+		w.Header().Set(HeaderRateLimitRemaining, "0")
+		w.Header().Set(HeaderRateLimitReset, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	ctx := WithOrganizationRecorder(context.TODO())
+	for i := 0; i < 2; i++ {
+		req, err := httplib.Get(srv.URL + "/low-org/_apis/projects")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = httplib.Fire(cli.HTTPClient(), req.WithContext(ctx), httplib.FireOptions{
+			Validators: []httplib.HandleResponseFunc{
+				httplib.CheckStatus(http.StatusOK),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 calls, got: %d", got)
+	}
+
+	if got := RecordedOrganizations(ctx); len(got) != 1 || got[0] != "low-org" {
+		t.Fatalf("unexpected recorded organizations: %v", got)
+	}
+	rl, ok := RateLimitStatus("low-org")
+	if !ok || !rl.Low() {
+		t.Fatalf("expected low rate limit status, got: %+v", rl)
+	}
+}
+
+func TestThrottlingDoesNotBlockOnServiceUnavailable(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set(HeaderRetryAfter, strconv.Itoa(3600))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cli := newThrottlingTestClient(srv)
+	for i := 0; i < 2; i++ {
+		if err := fireGet(t, cli, srv.URL+"/unavailable-org/_apis/projects"); err == nil {
+			t.Fatalf("expected service unavailable error, got: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 calls, got: %d", got)
+	}
+}
+
+func TestRateLimitPollInterval(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	table := []struct {
+		rl   RateLimit
+		want time.Duration
+	}{
+		{RateLimit{}, time.Minute},
+		{RateLimit{Limit: 200, Remaining: 150}, time.Minute},
+		{RateLimit{Limit: 200, Remaining: 20}, 2 * time.Minute},
+		{RateLimit{Limit: 200, Remaining: 0, Reset: now.Add(5 * time.Minute)}, 5 * time.Minute},
+		{RateLimit{RetryAfter: now.Add(3 * time.Minute)}, 3 * time.Minute},
+	}
+
+	for i, tc := range table {
+		if got := tc.rl.PollInterval(now, time.Minute); got != tc.want {
+			t.Fatalf("[%d] expected %v, got %v", i, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package azuredevops
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderSession is the Azure DevOps correlation header: requests sharing
+	// the same session id are grouped together in the server activity logs.
+	HeaderSession = "X-TFS-Session"
+	// HeaderActivityId is the id of the server activity returned by Azure DevOps.
+	HeaderActivityId = "ActivityId"
+)
+
+// tracingTransport is an http.RoundTripper that starts a span for each
+// call sent to Azure DevOps (spanning the throttling retries) and
+// propagates the trace context and the correlation header.
+type tracingTransport struct {
+	next   http.RoundTripper
+	uriMap map[URIKey]string
+}
+
+func newTracingTransport(next http.RoundTripper, uriMap map[URIKey]string) *tracingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &tracingTransport{
+		next:   next,
+		uriMap: uriMap,
+	}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := uriKeyOf(t.uriMap, req)
+	p := strings.TrimPrefix(req.URL.Path, basePath(t.uriMap[key]))
+	area := areaFromPath(p)
+
+	ctx, span := tracing.Start(req.Context(), fmt.Sprintf("azuredevops %s %s", req.Method, area),
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("azuredevops.uri_key", string(key)),
+		attribute.String("azuredevops.area", area),
+		attribute.String("azuredevops.organization", strings.ToLower(organizationFromPath(p))),
+	)
+	defer span.End()
+
+	// The request must not be modified by a RoundTripper.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if sc := span.SpanContext(); sc.HasTraceID() && len(req.Header.Get(HeaderSession)) == 0 {
+		req.Header.Set(HeaderSession, sessionID(sc.TraceID()))
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	if id := res.Header.Get(HeaderActivityId); len(id) > 0 {
+		span.SetAttributes(attribute.String("azuredevops.activity_id", id))
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+	}
+
+	return res, nil
+}
+
+// sessionID formats the trace id as the GUID expected by Azure DevOps.
+func sessionID(id trace.TraceID) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
@@ -0,0 +1,97 @@
+package azuredevops
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	"github.com/lucasepe/httplib"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingSpanPerCall(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	var sessions, parents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessions = append(sessions, r.Header.Get(HeaderSession))
+		parents = append(parents, r.Header.Get("traceparent"))
+		if len(sessions) < 2 {
+			w.Header().Set(HeaderRetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set(HeaderActivityId, "activity-1")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx, parent := tracing.Start(context.TODO(), "Reconcile Test")
+	req, err := httplib.Get(srv.URL + "/org/_apis/projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := newThrottlingTestClient(srv)
+	err = httplib.Fire(cli.HTTPClient(), req.WithContext(ctx), httplib.FireOptions{
+		AuthMethod: cli.AuthMethod(),
+		Validators: []httplib.HandleResponseFunc{
+			httplib.CheckStatus(http.StatusOK),
+		},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected a single span for the call, got: %d", len(spans)-1)
+	}
+	span := spans[0]
+	if span.Name() != "azuredevops GET projects" {
+		t.Fatalf("unexpected span name: %s", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected the call span to be a child of the reconcile span")
+	}
+
+	want := sessionID(parent.SpanContext().TraceID())
+	for i := range sessions {
+		if sessions[i] != want {
+			t.Errorf("attempt %d: expected session %q, got %q", i, want, sessions[i])
+		}
+		if len(parents[i]) == 0 {
+			t.Errorf("attempt %d: expected the trace context to be propagated", i)
+		}
+	}
+
+	var activity string
+	for _, kv := range span.Attributes() {
+		if kv.Key == "azuredevops.activity_id" {
+			activity = kv.Value.AsString()
+		}
+	}
+	if activity != "activity-1" {
+		t.Errorf("expected the activity id to be recorded, got: %q", activity)
+	}
+}
+
+func TestSessionID(t *testing.T) {
+	id := trace.TraceID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	if got := sessionID(id); got != "01234567-89ab-cdef-0123-456789abcdef" {
+		t.Fatalf("unexpected session id: %s", got)
+	}
+}
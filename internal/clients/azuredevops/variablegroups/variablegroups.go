@@ -13,10 +13,25 @@ import (
 	"github.com/lucasepe/httplib"
 )
 
+// Types of the variable groups.
+const (
+	TypeVsts          = "Vsts"
+	TypeAzureKeyVault = "AzureKeyVault"
+)
+
 type Variable struct {
 	Value      string `json:"value"`
 	IsSecret   bool   `json:"isSecret"`
 	IsReadOnly bool   `json:"isReadOnly"`
+	// Enabled: only for the secrets linked from an Azure Key Vault.
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ProviderData links an 'AzureKeyVault' variable group to the vault.
+type ProviderData struct {
+	ServiceEndpointId string `json:"serviceEndpointId"`
+	Vault             string `json:"vault"`
+	LastRefreshedOn   string `json:"lastRefreshedOn,omitempty"`
 }
 
 type ProjectReference struct {
@@ -35,6 +50,7 @@ type VariableGroupBody struct {
 	VariableGroupProjectReferences []VariableGroupProjectReference `json:"variableGroupProjectReferences"`
 	Name                           string                          `json:"name"`
 	Description                    string                          `json:"description"`
+	ProviderData                   *ProviderData                   `json:"providerData,omitempty"`
 }
 
 type CreatedModifiedBy struct {
@@ -54,6 +70,7 @@ type VariableGroupResponse struct {
 	ModifiedOn                     string                          `json:"modifiedOn"`
 	IsShared                       bool                            `json:"isShared"`
 	VariableGroupProjectReferences []VariableGroupProjectReference `json:"variableGroupProjectReferences"`
+	ProviderData                   *ProviderData                   `json:"providerData,omitempty"`
 }
 
 type GetOptions struct {
@@ -104,7 +121,6 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Variab
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &VariableGroupResponse{}
 
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
@@ -112,7 +128,7 @@ func Get(ctx context.Context, cli *azuredevops.Client, opts GetOptions) (*Variab
 		AuthMethod:      cli.AuthMethod(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
+			azuredevops.DecodeError(http.StatusOK),
 		},
 	})
 
@@ -158,14 +174,13 @@ func Create(ctx context.Context, cli *azuredevops.Client, opts CreateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &VariableGroupResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
@@ -186,45 +201,22 @@ type ListReturn struct {
 // Get variable groups.
 // GET https://dev.azure.com/{organization}/{project}/_apis/distributedtask/variablegroups?api-version=7.0
 func List(ctx context.Context, cli *azuredevops.Client, opts ListOptions) (*ListReturn, error) {
-	apiVersionParams, isNone := getAPIVersion(cli)
-	if len(apiVersionParams) == 0 && !isNone {
-		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
-	}
-
-	fullPath := path.Join(opts.Organization, opts.Project, "_apis/distributedtask/variablegroups")
-
-	ubo := httplib.URLBuilderOptions{
-		BaseURL: cli.BaseURL(azuredevops.Default),
-		Path:    fullPath,
-		Params:  apiVersionParams,
-	}
-
-	uri, err := httplib.NewURLBuilder(ubo).Build()
+	page, err := listPage(ctx, cli, opts, azuredevops.PageRequest{
+		ContinuationToken: helpers.String(opts.ContinuationToken),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := httplib.Get(uri.String())
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-
-	apiErr := &azuredevops.APIError{}
 	val := &ListReturn{
-		Value: []VariableGroupResponse{},
+		Count: page.Count,
+		Value: page.Value,
+	}
+	if len(page.ContinuationToken) > 0 {
+		val.ContinuationToken = helpers.StringPtr(page.ContinuationToken)
 	}
 
-	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
-		Verbose:         cli.Verbose(),
-		AuthMethod:      cli.AuthMethod(),
-		ResponseHandler: httplib.FromJSON(val),
-		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK),
-		},
-	})
-
-	return val, err
+	return val, nil
 }
 
 type FindOptions struct {
@@ -234,24 +226,27 @@ type FindOptions struct {
 
 // Find a VariableGroup by its name.
 func Find(ctx context.Context, cli *azuredevops.Client, opts FindOptions) (*VariableGroupResponse, error) {
-	for {
-		groups, err := List(ctx, cli, opts.ListOptions)
-		if err != nil {
-			return nil, err
-		}
-		for _, group := range groups.Value {
-			fmt.Println(group.Name, opts.VariableGroupName)
-			if strings.EqualFold(group.Name, opts.VariableGroupName) {
-				return &group, nil
-			}
-		}
-		if groups.ContinuationToken == nil {
-			break
-		}
-		opts.ListOptions.ContinuationToken = groups.ContinuationToken
+	pager := azuredevops.NewPager(azuredevops.ContinuationTokenPaging, 0,
+		func(ctx context.Context, pr azuredevops.PageRequest) (*azuredevops.Page[VariableGroupResponse], error) {
+			return listPage(ctx, cli, opts.ListOptions, pr)
+		})
+
+	return pager.Find(ctx, func(el VariableGroupResponse) bool {
+		return strings.EqualFold(el.Name, opts.VariableGroupName)
+	})
+}
+
+func listPage(ctx context.Context, cli *azuredevops.Client, opts ListOptions, pr azuredevops.PageRequest) (*azuredevops.Page[VariableGroupResponse], error) {
+	apiVersionParams, isNone := getAPIVersion(cli)
+	if len(apiVersionParams) == 0 && !isNone {
+		apiVersionParams = []string{azuredevops.ApiVersionKey, azuredevops.ApiVersionVal}
 	}
 
-	return nil, nil
+	return azuredevops.FetchPage[VariableGroupResponse](ctx, cli, azuredevops.FetchPageOptions{
+		Path:   path.Join(opts.Organization, opts.Project, "_apis/distributedtask/variablegroups"),
+		Params: apiVersionParams,
+		Page:   pr,
+	})
 }
 
 type DeleteOptions struct {
@@ -289,12 +284,11 @@ func Delete(ctx context.Context, cli *azuredevops.Client, opts DeleteOptions) er
 	}
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		Verbose:    cli.Verbose(),
 		AuthMethod: cli.AuthMethod(),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusNoContent),
+			azuredevops.DecodeError(http.StatusNoContent),
 		},
 	})
 
@@ -335,14 +329,13 @@ func Update(ctx context.Context, cli *azuredevops.Client, opts UpdateOptions) (*
 	req.Header.Add("Content-Type", "application/json")
 	req = req.WithContext(ctx)
 
-	apiErr := &azuredevops.APIError{}
 	val := &VariableGroupResponse{}
 	err = httplib.Fire(cli.HTTPClient(), req, httplib.FireOptions{
 		AuthMethod:      cli.AuthMethod(),
 		Verbose:         cli.Verbose(),
 		ResponseHandler: httplib.FromJSON(val),
 		Validators: []httplib.HandleResponseFunc{
-			httplib.ErrorJSON(apiErr, http.StatusOK, http.StatusCreated),
+			azuredevops.DecodeError(http.StatusOK, http.StatusCreated),
 		},
 	})
 	return val, err
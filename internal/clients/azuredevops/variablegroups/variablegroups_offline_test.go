@@ -0,0 +1,80 @@
+package variablegroups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+)
+
+func TestVariableGroupsOffline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := srv.AddProject(fake.Organization, "demo")
+	projectId := fake.String(prj["id"])
+
+	cli := srv.Client()
+	ctx := context.TODO()
+
+	body := &VariableGroupBody{
+		Name: "settings",
+		Type: "Vsts",
+		Variables: map[string]Variable{
+			"region":   {Value: "westeurope"},
+			"password": {Value: "s3cr3t", IsSecret: true},
+		},
+		VariableGroupProjectReferences: []VariableGroupProjectReference{
+			{Name: "settings", ProjectReference: ProjectReference{ID: projectId, Name: "demo"}},
+		},
+	}
+
+	vg, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, Project: projectId, VariableGroup: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.Variables["password"].Value != "" {
+		t.Fatalf("expected secret value to be hidden")
+	}
+	if _, err := Create(ctx, cli, CreateOptions{Organization: fake.Organization, Project: projectId, VariableGroup: body}); !azuredevops.IsAlreadyExists(err) {
+		t.Fatalf("expected conflict, got: %v", err)
+	}
+
+	found, err := Find(ctx, cli, FindOptions{
+		ListOptions:       ListOptions{Organization: fake.Organization, Project: projectId},
+		VariableGroupName: "settings",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.ID != vg.ID {
+		t.Fatalf("expected variable group %d, got: %v", vg.ID, found)
+	}
+
+	body.Description = "updated"
+	body.Variables["region"] = Variable{Value: "northeurope"}
+	if _, err := Update(ctx, cli, UpdateOptions{
+		Organization:    fake.Organization,
+		Project:         projectId,
+		VariableGroupId: vg.ID,
+		VariableGroup:   body,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, VariableGroupId: vg.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "updated" || got.Variables["region"].Value != "northeurope" {
+		t.Fatalf("unexpected variable group: %+v", got)
+	}
+
+	if err := Delete(ctx, cli, DeleteOptions{Organization: fake.Organization, ProjectID: projectId, VariableGroupId: vg.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, cli, GetOptions{Organization: fake.Organization, Project: projectId, VariableGroupId: vg.ID}); !azuredevops.IsNotFound(err) {
+		t.Fatalf("expected not found, got: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package azuredevops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Environment variables injected by the Azure Workload Identity webhook.
+// https://azure.github.io/azure-workload-identity/docs/quick-start.html
+const (
+	EnvAzureClientID           = "AZURE_CLIENT_ID"
+	EnvAzureTenantID           = "AZURE_TENANT_ID"
+	EnvAzureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+	EnvAzureAuthorityHost      = "AZURE_AUTHORITY_HOST"
+
+	defaultAuthorityHost = "https://login.microsoftonline.com/"
+)
+
+// WorkloadIdentityCredentials describes the federated identity used to
+// exchange the projected service account token for an access token.
+// Empty fields are read from the Azure Workload Identity environment variables.
+type WorkloadIdentityCredentials struct {
+	TenantID      string
+	ClientID      string
+	TokenFilePath string
+	TokenURL      string
+	Scope         string
+}
+
+// withDefaults returns a copy of the credentials with
+// the empty fields set from the environment.
+func (wi WorkloadIdentityCredentials) withDefaults(getenv func(string) string) WorkloadIdentityCredentials {
+	if len(wi.TenantID) == 0 {
+		wi.TenantID = getenv(EnvAzureTenantID)
+	}
+	if len(wi.ClientID) == 0 {
+		wi.ClientID = getenv(EnvAzureClientID)
+	}
+	if len(wi.TokenFilePath) == 0 {
+		wi.TokenFilePath = getenv(EnvAzureFederatedTokenFile)
+	}
+	if len(wi.TokenURL) == 0 && len(wi.TenantID) > 0 {
+		authority := getenv(EnvAzureAuthorityHost)
+		if len(authority) == 0 {
+			authority = defaultAuthorityHost
+		}
+		wi.TokenURL = strings.TrimSuffix(authority, "/") + "/" +
+			url.PathEscape(wi.TenantID) + "/oauth2/v2.0/token"
+	}
+	if len(wi.Scope) == 0 {
+		wi.Scope = AzureDevOpsScope
+	}
+	return wi
+}
+
+func (wi WorkloadIdentityCredentials) cacheKey() string {
+	return hashKey("workload-identity", wi.TenantID, wi.ClientID,
+		wi.TokenFilePath, wi.TokenURL, wi.Scope)
+}
+
+// identity identifies the application the credentials belong to
+// and the scope of its tokens.
+func (wi WorkloadIdentityCredentials) identity() string {
+	return hashKey("workload-identity", wi.TokenURL, wi.ClientID, wi.Scope)
+}
+
+// WorkloadIdentityTokenSource returns a TokenSource that exchanges the
+// projected service account token for Entra ID access tokens, caching
+// them and refreshing them before they expire.
+func WorkloadIdentityTokenSource(wi *WorkloadIdentityCredentials, httpClient *http.Client) (oauth2.TokenSource, error) {
+	if wi == nil {
+		return nil, errors.New("no workload identity credentials specified")
+	}
+
+	creds := wi.withDefaults(os.Getenv)
+	if len(creds.TokenURL) == 0 {
+		return nil, fmt.Errorf("workload identity tenantId is required (or set %s)", EnvAzureTenantID)
+	}
+	if len(creds.ClientID) == 0 {
+		return nil, fmt.Errorf("workload identity clientId is required (or set %s)", EnvAzureClientID)
+	}
+	if len(creds.TokenFilePath) == 0 {
+		return nil, fmt.Errorf("workload identity tokenFilePath is required (or set %s)", EnvAzureFederatedTokenFile)
+	}
+
+	return cachedTokenSourceFor(creds.identity(), creds.cacheKey(), httpClient, func() httpTokenSource {
+		return &workloadIdentityTokenSource{
+			creds: creds,
+			now:   time.Now,
+		}
+	}), nil
+}
+
+type workloadIdentityTokenSource struct {
+	tokenHTTPClient
+	creds WorkloadIdentityCredentials
+	now   func() time.Time
+}
+
+// Token reads the service account token (rotated by the kubelet) and
+// uses it as client assertion to request a new access token.
+// https://learn.microsoft.com/en-us/entra/identity-platform/v2-oauth2-client-creds-grant-flow#third-case-access-token-request-with-a-federated-credential
+func (ts *workloadIdentityTokenSource) Token() (*oauth2.Token, error) {
+	data, err := os.ReadFile(ts.creds.TokenFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read federated token file: %w", err)
+	}
+	assertion := strings.TrimSpace(string(data))
+	if len(assertion) == 0 {
+		return nil, fmt.Errorf("federated token file '%s' is empty", ts.creds.TokenFilePath)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", ts.creds.ClientID)
+	form.Set("scope", ts.creds.Scope)
+	form.Set("client_assertion_type", clientAssertionType)
+	form.Set("client_assertion", assertion)
+
+	return fetchToken(context.Background(), ts.httpClient(), ts.creds.TokenURL, form, ts.now)
+}
@@ -0,0 +1,150 @@
+package azuredevops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWorkloadIdentityTokenSource(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var assertions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.PostForm.Get("client_id") != "federated-app" ||
+			r.PostForm.Get("client_assertion_type") != clientAssertionType {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request"})
+			return
+		}
+		assertions = append(assertions, r.PostForm.Get("client_assertion"))
+
+		// Shorter than the expiry delta: every Token() call refreshes.
+		json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"access_token": "wi-access-token",
+			"expires_in":   60,
+		})
+	}))
+	defer srv.Close()
+
+	ts, err := WorkloadIdentityTokenSource(&WorkloadIdentityCredentials{
+		TenantID:      "tenant",
+		ClientID:      "federated-app",
+		TokenFilePath: tokenFile,
+		TokenURL:      srv.URL + "/tenant/oauth2/v2.0/token",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "wi-access-token" {
+		t.Fatalf("unexpected access token: %s", tok.AccessToken)
+	}
+
+	// The kubelet rotates the projected token.
+	if err := os.WriteFile(tokenFile, []byte("sa-token-2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(assertions) != 2 || assertions[0] != "sa-token-1" || assertions[1] != "sa-token-2" {
+		t.Fatalf("unexpected client assertions: %v", assertions)
+	}
+}
+
+func TestWorkloadIdentityTransportChange(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"token_type":   "Bearer",
+			"access_token": "token-" + r.PostForm.Get("scope"),
+			"expires_in":   60,
+		})
+	}))
+	defer srv.Close()
+
+	before := cachedTokenSources()
+	var first, second int32
+	token := func(scope string, calls *int32) string {
+		ts, err := WorkloadIdentityTokenSource(&WorkloadIdentityCredentials{
+			TenantID:      "tenant",
+			ClientID:      "transport-federated-app",
+			TokenFilePath: tokenFile,
+			TokenURL:      srv.URL,
+			Scope:         scope,
+		}, &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(calls, 1)
+			return http.DefaultTransport.RoundTrip(req)
+		})})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok.AccessToken
+	}
+
+	token("scope-a", &first)
+	token("scope-a", &second)
+	if atomic.LoadInt32(&first) != 1 || atomic.LoadInt32(&second) != 1 {
+		t.Fatalf("expected a token request per client, got: %d, %d", first, second)
+	}
+
+	// The tokens of the scopes are cached apart.
+	if a, b := token("scope-a", &second), token("scope-b", &second); a != "token-scope-a" || b != "token-scope-b" {
+		t.Fatalf("unexpected tokens: %s, %s", a, b)
+	}
+	if got := cachedTokenSources() - before; got != 2 {
+		t.Fatalf("expected 2 cached token sources, got: %d", got)
+	}
+}
+
+func TestWorkloadIdentityDefaults(t *testing.T) {
+	env := map[string]string{
+		EnvAzureTenantID:           "env-tenant",
+		EnvAzureClientID:           "env-client",
+		EnvAzureFederatedTokenFile: "/var/run/secrets/azure/tokens/azure-identity-token",
+		EnvAzureAuthorityHost:      "https://login.example.com/",
+	}
+
+	got := WorkloadIdentityCredentials{ClientID: "spec-client"}.
+		withDefaults(func(key string) string { return env[key] })
+
+	if got.TenantID != "env-tenant" || got.ClientID != "spec-client" {
+		t.Fatalf("unexpected identity: %+v", got)
+	}
+	if got.TokenURL != "https://login.example.com/env-tenant/oauth2/v2.0/token" {
+		t.Fatalf("unexpected token url: %s", got.TokenURL)
+	}
+	if got.TokenFilePath != env[EnvAzureFederatedTokenFile] || got.Scope != AzureDevOpsScope {
+		t.Fatalf("unexpected defaults: %+v", got)
+	}
+}
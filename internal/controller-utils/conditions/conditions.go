@@ -0,0 +1,188 @@
+// Package conditions maps the Azure DevOps errors returned by the external
+// clients to the reason of the Synced condition of the managed resources.
+//
+// The managed reconciler always reports the external clients errors with
+// the generic ReconcileError reason: the Mapper records the reason of each
+// failed operation and sets it on the next status update of the resource.
+package conditions
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the Synced condition for the classified Azure DevOps errors.
+const (
+	ReasonNotFound         rtv1.ConditionReason = "NotFound"
+	ReasonAlreadyExists    rtv1.ConditionReason = "AlreadyExists"
+	ReasonConflict         rtv1.ConditionReason = "Conflict"
+	ReasonUnauthorized     rtv1.ConditionReason = "Unauthorized"
+	ReasonPermissionDenied rtv1.ConditionReason = "PermissionDenied"
+	ReasonThrottled        rtv1.ConditionReason = "Throttled"
+	ReasonInvalidSpec      rtv1.ConditionReason = "InvalidSpec"
+	// ReasonConnectorNotReady is set when the referenced ConnectorConfig
+	// failed its last validation.
+	ReasonConnectorNotReady rtv1.ConditionReason = "ConnectorNotReady"
+)
+
+// ReasonFor returns the condition reason of the error; errors
+// not classified return the generic ReconcileError reason.
+func ReasonFor(err error) rtv1.ConditionReason {
+	var notReady *resolvers.ConnectorNotReadyError
+	switch {
+	case errors.As(err, &notReady):
+		return ReasonConnectorNotReady
+	case azuredevops.IsThrottled(err):
+		return ReasonThrottled
+	case azuredevops.IsUnauthorized(err):
+		return ReasonUnauthorized
+	case azuredevops.IsForbidden(err):
+		return ReasonPermissionDenied
+	case azuredevops.IsConflict(err):
+		return ReasonConflict
+	case azuredevops.IsAlreadyExists(err):
+		return ReasonAlreadyExists
+	case azuredevops.IsNotFound(err):
+		return ReasonNotFound
+	case azuredevops.IsValidation(err):
+		return ReasonInvalidSpec
+	}
+	return rtv1.ReasonReconcileError
+}
+
+// ReconcileError returns the ReconcileError condition of the error
+// with the reason returned by ReasonFor.
+func ReconcileError(err error) rtv1.Condition {
+	cond := rtv1.ReconcileError(err)
+	cond.Reason = ReasonFor(err)
+	return cond
+}
+
+// Mapper sets the reason of the ReconcileError conditions caused
+// by the errors of the external clients.
+type Mapper struct {
+	// reasons of the last failed operation by managed resource UID.
+	reasons sync.Map
+}
+
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// Manager returns a manager whose client sets the recorded reasons
+// on the status updates of the managed resources.
+func (m *Mapper) Manager(mgr ctrl.Manager) ctrl.Manager {
+	return &manager{Manager: mgr, client: &kubeClient{Client: mgr.GetClient(), mapper: m}}
+}
+
+// NewExternalConnecter returns an ExternalConnecter recording the
+// reason of the errors returned by the supplied one.
+func (m *Mapper) NewExternalConnecter(c reconciler.ExternalConnecter) reconciler.ExternalConnecter {
+	return &connecter{mapper: m, next: c}
+}
+
+func (m *Mapper) record(mg resource.Managed, err error) {
+	if err == nil {
+		m.reasons.Delete(mg.GetUID())
+		return
+	}
+	if reason := ReasonFor(err); reason != rtv1.ReasonReconcileError {
+		m.reasons.Store(mg.GetUID(), reason)
+	}
+}
+
+// apply sets the recorded reason on the ReconcileError condition.
+func (m *Mapper) apply(obj client.Object) {
+	mg, ok := obj.(resource.Managed)
+	if !ok {
+		return
+	}
+	reason, ok := m.reasons.LoadAndDelete(mg.GetUID())
+	if !ok {
+		return
+	}
+	if cond := mg.GetCondition(rtv1.TypeSynced); cond.Reason == rtv1.ReasonReconcileError {
+		cond.Reason = reason.(rtv1.ConditionReason)
+		mg.SetConditions(cond)
+	}
+}
+
+type manager struct {
+	ctrl.Manager
+	client client.Client
+}
+
+func (m *manager) GetClient() client.Client {
+	return m.client
+}
+
+type kubeClient struct {
+	client.Client
+	mapper *Mapper
+}
+
+func (c *kubeClient) Status() client.SubResourceWriter {
+	return &statusWriter{SubResourceWriter: c.Client.Status(), mapper: c.mapper}
+}
+
+type statusWriter struct {
+	client.SubResourceWriter
+	mapper *Mapper
+}
+
+func (w *statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.mapper.apply(obj)
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+type connecter struct {
+	mapper *Mapper
+	next   reconciler.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (reconciler.ExternalClient, error) {
+	ext, err := c.next.Connect(ctx, mg)
+	c.mapper.record(mg, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{mapper: c.mapper, next: ext}, nil
+}
+
+type external struct {
+	mapper *Mapper
+	next   reconciler.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler.ExternalObservation, error) {
+	obs, err := e.next.Observe(ctx, mg)
+	e.mapper.record(mg, err)
+	return obs, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Create(ctx, mg)
+	e.mapper.record(mg, err)
+	return err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Update(ctx, mg)
+	e.mapper.record(mg, err)
+	return err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Delete(ctx, mg)
+	e.mapper.record(mg, err)
+	return err
+}
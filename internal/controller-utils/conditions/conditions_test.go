@@ -0,0 +1,111 @@
+package conditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/projects"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+)
+
+func TestReasonFor(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	srv.Handle(http.MethodGet, "{org}/_apis/projects/{project}", func(c *fake.Call) {
+		c.Error(http.StatusForbidden, "AccessCheckException", "access denied")
+	})
+
+	_, err := projects.Get(context.TODO(), srv.Client(), projects.GetOptions{
+		Organization: fake.Organization,
+		ProjectId:    "demo",
+	})
+	if got := ReasonFor(err); got != ReasonPermissionDenied {
+		t.Fatalf("expected %s, got: %s", ReasonPermissionDenied, got)
+	}
+	err = fmt.Errorf("cannot create client options: %w", &resolvers.ConnectorNotReadyError{Name: "default", Reason: "Unauthorized"})
+	if got := ReasonFor(err); got != ReasonConnectorNotReady {
+		t.Fatalf("expected %s, got: %s", ReasonConnectorNotReady, got)
+	}
+	if got := ReasonFor(errors.New("boom")); got != rtv1.ReasonReconcileError {
+		t.Fatalf("expected %s, got: %s", rtv1.ReasonReconcileError, got)
+	}
+}
+
+func TestMapperSetsReasonOnStatusUpdate(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	srv.Handle(http.MethodPost, "{org}/_apis/projects", func(c *fake.Call) {
+		c.Error(http.StatusBadRequest, "ProjectAlreadyExistsException", "project already exists")
+	})
+
+	cr := &projectsv1alpha1.TeamProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: controllertest.Namespace, UID: "demo-uid"},
+		Spec: projectsv1alpha1.TeamProjectSpec{
+			Organization: fake.Organization,
+			Name:         "Demo",
+		},
+	}
+	kube := controllertest.NewKube(t, srv, cr)
+
+	m := NewMapper()
+	c := m.NewExternalConnecter(reconciler.ExternalConnectorFn(
+		func(context.Context, resource.Managed) (reconciler.ExternalClient, error) {
+			return reconciler.ExternalClientFns{
+				CreateFn: func(ctx context.Context, mg resource.Managed) error {
+					_, err := projects.Create(ctx, srv.Client(), projects.CreateOptions{
+						Organization: fake.Organization,
+						TeamProject:  &projects.TeamProject{Name: "Demo"},
+					})
+					return err
+				},
+			}, nil
+		}))
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ext.Create(ctx, cr)
+	if err == nil {
+		t.Fatal("expected the create to fail")
+	}
+
+	// As done by the managed reconciler.
+	cr.SetConditions(rtv1.ReconcileError(err))
+	cli := &kubeClient{Client: kube, mapper: m}
+	if err := cli.Status().Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &projectsv1alpha1.TeamProject{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}, got); err != nil {
+		t.Fatal(err)
+	}
+	if cond := got.GetCondition(rtv1.TypeSynced); cond.Reason != ReasonAlreadyExists {
+		t.Fatalf("expected reason %s, got: %s", ReasonAlreadyExists, cond.Reason)
+	}
+
+	// The reason is applied once.
+	cr.SetConditions(rtv1.ReconcileError(errors.New("boom")))
+	if err := cli.Status().Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cond := cr.GetCondition(rtv1.TypeSynced); cond.Reason != rtv1.ReasonReconcileError {
+		t.Fatalf("expected reason %s, got: %s", rtv1.ReasonReconcileError, cond.Reason)
+	}
+}
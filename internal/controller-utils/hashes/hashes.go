@@ -0,0 +1,23 @@
+// Package hashes computes the hashes of the secret values sent to Azure
+// DevOps: since they are never returned, the managed resources keep the
+// hashes of the values last sent in their status to detect the changes.
+package hashes
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Secret returns the HMAC-SHA256 of the named value keyed by the UID of
+// the managed resource: the status must not disclose the plain hash of
+// the secrets.
+func Secret(uid types.UID, name, value string) string {
+	mac := hmac.New(sha256.New, []byte(uid))
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
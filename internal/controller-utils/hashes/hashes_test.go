@@ -0,0 +1,30 @@
+package hashes
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSecret(t *testing.T) {
+	h := Secret("uid-1", "password", "s3cr3t")
+	if len(h) != 64 {
+		t.Fatalf("unexpected hash length: %d", len(h))
+	}
+	if Secret("uid-1", "password", "s3cr3t") != h {
+		t.Fatal("expected stable hash")
+	}
+
+	for _, tc := range []struct {
+		uid, name, value string
+	}{
+		{"uid-2", "password", "s3cr3t"},
+		{"uid-1", "token", "s3cr3t"},
+		{"uid-1", "password", "other"},
+		{"uid-1", "password\x00s3", "cr3t"},
+	} {
+		if Secret(types.UID(tc.uid), tc.name, tc.value) == h {
+			t.Fatalf("expected different hash for %+v", tc)
+		}
+	}
+}
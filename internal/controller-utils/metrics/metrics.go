@@ -0,0 +1,92 @@
+// Package metrics records the outcome of the managed resources external operations.
+package metrics
+
+import (
+	"context"
+
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	OperationConnect = "connect"
+	OperationObserve = "observe"
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var externalOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "azuredevops",
+	Subsystem: "managed_resource",
+	Name:      "external_operations_total",
+	Help:      "Total number of external operations by managed resource kind, operation and result.",
+}, []string{"kind", "operation", "result"})
+
+func init() {
+	// Exposed by the controller-runtime metrics server.
+	ctrlmetrics.Registry.MustRegister(externalOperations)
+}
+
+// Record records the outcome of an external operation.
+func Record(kind, operation string, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	externalOperations.WithLabelValues(kind, operation, result).Inc()
+}
+
+// NewExternalConnecter returns an ExternalConnecter recording the outcome
+// of the operations of the supplied one for the specified kind.
+func NewExternalConnecter(kind string, c reconciler.ExternalConnecter) reconciler.ExternalConnecter {
+	return &connecter{kind: kind, next: c}
+}
+
+type connecter struct {
+	kind string
+	next reconciler.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (reconciler.ExternalClient, error) {
+	ext, err := c.next.Connect(ctx, mg)
+	Record(c.kind, OperationConnect, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kind: c.kind, next: ext}, nil
+}
+
+type external struct {
+	kind string
+	next reconciler.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler.ExternalObservation, error) {
+	obs, err := e.next.Observe(ctx, mg)
+	Record(e.kind, OperationObserve, err)
+	return obs, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Create(ctx, mg)
+	Record(e.kind, OperationCreate, err)
+	return err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Update(ctx, mg)
+	Record(e.kind, OperationUpdate, err)
+	return err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	err := e.next.Delete(ctx, mg)
+	Record(e.kind, OperationDelete, err)
+	return err
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestExternalConnecterRecordsOutcomes(t *testing.T) {
+	errBoom := errors.New("boom")
+	ext := reconciler.ExternalClientFns{
+		ObserveFn: func(context.Context, resource.Managed) (reconciler.ExternalObservation, error) {
+			return reconciler.ExternalObservation{ResourceExists: true}, nil
+		},
+		CreateFn: func(context.Context, resource.Managed) error { return nil },
+		UpdateFn: func(context.Context, resource.Managed) error { return errBoom },
+		DeleteFn: func(context.Context, resource.Managed) error { return nil },
+	}
+	c := NewExternalConnecter("Test", reconciler.ExternalConnectorFn(
+		func(context.Context, resource.Managed) (reconciler.ExternalClient, error) {
+			return ext, nil
+		}))
+
+	ctx := context.TODO()
+	cli, err := c.Connect(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs, _ := cli.Observe(ctx, nil); !obs.ResourceExists {
+		t.Fatal("expected the observation to be returned")
+	}
+	if err := cli.Update(ctx, nil); !errors.Is(err, errBoom) {
+		t.Fatalf("expected the update error to be returned, got: %v", err)
+	}
+
+	tests := []struct {
+		operation, result string
+		want              float64
+	}{
+		{OperationConnect, ResultSuccess, 1},
+		{OperationObserve, ResultSuccess, 1},
+		{OperationUpdate, ResultError, 1},
+		{OperationUpdate, ResultSuccess, 0},
+		{OperationCreate, ResultSuccess, 0},
+	}
+	for _, tc := range tests {
+		got := testutil.ToFloat64(externalOperations.WithLabelValues("Test", tc.operation, tc.result))
+		if got != tc.want {
+			t.Errorf("%s/%s: expected %v, got %v", tc.operation, tc.result, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package throttling lengthens the poll interval of the managed resources
+// while Azure DevOps reports that the organizations they belong to are
+// running low on request units (TSTUs), and requeues the throttled ones
+// after the Retry-After wait.
+package throttling
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler returns a Reconciler requeueing the resources reconciled
+// by the supplied one after the poll interval of the rate limit status of
+// the organizations they called. The reconciles failed because throttled
+// are requeued after the Retry-After wait rather than with the backoff of
+// the rate limiter.
+func NewReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return &throttlingReconciler{next: r, now: time.Now}
+}
+
+type throttlingReconciler struct {
+	next reconcile.Reconciler
+	now  func() time.Time
+}
+
+func (r *throttlingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx = azuredevops.WithOrganizationRecorder(ctx)
+
+	res, err := r.next.Reconcile(ctx, req)
+	if err != nil || res.Requeue && res.RequeueAfter <= 0 {
+		// The managed reconciler reports the failures in the status
+		// and requeues: the throttling is recorded by the context.
+		wait := azuredevops.RecordedRetryAfter(ctx)
+		var te *azuredevops.ThrottledError
+		if errors.As(err, &te) && te.RetryAfter > wait {
+			wait = te.RetryAfter
+		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+		return res, err
+	}
+	if res.RequeueAfter <= 0 {
+		return res, nil
+	}
+
+	now := r.now()
+	interval := res.RequeueAfter
+	for _, org := range azuredevops.RecordedOrganizations(ctx) {
+		if rl, ok := azuredevops.RateLimitStatus(org); ok {
+			if val := rl.PollInterval(now, interval); val > res.RequeueAfter {
+				res.RequeueAfter = val
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,137 @@
+package throttling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/lucasepe/httplib"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcilerLengthensPollInterval(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(azuredevops.HeaderRateLimitResource, "ATCPU")
+		w.Header().Set(azuredevops.HeaderRateLimitLimit, "200")
+		if r.URL.Path == "/busy-org/_apis/projects" {
+			w.Header().Set(azuredevops.HeaderRateLimitRemaining, "5")
+		} else {
+			w.Header().Set(azuredevops.HeaderRateLimitRemaining, "190")
+		}
+		w.Header().Set(azuredevops.HeaderRateLimitReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli := azuredevops.NewClient(azuredevops.ClientOptions{
+		UriMap: &map[azuredevops.URIKey]string{azuredevops.Default: srv.URL},
+	})
+
+	table := []struct {
+		org  string
+		want func(time.Duration) bool
+	}{
+		{"idle-org", func(d time.Duration) bool { return d == time.Minute }},
+		{"busy-org", func(d time.Duration) bool { return d > 9*time.Minute }},
+	}
+
+	for _, tc := range table {
+		r := NewReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+			req, err := httplib.Get(srv.URL + "/" + tc.org + "/_apis/projects")
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			err = httplib.Fire(cli.HTTPClient(), req.WithContext(ctx), httplib.FireOptions{
+				Validators: []httplib.HandleResponseFunc{
+					httplib.CheckStatus(http.StatusOK),
+				},
+			})
+			return reconcile.Result{RequeueAfter: time.Minute}, err
+		}))
+
+		res, err := r.Reconcile(context.TODO(), reconcile.Request{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tc.want(res.RequeueAfter) {
+			t.Fatalf("[%s] unexpected requeue: %s", tc.org, res.RequeueAfter)
+		}
+	}
+}
+
+func TestReconcilerRequeuesThrottledAfterRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(azuredevops.HeaderRetryAfter, "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cli := azuredevops.NewClient(azuredevops.ClientOptions{
+		UriMap: &map[azuredevops.URIKey]string{azuredevops.Default: srv.URL},
+	})
+
+	table := []struct {
+		name string
+		fn   func(ctx context.Context) (reconcile.Result, error)
+		want reconcile.Result
+		err  bool
+	}{
+		{
+			// The managed reconciler reports the error in the status.
+			name: "throttled request",
+			fn: func(ctx context.Context) (reconcile.Result, error) {
+				req, err := httplib.Get(srv.URL + "/throttled-org/_apis/projects")
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+				err = httplib.Fire(cli.HTTPClient(), req.WithContext(ctx), httplib.FireOptions{
+					Validators: []httplib.HandleResponseFunc{
+						httplib.CheckStatus(http.StatusOK),
+					},
+				})
+				if err == nil {
+					t.Fatal("expected throttled request to fail")
+				}
+				return reconcile.Result{Requeue: true}, nil
+			},
+			want: reconcile.Result{RequeueAfter: 2 * time.Minute},
+		},
+		{
+			name: "throttled error",
+			fn: func(context.Context) (reconcile.Result, error) {
+				return reconcile.Result{}, fmt.Errorf("cannot observe: %w",
+					&azuredevops.ThrottledError{Organization: "other-org", RetryAfter: 30 * time.Second})
+			},
+			want: reconcile.Result{RequeueAfter: 30 * time.Second},
+		},
+		{
+			name: "other error",
+			fn: func(context.Context) (reconcile.Result, error) {
+				return reconcile.Result{Requeue: true}, nil
+			},
+			want: reconcile.Result{Requeue: true},
+		},
+	}
+
+	for _, tc := range table {
+		r := NewReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+			return tc.fn(ctx)
+		}))
+
+		res, err := r.Reconcile(context.TODO(), reconcile.Request{})
+		if err != nil {
+			t.Fatalf("[%s] unexpected error: %v", tc.name, err)
+		}
+		// The Retry-After wait is counted from the response.
+		if res.Requeue != tc.want.Requeue || res.RequeueAfter > tc.want.RequeueAfter ||
+			res.RequeueAfter < tc.want.RequeueAfter-5*time.Second {
+			t.Fatalf("[%s] unexpected result: %+v", tc.name, res)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/checkconfigurations"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/connectorconfig"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/endpoints"
 	environments "github.com/krateoplatformops/azuredevops-provider/internal/controllers/enviroments"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/feedpermissions"
@@ -19,6 +20,7 @@ import (
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/repository"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/repositorypermissions"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/run"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/runschedules"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/securefiles"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/teams"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/users"
@@ -29,10 +31,12 @@ import (
 // the supplied manager.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	for _, setup := range []func(ctrl.Manager, controller.Options) error{
+		connectorconfig.Setup,
 		project.Setup,
 		repository.Setup,
 		pipeline.Setup,
 		run.Setup,
+		runschedules.Setup,
 		pipelinepermissions.Setup,
 		feeds.Setup,
 		queues.Setup,
@@ -18,7 +18,11 @@ import (
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/checkconfiguration"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/graphs/groups"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/graphs/users"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
 	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
@@ -67,13 +71,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(checkconfigurations1alpha1.CheckConfigurationGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(checkconfigurations1alpha1.CheckConfigurationKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -82,7 +88,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&checkconfigurations1alpha1.CheckConfiguration{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(checkconfigurations1alpha1.CheckConfigurationKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -0,0 +1,107 @@
+package checkconfigurations
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	checkconfigurationsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/checkconfigurations/v1alpha1"
+	environmentsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/environments/v1alpha1"
+	usersv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/users/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/environments"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestCheckConfigurationLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+
+	ctx := context.TODO()
+	res, err := environments.Create(ctx, srv.Client(), environments.CreateOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		Environment:  &environments.Environment{Name: helpers.StringPtr("production")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := &environmentsv1alpha1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: controllertest.Namespace},
+		Status:     environmentsv1alpha1.EnvironmentStatus{Id: res.Id},
+	}
+	usr := &usersv1alpha1.Users{
+		ObjectMeta: metav1.ObjectMeta{Name: "jdoe", Namespace: controllertest.Namespace},
+		Status: usersv1alpha1.UsersStatus{
+			Descriptor: helpers.StringPtr(fake.String(srv.AddUser(fake.Organization, "jdoe@fake.onmicrosoft.com")["descriptor"])),
+		},
+	}
+	cr := &checkconfigurationsv1alpha1.CheckConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "production-approval", Namespace: controllertest.Namespace},
+		Spec: checkconfigurationsv1alpha1.CheckConfigurationSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Type:               string(CheckConfigurationTypeApproval),
+			Resource: checkconfigurationsv1alpha1.Resource{
+				Type:        "Environment",
+				ResourceRef: controllertest.Ref(env),
+			},
+			Timeout: 60,
+			ApprovalSettings: checkconfigurationsv1alpha1.ApprovalSettings{
+				Approvers: []checkconfigurationsv1alpha1.Approver{
+					{ApproverRef: controllertest.Ref(usr)},
+				},
+				MinRequiredApprovers: 1,
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, env, usr, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected check configuration not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.ID == nil {
+		t.Fatal("expected check configuration identifier")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected check configuration to be deleted")
+	}
+}
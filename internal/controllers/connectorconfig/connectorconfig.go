@@ -0,0 +1,286 @@
+package connectorconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lucasepe/httplib"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	connectorconfigs "github.com/krateoplatformops/azuredevops-provider/apis/connectorconfigs/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/connectiondata"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/controller"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
+)
+
+const (
+	// DefaultCheckInterval is how often a ready connector is validated.
+	DefaultCheckInterval = 5 * time.Minute
+	// NotReadyCheckInterval is the maximum time before a connector
+	// that is not ready is validated again.
+	NotReadyCheckInterval = 30 * time.Second
+
+	checkTimeout = 30 * time.Second
+
+	AuthMethodPersonalAccessToken = "PersonalAccessToken"
+	AuthMethodServicePrincipal    = "ServicePrincipal"
+	AuthMethodWorkloadIdentity    = "WorkloadIdentity"
+)
+
+// probedEndpoints are the ReST API base URLs validated by the health check.
+var probedEndpoints = []azuredevops.URIKey{
+	azuredevops.Default,
+	azuredevops.Feeds,
+	azuredevops.Vssps,
+}
+
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "config/" + strings.ToLower(connectorconfigs.ConnectorConfigGroupKind)
+
+	log := o.Logger.WithValues("controller", name)
+
+	r := &Reconciler{
+		kube:      mgr.GetClient(),
+		log:       log,
+		rec:       mgr.GetEventRecorderFor(name),
+		newClient: azuredevops.NewClient,
+	}
+
+	// Status updates do not change the generation:
+	// the periodic checks are driven by RequeueAfter.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&connectorconfigs.ConnectorConfig{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(connectorconfigs.ConnectorConfigKind, r), o.GlobalRateLimiter))
+}
+
+// Reconciler periodically validates ConnectorConfigs and reports
+// the outcome in their status.
+type Reconciler struct {
+	kube      client.Client
+	log       logging.Logger
+	rec       record.EventRecorder
+	newClient func(azuredevops.ClientOptions) *azuredevops.Client
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cfg := &connectorconfigs.ConnectorConfig{}
+	if err := r.kube.Get(ctx, req.NamespacedName, cfg); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if cfg.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	log := r.log.WithValues("name", cfg.Name)
+
+	prev := cfg.GetCondition(rtv1.TypeReady)
+
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	cond := r.check(checkCtx, cfg)
+
+	now := metav1.Now()
+	cfg.Status.LastCheckTime = &now
+	cfg.Status.ObservedGeneration = cfg.Generation
+	cfg.SetConditions(cond)
+
+	if err := r.kube.Status().Update(ctx, cfg); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if prev.Status != cond.Status || prev.Reason != cond.Reason {
+		if cond.Status == metav1.ConditionTrue {
+			log.Debug("Connector is ready", "authMethod", cfg.Status.AuthMethod)
+			r.rec.Eventf(cfg, corev1.EventTypeNormal, "ConnectorReady", "Connector is ready (%s)", cfg.Status.AuthMethod)
+		} else {
+			log.Info("Connector is not ready", "reason", cond.Reason, "message", cond.Message)
+			r.rec.Eventf(cfg, corev1.EventTypeWarning, string(cond.Reason), "Connector is not ready: %s", cond.Message)
+		}
+	}
+
+	interval := DefaultCheckInterval
+	if hc := cfg.Spec.HealthCheck; hc != nil && hc.Interval != nil && hc.Interval.Duration > 0 {
+		interval = hc.Interval.Duration
+	}
+	if cond.Status != metav1.ConditionTrue && interval > NotReadyCheckInterval {
+		interval = NotReadyCheckInterval
+	}
+
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+// check validates the connector updating the status fields
+// and returns the resulting Ready condition.
+func (r *Reconciler) check(ctx context.Context, cfg *connectorconfigs.ConnectorConfig) rtv1.Condition {
+	cfg.Status.AuthMethod = authMethod(cfg)
+	cfg.Status.AuthenticatedUser = ""
+	cfg.Status.AuthenticatedUserId = ""
+	cfg.Status.TokenExpiry = nil
+	cfg.Status.Endpoints = nil
+
+	opts, err := resolvers.ResolveConnectorConfigSpec(ctx, r.kube, cfg)
+	if err != nil {
+		return connectorconfigs.NotReady(connectorconfigs.ReasonInvalidConfig, err.Error())
+	}
+
+	if opts.TokenSource != nil {
+		tok, err := opts.TokenSource.Token()
+		if err != nil {
+			var tokErr *azuredevops.TokenError
+			if errors.As(err, &tokErr) && tokErr.StatusCode < http.StatusInternalServerError {
+				return connectorconfigs.NotReady(connectorconfigs.ReasonUnauthorized, err.Error())
+			}
+			return connectorconfigs.NotReady(connectorconfigs.ReasonUnreachable, err.Error())
+		}
+		if !tok.Expiry.IsZero() {
+			cfg.Status.TokenExpiry = &metav1.Time{Time: tok.Expiry}
+		}
+	}
+
+	var organization string
+	if hc := cfg.Spec.HealthCheck; hc != nil {
+		organization = hc.Organization
+	}
+	if len(organization) == 0 {
+		return rtv1.Available().WithMessage("credentials resolved; set spec.healthCheck.organization to probe the endpoints")
+	}
+
+	cli := r.newClient(opts)
+
+	var failure *rtv1.Condition
+	var degraded []string
+	for _, key := range probedEndpoints {
+		res, sta, cond := probe(ctx, cli, key, organization)
+		cfg.Status.Endpoints = append(cfg.Status.Endpoints, sta)
+
+		if cond != nil {
+			// An unreachable secondary endpoint only affects the
+			// resources using it: it is reported in status.endpoints.
+			if key != azuredevops.Default && cond.Reason == connectorconfigs.ReasonUnreachable {
+				degraded = append(degraded, cond.Message)
+				continue
+			}
+			if failure == nil || severity(cond.Reason) > severity(failure.Reason) {
+				failure = cond
+			}
+			continue
+		}
+
+		if key == azuredevops.Default && res != nil && res.AuthenticatedUser != nil {
+			cfg.Status.AuthenticatedUser = res.AuthenticatedUser.ProviderDisplayName
+			cfg.Status.AuthenticatedUserId = res.AuthenticatedUser.Id
+		}
+	}
+	if failure != nil {
+		return *failure
+	}
+	if len(degraded) > 0 {
+		return rtv1.Available().WithMessage(strings.Join(degraded, "; "))
+	}
+
+	return rtv1.Available()
+}
+
+// probe calls the connection data endpoint of the service and returns
+// a not nil condition if the service cannot be used.
+func probe(ctx context.Context, cli *azuredevops.Client, key azuredevops.URIKey, organization string) (*connectiondata.ConnectionData, connectorconfigs.EndpointStatus, *rtv1.Condition) {
+	sta := connectorconfigs.EndpointStatus{
+		Name: string(key),
+		URL:  strings.TrimSuffix(cli.BaseURL(key), "/") + "/" + organization,
+	}
+
+	res, err := connectiondata.Get(ctx, cli, connectiondata.GetOptions{
+		URIKey:       key,
+		Organization: organization,
+	})
+	if err != nil {
+		sta.Message = err.Error()
+
+		var reason rtv1.ConditionReason
+		statusErr := &httplib.StatusError{}
+		switch {
+		case azuredevops.IsThrottled(err):
+			// Throttled means reachable and authenticated.
+			sta.Reachable = true
+			return nil, sta, nil
+		case errors.As(err, &statusErr):
+			sta.Reachable = true
+			sta.StatusCode = statusErr.StatusCode
+			switch statusErr.StatusCode {
+			case http.StatusUnauthorized, http.StatusNonAuthoritativeInfo:
+				reason = connectorconfigs.ReasonUnauthorized
+			case http.StatusForbidden:
+				reason = connectorconfigs.ReasonForbidden
+			case http.StatusNotFound:
+				reason = connectorconfigs.ReasonInvalidConfig
+				sta.Message = fmt.Sprintf("organization '%s' not found", organization)
+			default:
+				reason = connectorconfigs.ReasonUnreachable
+			}
+		default:
+			reason = connectorconfigs.ReasonUnreachable
+		}
+
+		cond := connectorconfigs.NotReady(reason, fmt.Sprintf("%s endpoint: %s", key, sta.Message))
+		return nil, sta, &cond
+	}
+
+	sta.Reachable = true
+	sta.StatusCode = http.StatusOK
+
+	// The connection data endpoint answers anonymous requests too.
+	if usr := res.AuthenticatedUser; usr == nil || len(usr.Id) == 0 ||
+		strings.EqualFold(usr.ProviderDisplayName, "Anonymous") {
+		sta.Message = "request was not authenticated"
+		cond := connectorconfigs.NotReady(connectorconfigs.ReasonUnauthorized,
+			fmt.Sprintf("%s endpoint: %s", key, sta.Message))
+		return nil, sta, &cond
+	}
+
+	return res, sta, nil
+}
+
+// severity orders the failure reasons: the most relevant is reported.
+func severity(reason rtv1.ConditionReason) int {
+	switch reason {
+	case connectorconfigs.ReasonUnauthorized:
+		return 4
+	case connectorconfigs.ReasonForbidden:
+		return 3
+	case connectorconfigs.ReasonInvalidConfig:
+		return 2
+	case connectorconfigs.ReasonUnreachable:
+		return 1
+	}
+	return 0
+}
+
+func authMethod(cfg *connectorconfigs.ConnectorConfig) string {
+	switch {
+	case cfg.Spec.WorkloadIdentity != nil:
+		return AuthMethodWorkloadIdentity
+	case cfg.Spec.ServicePrincipal != nil:
+		return AuthMethodServicePrincipal
+	default:
+		return AuthMethodPersonalAccessToken
+	}
+}
@@ -0,0 +1,215 @@
+package connectorconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	connectorconfigs "github.com/krateoplatformops/azuredevops-provider/apis/connectorconfigs/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func newTestReconciler(t *testing.T, srvURL, token string) (*Reconciler, client.Client) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := connectorconfigs.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &connectorconfigs.ConnectorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "connector", Generation: 1},
+		Spec: connectorconfigs.ConnectorConfigSpec{
+			ApiUrl: srvURL,
+			ApiUrls: &connectorconfigs.ApiUrl{
+				Defautl: srvURL,
+				Feeds:   srvURL,
+				Vssps:   srvURL,
+			},
+			Credentials: &rtv1.CredentialSelectors{
+				SecretRef: &rtv1.SecretKeySelector{
+					Reference: rtv1.Reference{Name: "pat", Namespace: "default"},
+					Key:       "token",
+				},
+			},
+			HealthCheck: &connectorconfigs.HealthCheck{Organization: "org"},
+		},
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pat", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte(token)},
+	}
+
+	kube := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cfg, sec).
+		WithStatusSubresource(cfg).
+		Build()
+
+	return &Reconciler{
+		kube:      kube,
+		log:       logging.NewNopLogger(),
+		rec:       record.NewFakeRecorder(10),
+		newClient: azuredevops.NewClient,
+	}, kube
+}
+
+func newConnectionDataServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/_apis/connectionData" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, pwd, _ := r.BasicAuth(); pwd != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"authenticatedUser": map[string]any{
+				"id":                  "user-id",
+				"providerDisplayName": "Build Bot",
+			},
+		})
+	}))
+}
+
+func TestReconcileReady(t *testing.T) {
+	srv := newConnectionDataServer()
+	defer srv.Close()
+
+	r, kube := newTestReconciler(t, srv.URL, "valid")
+
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "connector"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != DefaultCheckInterval {
+		t.Fatalf("unexpected requeue: %s", res.RequeueAfter)
+	}
+
+	cfg := &connectorconfigs.ConnectorConfig{}
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: "connector"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cond := cfg.GetCondition(rtv1.TypeReady); cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected ready connector, got: %+v", cond)
+	}
+	if cfg.Status.AuthenticatedUser != "Build Bot" || cfg.Status.AuthMethod != AuthMethodPersonalAccessToken {
+		t.Fatalf("unexpected status: %+v", cfg.Status)
+	}
+	if len(cfg.Status.Endpoints) != len(probedEndpoints) {
+		t.Fatalf("expected %d endpoints, got: %d", len(probedEndpoints), len(cfg.Status.Endpoints))
+	}
+
+	if _, err := resolvers.ResolveConnectorConfig(context.TODO(), kube, &rtv1.Reference{Name: "connector"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileUnauthorized(t *testing.T) {
+	srv := newConnectionDataServer()
+	defer srv.Close()
+
+	r, kube := newTestReconciler(t, srv.URL, "expired")
+
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "connector"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != NotReadyCheckInterval {
+		t.Fatalf("unexpected requeue: %s", res.RequeueAfter)
+	}
+
+	cfg := &connectorconfigs.ConnectorConfig{}
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: "connector"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cond := cfg.GetCondition(rtv1.TypeReady)
+	if cond.Status != metav1.ConditionFalse || cond.Reason != connectorconfigs.ReasonUnauthorized {
+		t.Fatalf("expected unauthorized connector, got: %+v", cond)
+	}
+
+	_, err = resolvers.ResolveConnectorConfig(context.TODO(), kube, &rtv1.Reference{Name: "connector"})
+	if !resolvers.IsConnectorNotReady(err) {
+		t.Fatalf("expected connector not ready error, got: %v", err)
+	}
+}
+
+func TestReconcileSecondaryEndpointUnreachable(t *testing.T) {
+	srv := newConnectionDataServer()
+	defer srv.Close()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	r, kube := newTestReconciler(t, srv.URL, "valid")
+
+	cfg := &connectorconfigs.ConnectorConfig{}
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: "connector"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Spec.ApiUrls.Feeds = down.URL
+	if err := kube.Update(context.TODO(), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "connector"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != DefaultCheckInterval {
+		t.Fatalf("unexpected requeue: %s", res.RequeueAfter)
+	}
+
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: "connector"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cond := cfg.GetCondition(rtv1.TypeReady)
+	if cond.Status != metav1.ConditionTrue || !strings.Contains(cond.Message, string(azuredevops.Feeds)) {
+		t.Fatalf("expected ready connector reporting the feeds endpoint, got: %+v", cond)
+	}
+	for _, el := range cfg.Status.Endpoints {
+		if want := el.Name != string(azuredevops.Feeds); el.Reachable != want {
+			t.Fatalf("unexpected endpoint status: %+v", el)
+		}
+	}
+
+	if _, err := resolvers.ResolveConnectorConfig(context.TODO(), kube, &rtv1.Reference{Name: "connector"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unreachable default endpoint makes the connector not ready.
+	cfg.Spec.ApiUrls.Feeds = srv.URL
+	cfg.Spec.ApiUrls.Defautl = down.URL
+	if err := kube.Update(context.TODO(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "connector"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: "connector"}, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cond = cfg.GetCondition(rtv1.TypeReady)
+	if cond.Status != metav1.ConditionFalse || cond.Reason != connectorconfigs.ReasonUnreachable {
+		t.Fatalf("expected unreachable connector, got: %+v", cond)
+	}
+}
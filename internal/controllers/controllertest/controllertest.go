@@ -0,0 +1,126 @@
+// Package controllertest provides the helpers to run the managed resources
+// controllers against the in-memory Azure DevOps server.
+package controllertest
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kubefake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/krateoplatformops/azuredevops-provider/apis"
+	connectorconfigs "github.com/krateoplatformops/azuredevops-provider/apis/connectorconfigs/v1alpha1"
+	pipelines "github.com/krateoplatformops/azuredevops-provider/apis/pipelines/v1alpha1"
+	projects "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	repositories "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+const (
+	// Namespace is the namespace of the objects created by the helpers.
+	Namespace = "default"
+	// ConnectorConfigName is the name of the ConnectorConfig stored by NewKube.
+	ConnectorConfigName = "fake-connector"
+)
+
+// ConnectorConfigRef returns the reference to the ConnectorConfig stored by NewKube.
+func ConnectorConfigRef() *rtv1.Reference {
+	return &rtv1.Reference{Name: ConnectorConfigName, Namespace: Namespace}
+}
+
+// NewKube returns a fake Kubernetes client storing the specified objects
+// and a ConnectorConfig (with its token secret) pointing to the server.
+func NewKube(t *testing.T, srv *fake.Server, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &connectorconfigs.ConnectorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectorConfigName, Namespace: Namespace},
+		Spec: connectorconfigs.ConnectorConfigSpec{
+			ApiUrl: srv.URL,
+			ApiUrls: &connectorconfigs.ApiUrl{
+				Defautl: srv.URL,
+				Feeds:   srv.URL,
+				Vssps:   srv.URL,
+			},
+			Credentials: &rtv1.CredentialSelectors{
+				SecretRef: &rtv1.SecretKeySelector{
+					Reference: rtv1.Reference{Name: ConnectorConfigName, Namespace: Namespace},
+					Key:       "token",
+				},
+			},
+		},
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectorConfigName, Namespace: Namespace},
+		Data:       map[string][]byte{"token": []byte(fake.Token)},
+	}
+
+	return kubefake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append([]client.Object{cfg, sec}, objs...)...).
+		WithStatusSubresource(append([]client.Object{cfg}, objs...)...).
+		Build()
+}
+
+// TeamProject returns a TeamProject bound to a project stored by the server.
+func TeamProject(name string, prj fake.Object) *projects.TeamProject {
+	return &projects.TeamProject{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: Namespace},
+		Spec: projects.TeamProjectSpec{
+			ConnectorConfigRef: ConnectorConfigRef(),
+			Organization:       fake.Organization,
+			Name:               fake.String(prj["name"]),
+		},
+		Status: projects.TeamProjectStatus{
+			Id: fake.String(prj["id"]),
+		},
+	}
+}
+
+// GitRepository returns a GitRepository bound to a repository stored by the server.
+func GitRepository(name string, prj *projects.TeamProject, repo fake.Object) *repositories.GitRepository {
+	return &repositories.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: Namespace},
+		Spec: repositories.GitRepositorySpec{
+			ConnectorConfigRef: ConnectorConfigRef(),
+			ProjectRef:         Ref(prj),
+			Name:               fake.String(repo["name"]),
+		},
+		Status: repositories.GitRepositoryStatus{
+			Id: fake.String(repo["id"]),
+		},
+	}
+}
+
+// Pipeline returns a Pipeline bound to a pipeline stored by the server.
+func Pipeline(name string, prj *projects.TeamProject, pip fake.Object) *pipelines.Pipeline {
+	return &pipelines.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: Namespace},
+		Spec: pipelines.PipelineSpec{
+			ConnectorConfigRef: ConnectorConfigRef(),
+			ProjectRef:         Ref(prj),
+			Name:               fake.String(pip["name"]),
+		},
+		Status: pipelines.PipelineStatus{
+			Id: helpers.StringPtr(fake.String(pip["id"])),
+		},
+	}
+}
+
+// Ref returns the reference to an object.
+func Ref(obj client.Object) *rtv1.Reference {
+	return &rtv1.Reference{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+}
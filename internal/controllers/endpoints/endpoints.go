@@ -3,6 +3,7 @@ package endpoints
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -11,7 +12,11 @@ import (
 	endpointsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/endpoints"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -35,13 +40,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(endpointsv1alpha1.EndpointGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(endpointsv1alpha1.EndpointKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -50,7 +57,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&endpointsv1alpha1.Endpoint{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(endpointsv1alpha1.EndpointKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -125,20 +132,36 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 
 	cr.SetConditions(rtv1.Available())
 
-	endpoint, err := asAzureDevopsServiceEndpoint(ctx, e.kube, &ref, cr, *observed)
+	cr.Status.Id = observed.Id
+	cr.Status.Url = observed.Url
+	setFederationStatus(cr, observed)
+
+	err = e.kube.Status().Update(ctx, cr)
 	if err != nil {
 		return reconciler.ExternalObservation{}, err
 	}
 
-	cr.Status.Id = observed.Id
-	cr.Status.Url = observed.Url
+	// The referenced secrets could have been deleted already.
+	if cr.GetDeletionTimestamp() != nil {
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
 
-	err = e.kube.Status().Update(ctx, cr)
+	if err := e.publishFederation(ctx, cr); err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+
+	endpoint, err := asAzureDevopsServiceEndpoint(ctx, e.kube, &ref, cr, *observed)
 	if err != nil {
 		return reconciler.ExternalObservation{}, err
 	}
 
-	if !endpoints.Equal(endpoint, observed) {
+	// Secret parameters are never returned: their changes are
+	// detected comparing the hashes of the values last sent.
+	if !endpoints.Equal(endpoint, observed) ||
+		!maps.Equal(parameterHashes(cr, endpoint), cr.Status.ParameterHashes) {
 		return reconciler.ExternalObservation{
 			ResourceExists:   true,
 			ResourceUpToDate: false,
@@ -185,8 +208,14 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	cr.Status.Id = helpers.StringPtr(*res.Id)
 	cr.Status.Url = helpers.StringPtr(*res.Url)
+	cr.Status.ParameterHashes = parameterHashes(cr, endpoint)
+	setFederationStatus(cr, res)
 
-	return e.kube.Status().Update(ctx, cr)
+	if err := e.kube.Status().Update(ctx, cr); err != nil {
+		return err
+	}
+
+	return e.publishFederation(ctx, cr)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
@@ -224,12 +253,14 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 	}
 
 	endpoint, err := asAzureDevopsServiceEndpoint(ctx, e.kube, &ref, cr, *observed)
-	refBackup := endpoint.ServiceEndpointProjectReferences
-	endpoint.ServiceEndpointProjectReferences = observed.ServiceEndpointProjectReferences
 	if err != nil {
 		return err
 	}
-	if !endpoints.EqualResourceData(endpoint, observed) {
+	refBackup := endpoint.ServiceEndpointProjectReferences
+	endpoint.ServiceEndpointProjectReferences = observed.ServiceEndpointProjectReferences
+
+	hashes := parameterHashes(cr, endpoint)
+	if !endpoints.EqualResourceData(endpoint, observed) || !maps.Equal(hashes, cr.Status.ParameterHashes) {
 		_, err := endpoints.Update(ctx, e.azCli, endpoints.UpdateOptions{
 			EndpointId:   helpers.String(cr.Status.Id),
 			Organization: ref.Organization,
@@ -254,6 +285,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 
 	cr.Status.Id = observed.Id
 	cr.Status.Url = observed.Url
+	cr.Status.ParameterHashes = hashes
 
 	return e.kube.Status().Update(ctx, cr)
 }
@@ -282,9 +314,14 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(rtv1.Deleting())
 
-	return endpoints.Delete(ctx, e.azCli, endpoints.DeleteOptions{
+	err = endpoints.Delete(ctx, e.azCli, endpoints.DeleteOptions{
 		Organization: ref.Organization,
 		ProjectIds:   []string{ref.Id},
 		EndpointId:   helpers.String(cr.Status.Id),
 	})
+	if err != nil {
+		return err
+	}
+
+	return e.unpublishFederation(ctx, cr)
 }
@@ -0,0 +1,318 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	endpointsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestEndpointLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	other := controllertest.TeamProject("other", srv.AddProject(fake.Organization, "Other"))
+	cr := &endpointsv1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: controllertest.Namespace},
+		Spec: endpointsv1alpha1.EndpointSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("registry"),
+			Description:        helpers.StringPtr("container registry"),
+			Type:               helpers.StringPtr("generic"),
+			Url:                helpers.StringPtr("https://registry.example.com"),
+			Owner:              helpers.StringPtr("library"),
+			Authorization: &endpointsv1alpha1.EndpointAuthorization{
+				Scheme: helpers.StringPtr("Token"),
+				Parameters: &endpointsv1alpha1.EndpointAuthorizationParams{
+					Apitoken: helpers.StringPtr("s3cr3t"),
+				},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, other, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected endpoint not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil || cr.Status.Url == nil {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Sharing the endpoint with another project.
+	cr.Spec.ServiceEndpointProjectReferences = []endpointsv1alpha1.ServiceEndpointProjectReference{
+		{Name: helpers.StringPtr("registry"), ProjectRef: controllertest.Ref(other)},
+	}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected endpoint to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	refs, _ := srv.ServiceEndpoint(fake.Organization, *cr.Status.Id)["serviceEndpointProjectReferences"].([]any)
+	if len(refs) != 2 {
+		t.Fatalf("expected endpoint shared with 2 projects, got: %d", len(refs))
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected endpoint to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected endpoint to be removed from the project")
+	}
+}
+
+func TestEndpointSecretRotation(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-token", Namespace: controllertest.Namespace},
+		Data:       map[string][]byte{"token": []byte("v1")},
+	}
+	cr := &endpointsv1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: controllertest.Namespace, UID: "0a1b2c3d"},
+		Spec: endpointsv1alpha1.EndpointSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("registry"),
+			Description:        helpers.StringPtr("container registry"),
+			Type:               helpers.StringPtr("generic"),
+			Url:                helpers.StringPtr("https://registry.example.com"),
+			Owner:              helpers.StringPtr("library"),
+			Authorization: &endpointsv1alpha1.EndpointAuthorization{
+				Scheme: helpers.StringPtr("Token"),
+				Parameters: &endpointsv1alpha1.EndpointAuthorizationParams{
+					ApitokenRef: &rtv1.SecretKeySelector{
+						Reference: rtv1.Reference{Name: sec.Name, Namespace: sec.Namespace},
+						Key:       "token",
+					},
+				},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, sec, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apitoken := func() string {
+		auth, _ := srv.ServiceEndpoint(fake.Organization, *cr.Status.Id)["authorization"].(map[string]any)
+		params, _ := auth["parameters"].(map[string]any)
+		return fake.String(params["apitoken"])
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := apitoken(); got != "v1" {
+		t.Fatalf("unexpected api token: %s", got)
+	}
+	hash := cr.Status.ParameterHashes["apitoken"]
+	if len(hash) == 0 {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// The secret is rotated.
+	sec.Data["token"] = []byte("v2")
+	if err := kube.Update(ctx, sec); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected endpoint to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := apitoken(); got != "v2" {
+		t.Fatalf("unexpected api token: %s", got)
+	}
+	if cr.Status.ParameterHashes["apitoken"] == hash {
+		t.Fatal("expected parameter hash to change")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+}
+
+func TestEndpointWorkloadIdentityFederation(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &endpointsv1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: controllertest.Namespace, UID: "azure-uid"},
+		Spec: endpointsv1alpha1.EndpointSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("azure"),
+			Type:               helpers.StringPtr("azurerm"),
+			Url:                helpers.StringPtr("https://management.azure.com/"),
+			Owner:              helpers.StringPtr("library"),
+			Authorization: &endpointsv1alpha1.EndpointAuthorization{
+				Scheme: helpers.StringPtr("WorkloadIdentityFederation"),
+				Parameters: &endpointsv1alpha1.EndpointAuthorizationParams{
+					Tenantid:           helpers.StringPtr("1272a66f-e2e8-4e88-ab43-487409186c3f"),
+					ServiceprincipalId: helpers.StringPtr("3c1a0d2e-8d1b-4b7e-9a53-1f0c2a9d4e11"),
+				},
+			},
+			WorkloadIdentityFederation: &endpointsv1alpha1.WorkloadIdentityFederation{
+				ConfigMapRef: &rtv1.Reference{Name: "azure-federation", Namespace: controllertest.Namespace},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	issuer := "https://vstoken.dev.azure.com/" + fake.Organization
+	subject := "sc://" + fake.Organization + "/Demo/azure"
+	if helpers.String(cr.Status.WorkloadIdentityFederationIssuer) != issuer ||
+		helpers.String(cr.Status.WorkloadIdentityFederationSubject) != subject {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: "azure-federation", Namespace: controllertest.Namespace}, cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Data[ConfigMapKeyIssuer] != issuer || cm.Data[ConfigMapKeySubjectIdentifier] != subject {
+		t.Fatalf("unexpected configmap data: %v", cm.Data)
+	}
+
+	// The configmap is restored.
+	if err := kube.Delete(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if err := kube.Get(ctx, client.ObjectKey{Name: "azure-federation", Namespace: controllertest.Namespace}, cm); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(cm, cr) {
+		t.Fatalf("expected configmap to be controlled by the endpoint, got: %v", cm.OwnerReferences)
+	}
+
+	// The configmap created for the endpoint is deleted with it.
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	err = kube.Get(ctx, client.ObjectKey{Name: "azure-federation", Namespace: controllertest.Namespace}, cm)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected configmap to be deleted, got: %v", err)
+	}
+
+	// A configmap not created for the endpoint is kept.
+	shared := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: controllertest.Namespace},
+		Data:       map[string]string{"other": "value"},
+	}
+	if err := kube.Create(ctx, shared); err != nil {
+		t.Fatal(err)
+	}
+	cr.Spec.WorkloadIdentityFederation.ConfigMapRef = &rtv1.Reference{Name: "shared", Namespace: controllertest.Namespace}
+	e := ext.(*external)
+	if err := e.publishFederation(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.unpublishFederation(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Get(ctx, client.ObjectKey{Name: "shared", Namespace: controllertest.Namespace}, shared); err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.OwnerReferences) > 0 || len(shared.Data) != 1 || shared.Data["other"] != "value" {
+		t.Fatalf("unexpected shared configmap: %+v", shared)
+	}
+}
@@ -8,11 +8,15 @@ import (
 	endpointsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/endpoints"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/projects"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/hashes"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type ProjectReference struct {
@@ -121,11 +125,16 @@ func asAzureDevopsServiceEndpoint(ctx context.Context, kube client.Client, ref *
 			addEventually(res.Authorization.Parameters, "tenantid", aut.Parameters.Tenantid)
 			addEventually(res.Authorization.Parameters, "serviceprincipalId", aut.Parameters.ServiceprincipalId)
 			addEventually(res.Authorization.Parameters, "authenticationType", aut.Parameters.AuthenticationType)
-			addEventually(res.Authorization.Parameters, "serviceprincipalKey", aut.Parameters.ServiceprincipalKey)
 			addEventually(res.Authorization.Parameters, "scope", aut.Parameters.Scope)
-			addEventually(res.Authorization.Parameters, "serviceAccountCertificate", aut.Parameters.ServiceAccountCertificate)
 			addEventually(res.Authorization.Parameters, "isCreatedFromSecretYaml", aut.Parameters.IsCreatedFromSecretYaml)
-			addEventually(res.Authorization.Parameters, "apitoken", aut.Parameters.Apitoken)
+
+			secrets, err := resolvers.ResolveEndpointSecrets(ctx, kube, aut.Parameters)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range secrets {
+				res.Authorization.Parameters[k] = v
+			}
 		}
 	} else {
 		res.Authorization = originEndpoint.Authorization
@@ -169,6 +178,113 @@ func asAzureDevopsServiceEndpoint(ctx context.Context, kube client.Client, ref *
 	return &res, nil
 }
 
+// Keys of the ConfigMap of the workload identity federation.
+const (
+	ConfigMapKeyIssuer            = "issuer"
+	ConfigMapKeySubjectIdentifier = "subjectIdentifier"
+)
+
+// setFederationStatus sets the issuer and the subject identifier of the
+// federated credential generated by Azure DevOps for the endpoint.
+func setFederationStatus(cr *endpointsv1alpha1.Endpoint, observed *endpoints.ServiceEndpoint) {
+	cr.Status.WorkloadIdentityFederationIssuer = nil
+	cr.Status.WorkloadIdentityFederationSubject = nil
+
+	aut := observed.Authorization
+	if aut == nil || !strings.EqualFold(helpers.String(aut.Scheme), endpoints.SchemeWorkloadIdentityFederation) {
+		return
+	}
+	if val := aut.Parameters[endpoints.ParamWorkloadIdentityFederationIssuer]; len(val) > 0 {
+		cr.Status.WorkloadIdentityFederationIssuer = helpers.StringPtr(val)
+	}
+	if val := aut.Parameters[endpoints.ParamWorkloadIdentityFederationSubject]; len(val) > 0 {
+		cr.Status.WorkloadIdentityFederationSubject = helpers.StringPtr(val)
+	}
+}
+
+// publishFederation writes the issuer and the subject identifier of the
+// federated credential to the referenced ConfigMap, if any.
+func (e *external) publishFederation(ctx context.Context, cr *endpointsv1alpha1.Endpoint) error {
+	wif := cr.Spec.WorkloadIdentityFederation
+	if wif == nil || wif.ConfigMapRef == nil || cr.Status.WorkloadIdentityFederationIssuer == nil {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wif.ConfigMapRef.Name,
+			Namespace: wif.ConfigMapRef.Namespace,
+		},
+	}
+	res, err := controllerutil.CreateOrUpdate(ctx, e.kube, cm, func() error {
+		// The ConfigMap created for the Endpoint is deleted with it.
+		if len(cm.ResourceVersion) == 0 {
+			if err := controllerutil.SetControllerReference(cr, cm, e.kube.Scheme()); err != nil {
+				return err
+			}
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ConfigMapKeyIssuer] = helpers.String(cr.Status.WorkloadIdentityFederationIssuer)
+		cm.Data[ConfigMapKeySubjectIdentifier] = helpers.String(cr.Status.WorkloadIdentityFederationSubject)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "cannot write %s configmap", cm.Name)
+	}
+
+	if res != controllerutil.OperationResultNone {
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "FederatedCredentialPublished",
+			"Workload identity federation issuer and subject written to configmap '%s'", cm.Name)
+	}
+	return nil
+}
+
+// unpublishFederation deletes the ConfigMap created by publishFederation;
+// the issuer and the subject identifier are removed from the ConfigMaps
+// not created for the Endpoint.
+func (e *external) unpublishFederation(ctx context.Context, cr *endpointsv1alpha1.Endpoint) error {
+	wif := cr.Spec.WorkloadIdentityFederation
+	if wif == nil || wif.ConfigMapRef == nil {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := e.kube.Get(ctx, client.ObjectKey{Name: wif.ConfigMapRef.Name, Namespace: wif.ConfigMapRef.Namespace}, cm)
+	if err != nil {
+		return errors.Wrapf(client.IgnoreNotFound(err), "cannot get %s configmap", wif.ConfigMapRef.Name)
+	}
+
+	if metav1.IsControlledBy(cm, cr) {
+		return errors.Wrapf(client.IgnoreNotFound(e.kube.Delete(ctx, cm)), "cannot delete %s configmap", cm.Name)
+	}
+	if _, ok := cm.Data[ConfigMapKeyIssuer]; !ok {
+		return nil
+	}
+	delete(cm.Data, ConfigMapKeyIssuer)
+	delete(cm.Data, ConfigMapKeySubjectIdentifier)
+	return errors.Wrapf(e.kube.Update(ctx, cm), "cannot write %s configmap", cm.Name)
+}
+
+// secretParameters are the authorization parameters masked by Azure DevOps.
+var secretParameters = []string{"serviceprincipalKey", "serviceAccountCertificate", "apitoken"}
+
+// parameterHashes returns the hashes of the secret authorization parameters
+// specified for the endpoint.
+func parameterHashes(cr *endpointsv1alpha1.Endpoint, endpoint *endpoints.ServiceEndpoint) map[string]string {
+	res := map[string]string{}
+	if aut := cr.Spec.Authorization; aut == nil || aut.Parameters == nil {
+		return res
+	}
+	for _, key := range secretParameters {
+		if val, ok := endpoint.Authorization.Parameters[key]; ok {
+			res[key] = hashes.Secret(cr.GetUID(), key, val)
+		}
+	}
+	return res
+}
+
 func addEventually(dict map[string]string, key string, val *string) {
 	if val == nil {
 		return
@@ -13,7 +13,11 @@ import (
 	environmentsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/environments/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/environments"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -38,13 +42,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(environmentsv1alpha1.EnvironmentGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(environmentsv1alpha1.EnvironmentKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -53,7 +59,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&environmentsv1alpha1.Environment{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(environmentsv1alpha1.EnvironmentKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -0,0 +1,96 @@
+package environments
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	environmentsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/environments/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestEnvironmentLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &environmentsv1alpha1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: controllertest.Namespace},
+		Spec: environmentsv1alpha1.EnvironmentSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("staging"),
+			Description:        helpers.StringPtr("staging environment"),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected environment not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil {
+		t.Fatal("expected environment identifier")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	cr.Spec.Description = helpers.StringPtr("pre-production environment")
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected environment to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected environment to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected environment to be deleted")
+	}
+}
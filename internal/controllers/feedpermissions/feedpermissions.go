@@ -14,7 +14,11 @@ import (
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/feeds"
 	feedspermissions "github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/feedspermissions"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/identities"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -39,13 +43,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(feedpermissionsv1alpha1.FeedPermissionGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(feedpermissionsv1alpha1.FeedPermissionKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -54,7 +60,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&feedpermissionsv1alpha1.FeedPermission{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(feedpermissionsv1alpha1.FeedPermissionKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -130,7 +136,8 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 	upToDate := false
 
 	for _, feedPerm := range res.Value {
-		if *feedPerm.IdentityDescriptor == status.IdentityDescriptor && feedPerm.Role == cr.Spec.User.Role {
+		if helpers.String(feedPerm.IdentityDescriptor) == status.IdentityDescriptor &&
+			helpers.String(feedPerm.Role) == helpers.String(cr.Spec.User.Role) {
 			upToDate = true
 		}
 	}
@@ -0,0 +1,79 @@
+package feedpermissions
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	feedpermissionsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/feedpermissions/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/feeds"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/identities"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestFeedPermissionLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+
+	ctx := context.TODO()
+	feed, err := feeds.Create(ctx, srv.Client(), feeds.CreateOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		Feed:         &feeds.Feed{Name: "packages"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr := &feedpermissionsv1alpha1.FeedPermission{
+		ObjectMeta: metav1.ObjectMeta{Name: "packages-build", Namespace: controllertest.Namespace},
+		Spec: feedpermissionsv1alpha1.FeedPermissionSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Feed:               feed.Id,
+			User: &feedpermissionsv1alpha1.UserResource{
+				Type:       helpers.StringPtr(string(identities.BuildService)),
+				Role:       helpers.StringPtr("contributor"),
+				ProjectRef: controllertest.Ref(prj),
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Status.IdentityDescriptor) == 0 {
+		t.Fatal("expected identity descriptor")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected feed permission to be up to date")
+	}
+}
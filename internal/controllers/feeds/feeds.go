@@ -12,7 +12,11 @@ import (
 	feedsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/feeds/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/feeds"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -36,13 +40,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(feedsv1alpha1.FeedGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(feedsv1alpha1.FeedKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -51,7 +57,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&feedsv1alpha1.Feed{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(feedsv1alpha1.FeedKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -0,0 +1,101 @@
+package feeds
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	feedsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/feeds/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestFeedLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &feedsv1alpha1.Feed{
+		ObjectMeta: metav1.ObjectMeta{Name: "packages", Namespace: controllertest.Namespace},
+		Spec: feedsv1alpha1.FeedSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("packages"),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected feed not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil {
+		t.Fatal("expected feed identifier")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Adding an upstream source makes the feed outdated until it is updated.
+	cr.Spec.UpstreamSources = []feedsv1alpha1.UpstreamSource{{
+		Name:               helpers.StringPtr("npmjs"),
+		Location:           helpers.StringPtr("https://registry.npmjs.org/"),
+		Protocol:           helpers.StringPtr("npm"),
+		UpstreamSourceType: helpers.StringPtr("public"),
+	}}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected feed to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected feed to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected feed to be deleted")
+	}
+}
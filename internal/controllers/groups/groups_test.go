@@ -0,0 +1,111 @@
+package groups
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	groupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/groups/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestGroupsLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	parent := &groupsv1alpha1.Groups{
+		ObjectMeta: metav1.ObjectMeta{Name: "readers", Namespace: controllertest.Namespace},
+		Status: groupsv1alpha1.GroupsStatus{
+			Descriptor: helpers.StringPtr(fake.String(srv.AddGroup(fake.Organization, "Readers")["descriptor"])),
+		},
+	}
+	cr := &groupsv1alpha1.Groups{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewers", Namespace: controllertest.Namespace},
+		Spec: groupsv1alpha1.GroupsSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Membership: groupsv1alpha1.Membership{
+				ProjectRef: controllertest.Ref(prj),
+			},
+			GroupIdentifier: groupsv1alpha1.GroupIdentifier{
+				GroupsName: helpers.StringPtr("Reviewers"),
+			},
+			Description: "code reviewers",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, parent, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected group not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Descriptor == nil {
+		t.Fatal("expected group descriptor")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Adding a parent group makes the group outdated until the membership is created.
+	cr.Spec.GroupsRefs = []rtv1.Reference{*controllertest.Ref(parent)}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected group to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.IsMember(fake.Organization, *cr.Status.Descriptor, *parent.Status.Descriptor) {
+		t.Fatal("expected group to be a member of the parent group")
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected group to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected group to be deleted")
+	}
+}
@@ -23,9 +23,15 @@ import (
 	"github.com/pkg/errors"
 
 	pipelinesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/pipelines/v1alpha1"
+	variablegroupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	pipelines "github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/pipelines"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	valuehashes "github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/hashes"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 )
 
 const (
@@ -39,13 +45,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(pipelinesv1alpha1.PipelineGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(pipelinesv1alpha1.PipelineKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -54,7 +62,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&pipelinesv1alpha1.Pipeline{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(pipelinesv1alpha1.PipelineKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -156,11 +164,35 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 	cr.Status.Id = helpers.StringPtr(pipId)
 	cr.Status.Url = helpers.StringPtr(*pip.Url)
 
+	// The referenced repository, queue and secrets may be deleted before
+	// the Pipeline: the drift is computed for the live Pipelines only.
+	if meta.WasDeleted(cr) {
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+
+	def, err := pipelines.GetDefinition(ctx, e.azCli, pipelines.GetDefinitionOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		DefinitionId: pipId,
+	})
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	cr.Status.Revision = def.Revision
+
+	res, err := e.resolve(ctx, cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+
 	cr.SetConditions(rtv1.Available())
 
 	return reconciler.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceUpToDate: isUpToDate(cr, res, def),
 	}, nil
 }
 
@@ -226,7 +258,121 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
-	return nil // noop
+	cr, ok := mg.(*pipelinesv1alpha1.Pipeline)
+	if !ok {
+		return errors.New(errNotPipeline)
+	}
+
+	if !meta.IsActionAllowed(cr, meta.ActionUpdate) {
+		e.log.Debug("External resource should not be updated by provider, skip updating.")
+		return nil
+	}
+
+	e.log.Info("Updating resource")
+
+	spec := cr.Spec.DeepCopy()
+
+	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, spec.ProjectRef)
+	if err != nil {
+		return errors.Wrapf(err, "unble to resolve TeamProject: %s", spec.ProjectRef.Name)
+	}
+
+	res, err := e.resolve(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	// The whole definition is sent with the revision read: if someone
+	// else changed it meanwhile it is read and sent again once.
+	upd, err := e.updateDefinition(ctx, cr, prj.Spec.Organization, prj.Status.Id, spec, res)
+	if azuredevops.IsConflict(err) {
+		e.log.Debug("Pipeline changed since it was read, retrying", "id", meta.GetExternalName(cr))
+		upd, err = e.updateDefinition(ctx, cr, prj.Spec.Organization, prj.Status.Id, spec, res)
+	}
+	if err != nil {
+		return err
+	}
+	cr.Status.Revision = upd.Revision
+	cr.Status.ValueHashes = res.hashes
+
+	e.log.Debug("Pipeline updated", "id", meta.GetExternalName(cr), "revision", helpers.Int(upd.Revision))
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "PipelineUpdated",
+		"Pipeline '%s' updated", helpers.String(cr.Status.Url))
+
+	return nil
+}
+
+// updateDefinition reads the latest revision of the definition and
+// updates it with the spec.
+func (e *external) updateDefinition(ctx context.Context, cr *pipelinesv1alpha1.Pipeline, organization, projectId string, spec *pipelinesv1alpha1.PipelineSpec, res *resolved) (*pipelines.BuildDefinition, error) {
+	def, err := pipelines.GetDefinition(ctx, e.azCli, pipelines.GetDefinitionOptions{
+		Organization: organization,
+		Project:      projectId,
+		DefinitionId: meta.GetExternalName(cr),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	applySpec(spec, res, def)
+
+	return pipelines.UpdateDefinition(ctx, e.azCli, pipelines.UpdateDefinitionOptions{
+		Organization: organization,
+		Project:      projectId,
+		Definition:   def,
+	})
+}
+
+// resolve resolves the repository, the queue and the variables of the spec.
+func (e *external) resolve(ctx context.Context, cr *pipelinesv1alpha1.Pipeline) (*resolved, error) {
+	res := &resolved{}
+	if ref := cr.Spec.RepositoryRef; ref != nil {
+		repo, err := resolvers.ResolveGitRepository(ctx, e.kube, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve GitRepository: %s", ref.Name)
+		}
+		res.repo = &repo
+	}
+
+	bd := cr.Spec.BuildDefinition
+	if bd == nil {
+		return res, nil
+	}
+	if ref := bd.QueueRef; ref != nil {
+		q, err := resolvers.ResolveQueue(ctx, e.kube, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve Queue: %s", ref.Name)
+		}
+		if q.Status.Id == nil {
+			return nil, fmt.Errorf("queue '%s' has not been created yet", ref.Name)
+		}
+		res.queue = &pipelines.AgentQueueReference{
+			Id:   helpers.IntPtr(*q.Status.Id),
+			Name: q.Spec.Name,
+		}
+	}
+	if bd.Variables != nil {
+		res.variables = map[string]pipelines.DefinitionVariable{}
+		res.hashes = map[string]string{}
+		for k, v := range bd.Variables {
+			val, err := resolvers.ResolveVariableValue(ctx, e.kube, variablegroupsv1alpha1.VariableValue{
+				Value:     v.Value,
+				ValueFrom: v.ValueFrom,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve variable '%s': %w", k, err)
+			}
+			res.variables[k] = pipelines.DefinitionVariable{
+				Value:         helpers.StringPtr(val),
+				IsSecret:      v.IsSecret,
+				AllowOverride: v.AllowOverride,
+			}
+			if v.IsSecret || v.ValueFrom != nil {
+				res.hashes[k] = valuehashes.Secret(cr.GetUID(), k, val)
+			}
+		}
+	}
+	return res, nil
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -0,0 +1,336 @@
+package pipeline
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pipelinesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/pipelines/v1alpha1"
+	queuesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/queues/v1alpha1"
+	variablegroupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+)
+
+func TestPipelineLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+	cr := &pipelinesv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: controllertest.Namespace},
+		Spec: pipelinesv1alpha1.PipelineSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			RepositoryRef:      controllertest.Ref(repo),
+			Name:               "ci",
+			Folder:             "\\builds",
+			ConfigurationType:  helpers.StringPtr("yaml"),
+			DefinitionPath:     helpers.StringPtr("azure-pipelines.yml"),
+			RepositoryType:     helpers.StringPtr("azureReposGit"),
+		},
+	}
+
+	other := controllertest.GitRepository("other", prj, srv.AddRepository(fake.Organization, prj.Status.Id, "Other"))
+	kube := controllertest.NewKube(t, srv, prj, repo, other, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected pipeline not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if meta.GetExternalName(cr) == "" {
+		t.Fatal("expected external name")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if helpers.String(cr.Status.Id) != meta.GetExternalName(cr) || cr.Status.Url == nil {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	// Rename, move and point the pipeline to another YAML file and repository.
+	cr.Spec.Name = "ci-renamed"
+	cr.Spec.Folder = "/builds/nightly"
+	cr.Spec.DefinitionPath = helpers.StringPtr("/ci/nightly.yml")
+	cr.Spec.RepositoryRef = controllertest.Ref(other)
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || obs.ResourceUpToDate {
+		t.Fatalf("expected drift, got: %+v", obs)
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate || helpers.Int(cr.Status.Revision) != 2 {
+		t.Fatalf("unexpected observation: %+v (revision %d)", obs, helpers.Int(cr.Status.Revision))
+	}
+	got := srv.Pipeline(fake.Organization, prj.Status.Id, meta.GetExternalName(cr))
+	conf, _ := got["configuration"].(map[string]any)
+	repo2, _ := conf["repository"].(map[string]any)
+	if fake.String(got["name"]) != "ci-renamed" || fake.String(got["folder"]) != "\\builds\\nightly" ||
+		fake.String(conf["path"]) != "/ci/nightly.yml" || fake.String(repo2["id"]) != other.Status.Id {
+		t.Fatalf("unexpected pipeline: %v", got)
+	}
+
+	// The pipeline changed after it was read: it is read and sent again once.
+	conflict := func(c *fake.Call) {
+		c.Error(http.StatusConflict, "DefinitionUpdateConflictException", "the definition was changed by another client")
+	}
+	puts := func() int {
+		return srv.CountRequests(http.MethodPut, "/_apis/build/definitions/")
+	}
+	cr.Spec.Name = "ci"
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	srv.HandleOnce(http.MethodPut, "{org}/{project}/_apis/build/definitions/{pipeline}", conflict)
+	before := puts()
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if fake.String(got["name"]) != "ci" || puts()-before != 2 {
+		t.Fatalf("expected the update to be retried, got: %v", got)
+	}
+
+	// A conflict on the retry too is reported with its own reason.
+	cr.Spec.Name = "ci-conflict"
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	srv.HandleOnce(http.MethodPut, "{org}/{project}/_apis/build/definitions/{pipeline}", conflict)
+	srv.HandleOnce(http.MethodPut, "{org}/{project}/_apis/build/definitions/{pipeline}", conflict)
+	before = puts()
+	err = ext.Update(ctx, cr)
+	if reason := conditions.ReasonFor(err); reason != conditions.ReasonConflict || puts()-before != 2 {
+		t.Fatalf("expected %s after 2 updates, got: %s (%v)", conditions.ReasonConflict, reason, err)
+	}
+	if _, err := ext.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected pipeline to be deleted")
+	}
+}
+
+func TestPipelineBuildDefinition(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+	q := srv.AddQueue(fake.Organization, prj.Status.Id, "linux", srv.AddPool(fake.Organization, "linux"))
+	queue := &queuesv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "linux", Namespace: controllertest.Namespace},
+		Spec: queuesv1alpha1.QueueSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("linux"),
+			Pool:               "linux",
+		},
+		Status: queuesv1alpha1.QueueStatus{Id: helpers.IntPtr(q["id"].(int))},
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-secrets", Namespace: controllertest.Namespace},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	cr := &pipelinesv1alpha1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: controllertest.Namespace, UID: "uid-1"},
+		Spec: pipelinesv1alpha1.PipelineSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			RepositoryRef:      controllertest.Ref(repo),
+			Name:               "ci",
+			ConfigurationType:  helpers.StringPtr("yaml"),
+			DefinitionPath:     helpers.StringPtr("azure-pipelines.yml"),
+			RepositoryType:     helpers.StringPtr("azureReposGit"),
+			BuildDefinition: &pipelinesv1alpha1.BuildDefinitionSpec{
+				CITrigger: &pipelinesv1alpha1.CITrigger{
+					BranchFilters: []string{"+refs/heads/main", "-refs/heads/wip/*"},
+					PathFilters:   []string{"+/src"},
+				},
+				Variables: map[string]pipelinesv1alpha1.PipelineVariable{
+					"env": {Value: "dev", AllowOverride: true},
+					"token": {IsSecret: true, ValueFrom: &variablegroupsv1alpha1.VariableValueSource{
+						SecretKeyRef: &rtv1.SecretKeySelector{Reference: *controllertest.Ref(sec), Key: "token"},
+					}},
+				},
+				QueueRef:     controllertest.Ref(queue),
+				BadgeEnabled: helpers.BoolPtr(true),
+				Retention:    &pipelinesv1alpha1.RetentionPolicy{DaysToKeep: 30, MinimumToKeep: 3},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, repo, queue, sec, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	// The settings are applied to the created pipeline.
+	sync := func() {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceExists || obs.ResourceUpToDate {
+			t.Fatalf("expected drift, got: %+v", obs)
+		}
+		if err := ext.Update(ctx, cr); err != nil {
+			t.Fatal(err)
+		}
+		// The reconciler persists the status with the value hashes.
+		if err := kube.Status().Update(ctx, cr); err != nil {
+			t.Fatal(err)
+		}
+		obs, err = ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceUpToDate {
+			t.Fatalf("unexpected observation: %+v", obs)
+		}
+	}
+	settings := func() fake.Object {
+		pip := srv.Pipeline(fake.Organization, prj.Status.Id, meta.GetExternalName(cr))
+		return pip["_definition"].(fake.Object)
+	}
+	variable := func(name string) map[string]any {
+		vars, _ := settings()["variables"].(map[string]any)
+		res, _ := vars[name].(map[string]any)
+		return res
+	}
+
+	sync()
+	got := settings()
+	triggers, _ := got["triggers"].([]any)
+	if len(triggers) != 1 {
+		t.Fatalf("unexpected triggers: %v", got["triggers"])
+	}
+	ci, _ := triggers[0].(map[string]any)
+	if fake.String(ci["settingsSourceType"]) != "1" || len(ci["branchFilters"].([]any)) != 2 ||
+		fake.String(ci["maxConcurrentBuildsPerBranch"]) != "1" {
+		t.Fatalf("unexpected ci trigger: %v", ci)
+	}
+	if fake.String(got["badgeEnabled"]) != "true" || fake.String(got["jobAuthorizationScope"]) != "projectCollection" {
+		t.Fatalf("unexpected definition: %v", got)
+	}
+	if queue, _ := got["queue"].(map[string]any); fake.String(queue["id"]) != fake.String(q["id"]) {
+		t.Fatalf("unexpected queue: %v", got["queue"])
+	}
+	if rules, _ := got["retentionRules"].([]any); len(rules) != 1 ||
+		fake.String(rules[0].(map[string]any)["daysToKeep"]) != "30" {
+		t.Fatalf("unexpected retention rules: %v", got["retentionRules"])
+	}
+	if fake.String(variable("env")["value"]) != "dev" || fake.String(variable("token")["value"]) != "s3cr3t" {
+		t.Fatalf("unexpected variables: %v", got["variables"])
+	}
+	hash := cr.Status.ValueHashes["token"]
+	if len(hash) == 0 || len(cr.Status.ValueHashes) != 1 {
+		t.Fatalf("unexpected value hashes: %v", cr.Status.ValueHashes)
+	}
+
+	// The rotated secret is detected through its hash.
+	sec.Data["token"] = []byte("r0t4t3d")
+	if err := kube.Update(ctx, sec); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if fake.String(variable("token")["value"]) != "r0t4t3d" || cr.Status.ValueHashes["token"] == hash {
+		t.Fatalf("expected rotated secret, got: %v", settings()["variables"])
+	}
+
+	// Disable the continuous integration.
+	cr.Spec.BuildDefinition.CITrigger.Disabled = true
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if triggers, _ := settings()["triggers"].([]any); len(triggers) != 0 {
+		t.Fatalf("expected no triggers, got: %v", triggers)
+	}
+
+	// The references deleted first do not block the deletion.
+	for _, obj := range []client.Object{queue, sec, repo} {
+		if err := kube.Delete(ctx, obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cr.Finalizers = []string{"finalizer.managedresource.krateo.io"}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := kube.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists {
+		t.Fatal("expected the pipeline to exist")
+	}
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Pipeline(fake.Organization, prj.Status.Id, meta.GetExternalName(cr)) != nil {
+		t.Fatal("expected pipeline to be deleted")
+	}
+}
@@ -0,0 +1,308 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	pipelinesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/pipelines/v1alpha1"
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	pipelines "github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/pipelines"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+// resolved holds the references and the variable values of the spec.
+type resolved struct {
+	// repo is the referenced GitRepository, if any.
+	repo *repositoriesv1alpha1.GitRepository
+	// queue is the default agent queue, if any.
+	queue *pipelines.AgentQueueReference
+	// variables are the variables of the build definition, if specified.
+	variables map[string]pipelines.DefinitionVariable
+	// hashes are the hashes of the secret and of the referenced values.
+	hashes map[string]string
+}
+
+// isUpToDate returns true if the build definition has the name, the
+// folder, the YAML file, the repository and the build definition
+// settings of the spec.
+func isUpToDate(cr *pipelinesv1alpha1.Pipeline, res *resolved, def *pipelines.BuildDefinition) bool {
+	spec := &cr.Spec
+	if def.Name != spec.Name {
+		return false
+	}
+	if !strings.EqualFold(folder(def.Path), folder(spec.Folder)) {
+		return false
+	}
+	if spec.DefinitionPath != nil && isYaml(def) &&
+		yamlFilename(helpers.String(def.Process.YamlFilename)) != yamlFilename(helpers.String(spec.DefinitionPath)) {
+		return false
+	}
+	if res.repo != nil && (def.Repository == nil ||
+		!strings.EqualFold(helpers.String(def.Repository.Id), res.repo.Status.Id)) {
+		return false
+	}
+
+	bd := spec.BuildDefinition
+	if bd == nil {
+		return true
+	}
+	if bd.CITrigger != nil &&
+		!isTriggerUpToDate(def.Triggers, bd.CITrigger.Disabled, ciTrigger(bd.CITrigger)) {
+		return false
+	}
+	if bd.PRTrigger != nil &&
+		!isTriggerUpToDate(def.Triggers, bd.PRTrigger.Disabled, prTrigger(bd.PRTrigger)) {
+		return false
+	}
+	if res.queue != nil && (def.Queue == nil || helpers.Int(def.Queue.Id) != helpers.Int(res.queue.Id)) {
+		return false
+	}
+	if bd.BadgeEnabled != nil && helpers.Bool(def.BadgeEnabled) != *bd.BadgeEnabled {
+		return false
+	}
+	if bd.Retention != nil && !isRetentionUpToDate(def.RetentionRules, retentionRule(bd.Retention)) {
+		return false
+	}
+	if res.variables != nil && !areVariablesUpToDate(cr, res, def) {
+		return false
+	}
+	return true
+}
+
+// applySpec sets the name, the folder, the YAML file, the repository
+// and the build definition settings of the spec to the build definition.
+func applySpec(spec *pipelinesv1alpha1.PipelineSpec, res *resolved, def *pipelines.BuildDefinition) {
+	def.Name = spec.Name
+	def.Path = folder(spec.Folder)
+	if spec.DefinitionPath != nil && isYaml(def) {
+		def.Process.YamlFilename = spec.DefinitionPath
+	}
+	if repo := res.repo; repo != nil && (def.Repository == nil ||
+		!strings.EqualFold(helpers.String(def.Repository.Id), repo.Status.Id)) {
+		val := &pipelines.DefinitionRepository{
+			Id:   helpers.StringPtr(repo.Status.Id),
+			Name: helpers.StringPtr(repo.Spec.Name),
+			Type: helpers.StringPtr(pipelines.DefinitionRepositoryType(
+				pipelines.BuildRepositoryType(helpers.String(spec.RepositoryType)))),
+		}
+		// The default branch of the previous repository is kept.
+		if def.Repository != nil {
+			val.DefaultBranch = def.Repository.DefaultBranch
+		}
+		def.Repository = val
+	}
+
+	bd := spec.BuildDefinition
+	if bd == nil {
+		return
+	}
+	if bd.CITrigger != nil {
+		def.Triggers = applyTrigger(def.Triggers, bd.CITrigger.Disabled, ciTrigger(bd.CITrigger))
+	}
+	if bd.PRTrigger != nil {
+		def.Triggers = applyTrigger(def.Triggers, bd.PRTrigger.Disabled, prTrigger(bd.PRTrigger))
+	}
+	if res.queue != nil {
+		def.Queue = res.queue
+	}
+	if bd.BadgeEnabled != nil {
+		def.BadgeEnabled = helpers.BoolPtr(*bd.BadgeEnabled)
+	}
+	if bd.Retention != nil {
+		def.RetentionRules = []pipelines.RetentionRule{retentionRule(bd.Retention)}
+	}
+	if res.variables != nil {
+		def.Variables = res.variables
+	}
+}
+
+// ciTrigger returns the fields of the continuous integration trigger of the spec.
+func ciTrigger(spec *pipelinesv1alpha1.CITrigger) pipelines.DefinitionTrigger {
+	res := pipelines.DefinitionTrigger{
+		"triggerType":        pipelines.TriggerContinuousIntegration,
+		"settingsSourceType": pipelines.SettingsSourceDefinition,
+		"branchFilters":      filters(spec.BranchFilters),
+		"pathFilters":        filters(spec.PathFilters),
+	}
+	if spec.BatchChanges != nil {
+		res["batchChanges"] = *spec.BatchChanges
+	}
+	return res
+}
+
+// prTrigger returns the fields of the pull request trigger of the spec.
+func prTrigger(spec *pipelinesv1alpha1.PRTrigger) pipelines.DefinitionTrigger {
+	res := pipelines.DefinitionTrigger{
+		"triggerType":        pipelines.TriggerPullRequest,
+		"settingsSourceType": pipelines.SettingsSourceDefinition,
+		"branchFilters":      filters(spec.BranchFilters),
+		"pathFilters":        filters(spec.PathFilters),
+	}
+	if spec.AutoCancel != nil {
+		res["autoCancel"] = *spec.AutoCancel
+	}
+	return res
+}
+
+// triggerDefaults are the fields of the new triggers besides those of the spec.
+var triggerDefaults = map[string]pipelines.DefinitionTrigger{
+	pipelines.TriggerContinuousIntegration: {
+		"batchChanges":                 false,
+		"maxConcurrentBuildsPerBranch": 1,
+	},
+	pipelines.TriggerPullRequest: {
+		"autoCancel":                      true,
+		"isCommentRequiredForPullRequest": false,
+		"forks":                           map[string]any{"enabled": false, "allowSecrets": false},
+	},
+}
+
+// isTriggerUpToDate returns true if the trigger of the type of want is
+// missing when disabled, or has the fields of want otherwise.
+func isTriggerUpToDate(triggers []pipelines.DefinitionTrigger, disabled bool, want pipelines.DefinitionTrigger) bool {
+	_, cur := findTrigger(triggers, want.TriggerType())
+	if disabled || cur == nil {
+		return disabled && cur == nil
+	}
+	for k, v := range want {
+		if !sameJSON(cur[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyTrigger removes the trigger of the type of want when disabled,
+// or sets the fields of want to it otherwise; the other fields of the
+// existing trigger are kept.
+func applyTrigger(triggers []pipelines.DefinitionTrigger, disabled bool, want pipelines.DefinitionTrigger) []pipelines.DefinitionTrigger {
+	idx, cur := findTrigger(triggers, want.TriggerType())
+	if disabled {
+		if idx >= 0 {
+			triggers = slices.Delete(triggers, idx, idx+1)
+		}
+		return triggers
+	}
+
+	res := pipelines.DefinitionTrigger{}
+	if cur == nil {
+		cur = triggerDefaults[want.TriggerType()]
+	}
+	for k, v := range cur {
+		res[k] = v
+	}
+	for k, v := range want {
+		res[k] = v
+	}
+	if idx < 0 {
+		return append(triggers, res)
+	}
+	triggers[idx] = res
+	return triggers
+}
+
+func findTrigger(triggers []pipelines.DefinitionTrigger, triggerType string) (int, pipelines.DefinitionTrigger) {
+	for i, el := range triggers {
+		if el.TriggerType() == triggerType {
+			return i, el
+		}
+	}
+	return -1, nil
+}
+
+// filters returns the branch or path filters as sent by Azure DevOps:
+// a list even when empty.
+func filters(vals []string) []string {
+	if vals == nil {
+		return []string{}
+	}
+	return vals
+}
+
+// sameJSON returns true if the values have the same JSON encoding:
+// the triggers fields are decoded as generic values.
+func sameJSON(a, b any) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(x) == string(y)
+}
+
+// retentionRule returns the retention rule of the policy of the spec.
+func retentionRule(spec *pipelinesv1alpha1.RetentionPolicy) pipelines.RetentionRule {
+	branches := spec.Branches
+	if len(branches) == 0 {
+		branches = []string{"+refs/heads/*"}
+	}
+	deleteBuildRecord := true
+	if spec.DeleteBuildRecord != nil {
+		deleteBuildRecord = *spec.DeleteBuildRecord
+	}
+	return pipelines.RetentionRule{
+		Branches:              branches,
+		ArtifactTypesToDelete: []string{"FilePath", "SymbolStore"},
+		DaysToKeep:            spec.DaysToKeep,
+		MinimumToKeep:         spec.MinimumToKeep,
+		DeleteBuildRecord:     deleteBuildRecord,
+		DeleteTestResults:     true,
+	}
+}
+
+func isRetentionUpToDate(rules []pipelines.RetentionRule, want pipelines.RetentionRule) bool {
+	if len(rules) != 1 {
+		return false
+	}
+	got := rules[0]
+	return slices.Equal(got.Branches, want.Branches) &&
+		got.DaysToKeep == want.DaysToKeep &&
+		got.MinimumToKeep == want.MinimumToKeep &&
+		got.DeleteBuildRecord == want.DeleteBuildRecord
+}
+
+// areVariablesUpToDate returns true if the build definition has the
+// variables of the spec only.
+func areVariablesUpToDate(cr *pipelinesv1alpha1.Pipeline, res *resolved, def *pipelines.BuildDefinition) bool {
+	if len(def.Variables) != len(res.variables) {
+		return false
+	}
+	for k, v := range res.variables {
+		obs, ok := def.Variables[k]
+		if !ok ||
+			obs.IsSecret != v.IsSecret ||
+			obs.AllowOverride != v.AllowOverride ||
+			!obs.IsSecret && helpers.String(obs.Value) != helpers.String(v.Value) {
+			return false
+		}
+	}
+	// Secret values are never returned: their changes are detected
+	// comparing the hashes of the values last sent.
+	for k, h := range res.hashes {
+		if cr.Status.ValueHashes[k] != h {
+			return false
+		}
+	}
+	return true
+}
+
+func isYaml(def *pipelines.BuildDefinition) bool {
+	return def.Process != nil && def.Process.Type == pipelines.ProcessYaml
+}
+
+// folder returns the path of the folder with backslash separators:
+// the root folder is '\'.
+func folder(s string) string {
+	s = strings.Trim(strings.ReplaceAll(s, "/", "\\"), "\\")
+	return "\\" + s
+}
+
+// yamlFilename returns the path of the YAML file relative to the
+// repository root.
+func yamlFilename(s string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(s, "\\", "/"), "/")
+}
@@ -0,0 +1,93 @@
+package pipelinepermissions
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	pipelinepermissionsv1alpha2 "github.com/krateoplatformops/azuredevops-provider/apis/pipelinepermissions/v1alpha2"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestPipelinePermissionLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+	pip := controllertest.Pipeline("ci", prj, srv.AddPipeline(fake.Organization, prj.Status.Id, "ci"))
+	cr := &pipelinepermissionsv1alpha2.PipelinePermission{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-ci", Namespace: controllertest.Namespace},
+		Spec: pipelinepermissionsv1alpha2.PipelinePermissionSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Resource: &pipelinepermissionsv1alpha2.Resource{
+				Type:        helpers.StringPtr(string(pipelinepermissionsv1alpha2.GitRepository)),
+				ResourceRef: controllertest.Ref(repo),
+			},
+			Pipelines: []pipelinepermissionsv1alpha2.PipelineAuthorization{
+				{Authorized: true, PipelineRef: controllertest.Ref(pip)},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, repo, pip, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected pipeline permission to be up to date")
+	}
+
+	// Authorizing all the pipelines makes the permission outdated.
+	cr.Spec.AuthorizeAll = helpers.BoolPtr(true)
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected pipeline permission to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected pipeline permission to be up to date")
+	}
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Reason != rtv1.ReasonAvailable {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"fmt"
 
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/lucasepe/httplib"
 	"k8s.io/client-go/tools/record"
@@ -39,13 +43,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(policiesv1alpha1.PolicyGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(policiesv1alpha1.PolicyKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -54,7 +60,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&policiesv1alpha1.Policy{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(policiesv1alpha1.PolicyKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -100,7 +106,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.PolicyBody.ProjectRef)
 	if err != nil {
-		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve project reference: %v", err)
+		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 
 	response := &policies.PolicyBody{}
@@ -173,12 +179,12 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.PolicyBody.ProjectRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve project reference: %v", err)
+		return fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 
 	policy, err := customResourceToPolicy(ctx, e.kube, cr)
 	if err != nil {
-		return fmt.Errorf("failed to convert custom resource to : %v", err)
+		return fmt.Errorf("failed to convert custom resource to : %w", err)
 	}
 
 	response, err := policies.Create(ctx, e.azCli, policies.CreateOptions{
@@ -188,7 +194,7 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to create Policy: %v", err)
+		return fmt.Errorf("failed to create Policy: %w", err)
 	}
 
 	cr.SetConditions(rtv1.Creating())
@@ -213,12 +219,12 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.PolicyBody.ProjectRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve project reference: %v", err)
+		return fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 
 	policy, err := customResourceToPolicy(ctx, e.kube, cr)
 	if err != nil {
-		return fmt.Errorf("failed to convert custom resource to : %v", err)
+		return fmt.Errorf("failed to convert custom resource to : %w", err)
 	}
 
 	response, err := policies.Update(ctx, e.azCli, policies.UpdateOptions{
@@ -228,7 +234,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 		ConfigurationId: *cr.Status.ID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update Policy: %v", err)
+		return fmt.Errorf("failed to update Policy: %w", err)
 	}
 
 	cr.SetConditions(rtv1.Creating())
@@ -253,7 +259,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.PolicyBody.ProjectRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve project reference: %v", err)
+		return fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 
 	err = policies.Delete(ctx, e.azCli, policies.DeleteOptions{
@@ -262,7 +268,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		ConfigurationId: *cr.Status.ID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete Policy: %v", err)
+		return fmt.Errorf("failed to delete Policy: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,108 @@
+package policies
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	policiesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/policies/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestPolicyLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+	cr := &policiesv1alpha1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "min-reviewers", Namespace: controllertest.Namespace},
+		Spec: policiesv1alpha1.PolicySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			PolicyBody: policiesv1alpha1.PolicyBody{
+				ProjectRef: controllertest.Ref(prj),
+				Type: policiesv1alpha1.PolicyType{
+					Id: "fa4e907d-c16b-4a4c-9dfa-4906e5d171dd",
+				},
+				IsBlocking: true,
+				IsEnabled:  true,
+				Settings: policiesv1alpha1.PolicySettings{
+					MinimumApproverCount: 2,
+					Scope: []policiesv1alpha1.Scope{
+						{RefName: "refs/heads/main", MatchKind: "exact", RepositoryRef: controllertest.Ref(repo)},
+					},
+				},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, repo, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected policy not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.ID == nil || cr.Status.URL == nil {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Disabling the policy makes it outdated.
+	cr.Spec.PolicyBody.IsEnabled = false
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected policy to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected policy to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected policy to be deleted")
+	}
+}
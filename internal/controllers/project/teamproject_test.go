@@ -0,0 +1,152 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+)
+
+func TestTeamProjectLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr := &projectsv1alpha1.TeamProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: controllertest.Namespace},
+		Spec: projectsv1alpha1.TeamProjectSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Organization:       fake.Organization,
+			Name:               "Demo",
+			Description:        "demo project",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected project not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if getOperationAnnotation(cr) == "" {
+		t.Fatal("expected operation annotation")
+	}
+
+	// The first observation waits for the queued operation.
+	if _, err := ext.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if getOperationAnnotation(cr) != "" {
+		t.Fatal("expected operation annotation to be removed")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	prj := srv.Project(fake.Organization, "Demo")
+	if cr.Status.Id != fake.String(prj["id"]) || meta.GetExternalName(cr) != cr.Status.Id {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	cr.Spec.Description = "updated"
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected project to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ext.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected project to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Project(fake.Organization, "Demo") != nil {
+		t.Fatal("expected project to be deleted")
+	}
+}
+
+func TestTeamProjectOperationPending(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.OperationPolls = 1
+
+	cr := &projectsv1alpha1.TeamProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: controllertest.Namespace},
+		Spec: projectsv1alpha1.TeamProjectSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Organization:       fake.Organization,
+			Name:               "Demo",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs != (reconciler.ExternalObservation{}) || getOperationAnnotation(cr) == "" {
+		t.Fatalf("expected pending operation, got: %+v", obs)
+	}
+
+	// Creating again while the operation is pending is a no-op.
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.CountRequests("POST", "/_apis/projects"); got != 1 {
+		t.Fatalf("expected 1 create request, got: %d", got)
+	}
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Status != metav1.ConditionStatus(corev1.ConditionFalse) {
+		t.Fatalf("unexpected condition: %+v", cond)
+	}
+}
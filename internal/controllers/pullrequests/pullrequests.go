@@ -6,7 +6,11 @@ import (
 	"fmt"
 
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/lucasepe/httplib"
 	"k8s.io/client-go/tools/record"
@@ -40,13 +44,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(pullrequestsv1alpha1.PullRequestGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(pullrequestsv1alpha1.PullRequestKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -55,7 +61,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&pullrequestsv1alpha1.PullRequest{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(pullrequestsv1alpha1.PullRequestKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -101,11 +107,11 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
 	if err != nil {
-		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve project reference: %v", err)
+		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 	repository, err := resolvers.ResolveGitRepository(ctx, e.kube, cr.Spec.RepositoryRef)
 	if err != nil {
-		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve repository reference: %v", err)
+		return reconciler.ExternalObservation{}, fmt.Errorf("failed to resolve repository reference: %w", err)
 	}
 
 	response := &pullrequests.PullRequest{}
@@ -176,17 +182,17 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve project reference: %v", err)
+		return fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 
 	repo, err := resolvers.ResolveGitRepository(ctx, e.kube, cr.Spec.RepositoryRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve repository reference: %v", err)
+		return fmt.Errorf("failed to resolve repository reference: %w", err)
 	}
 
 	pr, err := customResourceToPullRequest(cr)
 	if err != nil {
-		return fmt.Errorf("failed to convert custom resource to pull request: %v", err)
+		return fmt.Errorf("failed to convert custom resource to pull request: %w", err)
 	}
 
 	response, err := pullrequests.Create(ctx, e.azCli, pullrequests.CreateOptions{
@@ -197,7 +203,7 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to create pull request: %v", err)
+		return fmt.Errorf("failed to create pull request: %w", err)
 	}
 
 	cr.SetConditions(rtv1.Creating())
@@ -221,11 +227,11 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 
 	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve project reference: %v", err)
+		return fmt.Errorf("failed to resolve project reference: %w", err)
 	}
 	repository, err := resolvers.ResolveGitRepository(ctx, e.kube, cr.Spec.RepositoryRef)
 	if err != nil {
-		return fmt.Errorf("failed to resolve repository reference: %v", err)
+		return fmt.Errorf("failed to resolve repository reference: %w", err)
 	}
 	getResponse, err := pullrequests.Get(ctx, e.azCli, pullrequests.GetOptions{
 		Organization:  project.Spec.Organization,
@@ -234,12 +240,12 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 		PullRequestId: helpers.String(cr.Status.Id),
 	})
 	if err != nil && !httplib.IsNotFoundError(err) {
-		return fmt.Errorf("failed to get pull request: %v", err)
+		return fmt.Errorf("failed to get pull request: %w", err)
 	}
 
 	pr, err := createPRWithModifiedFields(cr, getResponse)
 	if err != nil {
-		return fmt.Errorf("failed to create pull request with modified fields: %v", err)
+		return fmt.Errorf("failed to create pull request with modified fields: %w", err)
 	}
 
 	response, err := pullrequests.Update(ctx, e.azCli, pullrequests.UpdateOptions{
@@ -251,7 +257,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("failed to update pull request: %v", err)
+		return fmt.Errorf("failed to update pull request: %w", err)
 	}
 
 	cr.SetConditions(rtv1.Creating())
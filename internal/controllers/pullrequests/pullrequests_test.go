@@ -0,0 +1,107 @@
+package pullrequests
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	pullrequestsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/pullrequests/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestPullRequestLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+
+	ctx := context.TODO()
+	for _, ref := range []string{"refs/heads/main", "refs/heads/feature"} {
+		_, err := repositories.CreatePush(ctx, srv.Client(), repositories.GitPushOptions{
+			Organization: fake.Organization,
+			Project:      prj.Status.Id,
+			RepositoryId: repo.Status.Id,
+			Push: &repositories.GitPush{
+				RefUpdates: &[]repositories.GitRefUpdate{{Name: helpers.StringPtr(ref)}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cr := &pullrequestsv1alpha1.PullRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "feature", Namespace: controllertest.Namespace},
+		Spec: pullrequestsv1alpha1.PullRequestSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			RepositoryRef:      controllertest.Ref(repo),
+			PullRequest: pullrequestsv1alpha1.GitPullRequest{
+				Title:         "Add feature",
+				SourceRefName: "refs/heads/feature",
+				TargetRefName: "refs/heads/main",
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, repo, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected pull request not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil {
+		t.Fatal("expected pull request identifier")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Abandoning the pull request makes it outdated.
+	cr.Spec.PullRequest.Status = "abandoned"
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected pull request to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected pull request to be up to date")
+	}
+}
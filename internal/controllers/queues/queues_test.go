@@ -0,0 +1,106 @@
+package queues
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	queuesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/queues/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestQueueLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+	srv.AddPool(fake.Organization, "Linux")
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &queuesv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "linux", Namespace: controllertest.Namespace},
+		Spec: queuesv1alpha1.QueueSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               helpers.StringPtr("Linux"),
+			Pool:               "Linux",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected queue not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil {
+		t.Fatal("expected queue identifier")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected queue to be deleted")
+	}
+}
+
+func TestQueueCreateUnknownPool(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr := &queuesv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "linux", Namespace: controllertest.Namespace},
+		Spec: queuesv1alpha1.QueueSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Organization:       helpers.StringPtr(fake.Organization),
+			Project:            helpers.StringPtr(fake.String(srv.AddProject(fake.Organization, "Demo")["name"])),
+			Pool:               "Missing",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.Create(ctx, cr); err == nil {
+		t.Fatal("expected error for unknown pool")
+	}
+}
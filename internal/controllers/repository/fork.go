@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/projects"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+)
+
+// parentRepository returns the reference to the parent repository of
+// the fork of the spec; it must belong to the same organization.
+func (e *external) parentRepository(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, organization string) (*repositories.GitRepositoryRef, error) {
+	ref := cr.Spec.Fork.ParentRef
+	if ref == nil {
+		return nil, fmt.Errorf("no parent GitRepository referenced")
+	}
+	parent, err := resolvers.ResolveGitRepository(ctx, e.kube, ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve parent GitRepository '%s': %w", ref.Name, err)
+	}
+	if len(parent.Status.Id) == 0 {
+		return nil, fmt.Errorf("parent GitRepository '%s' has not been created yet", ref.Name)
+	}
+	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, parent.Spec.ProjectRef)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve TeamProject of parent GitRepository '%s': %w", ref.Name, err)
+	}
+	if !strings.EqualFold(prj.Spec.Organization, organization) {
+		return nil, fmt.Errorf("parent GitRepository '%s' belongs to another organization", ref.Name)
+	}
+
+	return &repositories.GitRepositoryRef{
+		Id:      helpers.StringPtr(parent.Status.Id),
+		Name:    helpers.StringPtr(parent.Spec.Name),
+		Project: &projects.TeamProject{Id: helpers.StringPtr(prj.Status.Id)},
+	}, nil
+}
+
+// observeFork sets the parent repository and the last sync of the fork
+// to the status. With a fork in the spec, it returns the message of the
+// mismatch if the repository, even if adopted, is not a fork of the
+// referenced parent; the parent is verified only when it resolves, since
+// it may be deleted before the fork.
+func (e *external) observeFork(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, organization, project string, repo *repositories.GitRepository) (string, error) {
+	var mismatch string
+	if cr.Spec.Fork != nil && !meta.WasDeleted(cr) {
+		want, err := e.parentRepository(ctx, cr, organization)
+		if err == nil && (repo.ParentRepository == nil ||
+			!strings.EqualFold(helpers.String(repo.ParentRepository.Id), helpers.String(want.Id))) {
+			mismatch = fmt.Sprintf("repository '%s' is not a fork of parent GitRepository '%s'",
+				helpers.String(repo.Name), cr.Spec.Fork.ParentRef.Name)
+		}
+	}
+
+	if !helpers.Bool(repo.IsFork) || repo.ParentRepository == nil {
+		cr.Status.Parent, cr.Status.ForkSync = nil, nil
+		return mismatch, nil
+	}
+	parent := repo.ParentRepository
+	cr.Status.Parent = &repositoriesv1alpha1.ParentRepositoryStatus{
+		Id:        helpers.String(parent.Id),
+		Name:      helpers.String(parent.Name),
+		RemoteUrl: helpers.String(parent.RemoteUrl),
+	}
+	if parent.Project != nil {
+		cr.Status.Parent.Project = parent.Project.Name
+	}
+
+	// The fork sync requests are listed again only while the last one is
+	// in progress or when the spec changes.
+	if sync := cr.Status.ForkSync; sync != nil && sync.ObservedGeneration == cr.Generation &&
+		(&repositories.GitForkSyncRequest{Status: repositories.GitAsyncOperationStatus(sync.Status)}).IsDone() {
+		return mismatch, nil
+	}
+
+	all, err := repositories.ListForkSyncRequests(ctx, e.azCli, repositories.ListForkSyncRequestsOptions{
+		Organization:     organization,
+		Project:          project,
+		RepositoryId:     helpers.String(repo.Id),
+		IncludeAbandoned: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	var last *repositories.GitForkSyncRequest
+	for i := range all {
+		if last == nil || helpers.Int(all[i].OperationId) > helpers.Int(last.OperationId) {
+			last = &all[i]
+		}
+	}
+	cr.Status.ForkSync = nil
+	if last != nil {
+		cr.Status.ForkSync = forkSyncStatus(last)
+		cr.Status.ForkSync.ObservedGeneration = cr.Generation
+	}
+	return mismatch, nil
+}
+
+func forkSyncStatus(req *repositories.GitForkSyncRequest) *repositoriesv1alpha1.ForkSyncStatus {
+	res := &repositoriesv1alpha1.ForkSyncStatus{
+		OperationId: helpers.Int(req.OperationId),
+		Status:      string(req.Status),
+	}
+	if det := req.DetailedStatus; det != nil {
+		res.ErrorMessage = helpers.String(det.ErrorMessage)
+		if step := helpers.Int(det.CurrentStep); step > 0 && step <= len(det.AllSteps) {
+			res.Progress = fmt.Sprintf("%d/%d %s", step, len(det.AllSteps), det.AllSteps[step-1])
+		}
+	}
+	return res
+}
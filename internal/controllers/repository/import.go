@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/endpoints"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+// Keys of the Secret with the credentials of the imported repository.
+const (
+	importUsernameKey = "username"
+	importPasswordKey = "password"
+)
+
+// startImport requests the import of the external repository of the
+// spec into the empty repository.
+func (e *external) startImport(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, prj *projectsv1alpha1.TeamProject, repo *repositories.GitRepository) error {
+	src := cr.Spec.Import
+	params := &repositories.GitImportRequestParameters{
+		GitSource: &repositories.GitImportGitSource{Url: helpers.StringPtr(src.Url)},
+	}
+
+	switch {
+	case src.EndpointRef != nil:
+		ep, err := resolvers.ResolveEndpoint(ctx, e.kube, src.EndpointRef)
+		if err != nil {
+			return fmt.Errorf("unable to resolve Endpoint '%s': %w", src.EndpointRef.Name, err)
+		}
+		if len(helpers.String(ep.Status.Id)) == 0 {
+			return fmt.Errorf("endpoint '%s' has not been created yet", src.EndpointRef.Name)
+		}
+		params.ServiceEndpointId = ep.Status.Id
+	case src.SecretRef != nil:
+		ep, err := e.createImportEndpoint(ctx, cr, prj, repo)
+		if err != nil {
+			return err
+		}
+		params.ServiceEndpointId = ep.Id
+		params.DeleteServiceEndpointAfterImportIsDone = helpers.BoolPtr(true)
+	}
+
+	req, err := repositories.CreateImportRequest(ctx, e.azCli, repositories.CreateImportRequestOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: helpers.String(repo.Id),
+		Parameters:   params,
+	})
+	if err != nil {
+		// The service connection created for the import is not left behind.
+		if helpers.Bool(params.DeleteServiceEndpointAfterImportIsDone) {
+			derr := endpoints.Delete(ctx, e.azCli, endpoints.DeleteOptions{
+				Organization: prj.Spec.Organization,
+				ProjectIds:   []string{prj.Status.Id},
+				EndpointId:   helpers.String(params.ServiceEndpointId),
+			})
+			if derr != nil {
+				e.log.Debug("Unable to delete the import service endpoint", "id", helpers.String(params.ServiceEndpointId), "error", derr.Error())
+			}
+		}
+		return err
+	}
+	cr.Status.Import = importStatus(cr, req)
+
+	e.log.Debug("GitRepository import started", "id", helpers.String(repo.Id), "url", src.Url)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "GitRepositoryImportStarted",
+		"GitRepository import from '%s' started", src.Url)
+
+	return nil
+}
+
+// createImportEndpoint creates the 'Other Git' service connection with
+// the credentials of the Secret of the spec.
+func (e *external) createImportEndpoint(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, prj *projectsv1alpha1.TeamProject, repo *repositories.GitRepository) (*endpoints.ServiceEndpoint, error) {
+	ref := cr.Spec.Import.SecretRef
+	sec := &corev1.Secret{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, sec); err != nil {
+		return nil, fmt.Errorf("cannot get secret '%s': %w", ref.Name, err)
+	}
+	password, ok := sec.Data[importPasswordKey]
+	if !ok {
+		return nil, fmt.Errorf("key '%s' not found in secret '%s'", importPasswordKey, ref.Name)
+	}
+
+	name := fmt.Sprintf("import-%s-%s", helpers.String(repo.Name), helpers.String(repo.Id))
+	return endpoints.Create(ctx, e.azCli, endpoints.CreateOptions{
+		Organization: prj.Spec.Organization,
+		Endpoint: &endpoints.ServiceEndpoint{
+			Name: helpers.StringPtr(name),
+			Type: helpers.StringPtr("git"),
+			Url:  helpers.StringPtr(cr.Spec.Import.Url),
+			Authorization: &endpoints.EndpointAuthorization{
+				Scheme: helpers.StringPtr("UsernamePassword"),
+				Parameters: map[string]string{
+					"username": string(sec.Data[importUsernameKey]),
+					"password": string(password),
+				},
+			},
+			ServiceEndpointProjectReferences: []endpoints.ServiceEndpointProjectReference{
+				{
+					Name: helpers.StringPtr(name),
+					ProjectReference: &endpoints.ProjectReference{
+						Id:   helpers.StringPtr(prj.Status.Id),
+						Name: prj.Spec.Name,
+					},
+				},
+			},
+		},
+	})
+}
+
+// lastImportRequest returns the last import request of the repository, if any.
+func (e *external) lastImportRequest(ctx context.Context, prj *projectsv1alpha1.TeamProject, repo *repositories.GitRepository) (*repositories.GitImportRequest, error) {
+	all, err := repositories.ListImportRequests(ctx, e.azCli, repositories.ListImportRequestsOptions{
+		Organization:     prj.Spec.Organization,
+		Project:          prj.Status.Id,
+		RepositoryId:     helpers.String(repo.Id),
+		IncludeAbandoned: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var res *repositories.GitImportRequest
+	for i := range all {
+		if res == nil || helpers.Int(all[i].ImportRequestId) > helpers.Int(res.ImportRequestId) {
+			res = &all[i]
+		}
+	}
+	return res, nil
+}
+
+// observeImport returns the last import request of the repository and
+// sets its status. The import requests are listed again only while the
+// last one is in progress or when the spec changes: otherwise the request
+// of the status is returned.
+func (e *external) observeImport(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, prj *projectsv1alpha1.TeamProject, repo *repositories.GitRepository) (*repositories.GitImportRequest, error) {
+	if imp := cr.Status.Import; imp != nil && imp.ObservedGeneration == cr.Generation {
+		req := &repositories.GitImportRequest{
+			ImportRequestId: helpers.IntPtr(imp.RequestId),
+			Status:          repositories.GitAsyncOperationStatus(imp.Status),
+		}
+		if req.IsDone() {
+			return req, nil
+		}
+	}
+
+	req, err := e.lastImportRequest(ctx, prj, repo)
+	if err != nil || req == nil {
+		return req, err
+	}
+	// The failed import to retry keeps the generation it was observed at.
+	if !isImportRetried(cr, req, repo) {
+		cr.Status.Import = importStatus(cr, req)
+	}
+	return req, nil
+}
+
+// isImportRetried returns true if the import request failed and the spec
+// changed since the failure was observed: the empty repositories are
+// imported again.
+func isImportRetried(cr *repositoriesv1alpha1.GitRepository, req *repositories.GitImportRequest, repo *repositories.GitRepository) bool {
+	imp := cr.Status.Import
+	return imp != nil && imp.RequestId == helpers.Int(req.ImportRequestId) &&
+		imp.ObservedGeneration != cr.Generation &&
+		req.IsDone() && req.Status != repositories.AsyncOperationCompleted &&
+		len(helpers.String(repo.DefaultBranch)) == 0
+}
+
+func importStatus(cr *repositoriesv1alpha1.GitRepository, req *repositories.GitImportRequest) *repositoriesv1alpha1.ImportStatus {
+	res := &repositoriesv1alpha1.ImportStatus{
+		RequestId:          helpers.Int(req.ImportRequestId),
+		Status:             string(req.Status),
+		ObservedGeneration: cr.Generation,
+	}
+	if det := req.DetailedStatus; det != nil {
+		res.ErrorMessage = helpers.String(det.ErrorMessage)
+		if step := helpers.Int(det.CurrentStep); step > 0 && step <= len(det.AllSteps) {
+			res.Progress = fmt.Sprintf("%d/%d %s", step, len(det.AllSteps), det.AllSteps[step-1])
+		}
+	}
+	return res
+}
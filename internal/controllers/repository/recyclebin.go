@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	"github.com/lucasepe/httplib"
+)
+
+// restore restores the soft-deleted repository with the name of the
+// spec, if any, returning true if restored.
+func (e *external) restore(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, prj *projectsv1alpha1.TeamProject) (bool, error) {
+	del, err := repositories.FindDeleted(ctx, e.azCli, repositories.FindOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		Name:         cr.Spec.Name,
+	})
+	if err != nil {
+		if httplib.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	res, err := repositories.RestoreFromRecycleBin(ctx, e.azCli, repositories.RestoreFromRecycleBinOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: helpers.String(del.Id),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	meta.SetExternalName(cr, helpers.String(res.Id))
+	if err := e.kube.Update(ctx, cr); err != nil {
+		return false, err
+	}
+
+	e.log.Debug("GitRepository restored", "id", helpers.String(res.Id), "url", helpers.String(res.Url))
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "GitRepositoryRestored",
+		"GitRepository '%s' restored from the recycle bin", helpers.String(res.Url))
+
+	return true, nil
+}
+
+// recycleBinConflict returns the error of a repository whose name is
+// held by a soft-deleted repository, or nil if it is not.
+func (e *external) recycleBinConflict(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, prj *projectsv1alpha1.TeamProject) error {
+	del, err := repositories.FindDeleted(ctx, e.azCli, repositories.FindOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		Name:         cr.Spec.Name,
+	})
+	if err != nil || del == nil {
+		return nil
+	}
+	return fmt.Errorf("repository name '%s' is held by the soft-deleted repository '%s' in the recycle bin: "+
+		"set deletionMode to '%s' to restore it, or destroy it", cr.Spec.Name, helpers.String(del.Id), repositoriesv1alpha1.RestoreDeletion)
+}
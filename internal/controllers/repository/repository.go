@@ -2,13 +2,17 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/lucasepe/httplib"
 	corev1 "k8s.io/api/core/v1"
@@ -40,13 +44,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(repositoriesv1alpha1.GitRepositoryGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(repositoriesv1alpha1.GitRepositoryKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -55,7 +61,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&repositoriesv1alpha1.GitRepository{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(repositoriesv1alpha1.GitRepositoryKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -151,12 +157,67 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 	cr.Status.SshUrl = helpers.String(repo.SshUrl)
 	cr.Status.Url = helpers.String(repo.Url)
 	cr.Status.RemoteUrl = helpers.String(repo.RemoteUrl)
+	cr.Status.Disabled = helpers.Bool(repo.IsDisabled)
+
+	mismatch, err := e.observeFork(ctx, cr, prj.Spec.Organization, prj.Status.Id, repo)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	if len(mismatch) > 0 {
+		cr.SetConditions(repositoriesv1alpha1.ParentMismatch(mismatch))
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: isUpToDate(spec, repo),
+		}, nil
+	}
+
+	// The imported repositories are available once the import completes.
+	if spec.Import != nil {
+		req, err := e.observeImport(ctx, cr, prj, repo)
+		if err != nil {
+			return reconciler.ExternalObservation{}, err
+		}
+		switch {
+		case req != nil && isImportRetried(cr, req, repo):
+			// The spec changed since the import failed: Update requests it again.
+			cr.SetConditions(repositoriesv1alpha1.Importing())
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+			}, nil
+		case req == nil && len(helpers.String(repo.DefaultBranch)) == 0:
+			// The import has not been requested yet: Update requests it.
+			cr.SetConditions(repositoriesv1alpha1.Importing())
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: false,
+			}, nil
+		case req == nil:
+		case !req.IsDone():
+			cr.SetConditions(repositoriesv1alpha1.Importing())
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		case req.Status != repositories.AsyncOperationCompleted:
+			cr.SetConditions(repositoriesv1alpha1.ImportFailed(cr.Status.Import.ErrorMessage))
+			return reconciler.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: isUpToDate(spec, repo),
+			}, nil
+		}
+	}
+
+	// A repository with content does not need the initial commit.
+	if !isSeeded(cr) && len(helpers.String(repo.DefaultBranch)) > 0 {
+		cr.Status.Seeded = true
+	}
 
 	cr.SetConditions(rtv1.Available())
 
 	return reconciler.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceUpToDate: isUpToDate(spec, repo) && isSeeded(cr),
 	}, nil
 }
 
@@ -175,70 +236,86 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	e.log.Info("Creating resource")
 
+	// The imported repositories must be empty.
+	if cr.Spec.Import != nil && (helpers.Bool(cr.Spec.Initialize) || cr.Spec.Template != nil) {
+		return errors.New("import cannot be specified with initialize or template")
+	}
+	// The forks have the content of the parent repository.
+	if cr.Spec.Fork != nil && (helpers.Bool(cr.Spec.Initialize) || cr.Spec.Template != nil || cr.Spec.Import != nil) {
+		return errors.New("fork cannot be specified with initialize, template or import")
+	}
+
 	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
 	if err != nil || prj == nil {
 		return errors.Wrapf(err, "unble to resolve TeamProject: %s", cr.Spec.ProjectRef.Name)
 	}
 
-	res, err := repositories.Create(ctx, e.azCli, repositories.CreateOptions{
-		Organization: prj.Spec.Organization,
-		ProjectId:    prj.Status.Id,
-		Name:         cr.Spec.Name,
-	})
-	if err != nil {
-		return err
+	// A soft-deleted repository is restored as it was, in place of
+	// creating a new one.
+	if cr.Spec.DeletionMode == repositoriesv1alpha1.RestoreDeletion {
+		restored, err := e.restore(ctx, cr, prj)
+		if err != nil {
+			return errors.Wrapf(err, "unable to restore GitRepository: %s", cr.Spec.Name)
+		}
+		if restored {
+			return nil
+		}
 	}
 
-	if helpers.Bool(cr.Spec.Initialize) {
-		//repoId := meta.GetExternalName(cr)
-		//if res != nil {
-		repoId := helpers.String(res.Id)
-		//}
-		defaultBranch := helpers.String(cr.Spec.DefaultBranch)
-		if len(defaultBranch) == 0 {
-			defaultBranch = "refs/heads/main"
+	// The files of the initial commit are read before creating the
+	// repository: an invalid template does not leave it empty.
+	var files map[string][]byte
+	if !isSeeded(cr) {
+		files, err = e.seedFiles(ctx, cr)
+		if err != nil {
+			return errors.Wrap(err, "unable to read the GitRepository template")
 		}
+	}
 
-		_, err = repositories.CreatePush(ctx, e.azCli, repositories.GitPushOptions{
-			Organization: prj.Spec.Organization,
-			Project:      prj.Status.Id,
-			RepositoryId: repoId,
-			Push: &repositories.GitPush{
-				RefUpdates: &[]repositories.GitRefUpdate{
-					{
-						Name:        helpers.StringPtr(defaultBranch),
-						OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000"),
-					},
-				},
-				Commits: &[]repositories.GitCommitRef{
-					{
-						Comment: helpers.StringPtr("Initial commit."),
-						Changes: []repositories.GitChange{
-							{
-								ChangeType: repositories.ChangeTypeAdd,
-								Item: map[string]string{
-									"path": "/README.md",
-								},
-								NewContent: &repositories.ItemContent{
-									Content:     fmt.Sprintf("# %s", helpers.String(res.Name)),
-									ContentType: repositories.ContentTypeRawText,
-								},
-							},
-						},
-					},
-				},
-			},
-		})
+	opts := repositories.CreateOptions{
+		Organization: prj.Spec.Organization,
+		ProjectId:    prj.Status.Id,
+		Name:         cr.Spec.Name,
+	}
+	if fork := cr.Spec.Fork; fork != nil {
+		opts.ParentRepository, err = e.parentRepository(ctx, cr, prj.Spec.Organization)
 		if err != nil {
 			return err
 		}
+		if fork.SourceBranch != nil {
+			opts.SourceRef = branchRef(*fork.SourceBranch)
+		}
+	}
+
+	res, err := repositories.Create(ctx, e.azCli, opts)
+	if err != nil {
+		if azuredevops.IsAlreadyExists(err) {
+			if cerr := e.recycleBinConflict(ctx, cr, prj); cerr != nil {
+				return cerr
+			}
+		}
+		return err
 	}
 
+	// The external name is set at once: if the initial commit fails
+	// Update pushes it again.
 	meta.SetExternalName(cr, helpers.String(res.Id))
 	if err := e.kube.Update(ctx, cr); err != nil {
 		return err
 	}
 
+	if files != nil {
+		if err := e.seed(ctx, cr, prj.Spec.Organization, prj.Status.Id, res, files); err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.Import != nil {
+		if err := e.startImport(ctx, cr, prj, res); err != nil {
+			return err
+		}
+	}
+
 	e.log.Debug("GitRepository created", "id", helpers.String(res.Id), "url", helpers.String(res.Url))
 	e.rec.Eventf(cr, corev1.EventTypeNormal, "GitRepositoryCreated",
 		"GitRepository '%s' created", helpers.String(res.Url))
@@ -247,7 +324,110 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
-	return nil // noop
+	cr, ok := mg.(*repositoriesv1alpha1.GitRepository)
+	if !ok {
+		return errors.New(errNotGitRepository)
+	}
+
+	if !meta.IsActionAllowed(cr, meta.ActionUpdate) {
+		e.log.Debug("External resource should not be updated by provider, skip updating.")
+		return nil
+	}
+
+	e.log.Info("Updating resource")
+
+	spec := cr.Spec.DeepCopy()
+
+	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, spec.ProjectRef)
+	if err != nil {
+		return errors.Wrapf(err, "unble to resolve TeamProject: %s", spec.ProjectRef.Name)
+	}
+
+	repo, err := repositories.Get(ctx, e.azCli, repositories.GetOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		Repository:   meta.GetExternalName(cr),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The import is requested again if the request failed on creation
+	// or if the spec changed since the import failed.
+	if spec.Import != nil && len(helpers.String(repo.DefaultBranch)) == 0 {
+		req, err := e.lastImportRequest(ctx, prj, repo)
+		if err != nil {
+			return err
+		}
+		if req == nil || isImportRetried(cr, req, repo) {
+			return e.startImport(ctx, cr, prj, repo)
+		}
+	}
+
+	// The initial commit is pushed again if it failed on creation.
+	if !isSeeded(cr) && len(helpers.String(repo.DefaultBranch)) == 0 {
+		files, err := e.seedFiles(ctx, cr)
+		if err != nil {
+			return errors.Wrap(err, "unable to read the GitRepository template")
+		}
+		if err := e.seed(ctx, cr, prj.Spec.Organization, prj.Status.Id, repo, files); err != nil {
+			return err
+		}
+	}
+
+	opts := repositories.UpdateOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: helpers.String(repo.Id),
+	}
+	if helpers.String(repo.Name) != spec.Name {
+		opts.Name = helpers.StringPtr(spec.Name)
+	}
+	if isDefaultBranchChanged(spec, repo) {
+		opts.DefaultBranch = helpers.StringPtr(branchRef(helpers.String(spec.DefaultBranch)))
+	}
+	changed := opts.Name != nil || opts.DefaultBranch != nil
+
+	disabled := helpers.Bool(repo.IsDisabled)
+	wantDisabled := disabled
+	if spec.Disabled != nil {
+		wantDisabled = *spec.Disabled
+	}
+
+	// The disabled repositories must be enabled to be changed.
+	if disabled && (changed || !wantDisabled) {
+		if err := e.setDisabled(ctx, opts, false); err != nil {
+			return err
+		}
+	}
+	if changed {
+		repo, err = repositories.Update(ctx, e.azCli, opts)
+		if err != nil {
+			return err
+		}
+	}
+	if wantDisabled && (!disabled || changed) {
+		if err := e.setDisabled(ctx, opts, true); err != nil {
+			return err
+		}
+	}
+
+	e.log.Debug("GitRepository updated", "id", helpers.String(repo.Id), "name", spec.Name, "disabled", wantDisabled)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "GitRepositoryUpdated",
+		"GitRepository '%s' updated", helpers.String(repo.Url))
+
+	return nil
+}
+
+// setDisabled disables or enables the repository.
+func (e *external) setDisabled(ctx context.Context, opts repositories.UpdateOptions, disabled bool) error {
+	_, err := repositories.Update(ctx, e.azCli, repositories.UpdateOptions{
+		Organization: opts.Organization,
+		Project:      opts.Project,
+		RepositoryId: opts.RepositoryId,
+		IsDisabled:   helpers.BoolPtr(disabled),
+	})
+	return err
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -270,18 +450,60 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.Wrapf(err, "unble to resolve TeamProject: %s", cr.Spec.ProjectRef.Name)
 	}
 
-	err = repositories.Delete(ctx, e.azCli, repositories.DeleteOptions{
+	opts := repositories.DeleteOptions{
 		Organization: prj.Spec.Organization,
 		Project:      prj.Status.Id,
 		RepositoryId: cr.Status.Id,
-	})
-	if err != nil {
+	}
+	err = repositories.Delete(ctx, e.azCli, opts)
+	if err != nil && (!httplib.IsNotFoundError(err) || cr.Spec.DeletionMode != repositoriesv1alpha1.HardDeletion) {
 		return resource.Ignore(httplib.IsNotFoundError, err)
 	}
 
+	// The soft-deleted repository is destroyed, releasing its name.
+	if cr.Spec.DeletionMode == repositoriesv1alpha1.HardDeletion {
+		err = repositories.DeleteFromRecycleBin(ctx, e.azCli, opts)
+		if err != nil {
+			return resource.Ignore(httplib.IsNotFoundError, err)
+		}
+	}
+
 	e.log.Debug("GitRepository deleted", "id", cr.Status.Id, "url", cr.Status.Url)
 	e.rec.Eventf(cr, corev1.EventTypeNormal, "GitRepositoryDeleted",
 		"GitRepository '%s' deleted", cr.Status.Url)
 
 	return nil
 }
+
+// isUpToDate returns true if the repository has the name, the default
+// branch and the disabled state of the spec.
+func isUpToDate(spec *repositoriesv1alpha1.GitRepositorySpec, repo *repositories.GitRepository) bool {
+	if helpers.String(repo.Name) != spec.Name {
+		return false
+	}
+	if isDefaultBranchChanged(spec, repo) {
+		return false
+	}
+	if spec.Disabled != nil && helpers.Bool(repo.IsDisabled) != *spec.Disabled {
+		return false
+	}
+	return true
+}
+
+// isDefaultBranchChanged returns true if the spec has a different
+// default branch; the empty repositories have no default branch
+// until the first push.
+func isDefaultBranchChanged(spec *repositoriesv1alpha1.GitRepositorySpec, repo *repositories.GitRepository) bool {
+	if spec.DefaultBranch == nil || len(helpers.String(repo.DefaultBranch)) == 0 {
+		return false
+	}
+	return branchRef(helpers.String(spec.DefaultBranch)) != helpers.String(repo.DefaultBranch)
+}
+
+// branchRef returns the full ref name of the branch (i.e. 'refs/heads/main').
+func branchRef(name string) string {
+	if strings.HasPrefix(name, "refs/") {
+		return name
+	}
+	return "refs/heads/" + name
+}
@@ -0,0 +1,725 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	endpointsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+)
+
+func TestGitRepositoryLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "app",
+			Initialize:         helpers.BoolPtr(true),
+			DefaultBranch:      helpers.StringPtr("refs/heads/develop"),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected repository not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	repo := srv.Repository(fake.Organization, prj.Status.Id, "app")
+	if repo == nil || meta.GetExternalName(cr) != fake.String(repo["id"]) {
+		t.Fatalf("unexpected external name: %s", meta.GetExternalName(cr))
+	}
+	if _, ok := srv.Refs(fake.Organization, prj.Status.Id, "app")["refs/heads/develop"]; !ok {
+		t.Fatal("expected initialized default branch")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if cr.Status.Id != fake.String(repo["id"]) {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	// Rename, disable and switch the default branch of the repository.
+	_, err = repositories.CreatePush(ctx, srv.Client(), repositories.GitPushOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: cr.Status.Id,
+		Push: &repositories.GitPush{
+			RefUpdates: &[]repositories.GitRefUpdate{
+				{Name: helpers.StringPtr("refs/heads/main"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+			},
+			Commits: &[]repositories.GitCommitRef{{Comment: helpers.StringPtr("main")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr.Spec.Name = "app-renamed"
+	cr.Spec.DefaultBranch = helpers.StringPtr("main")
+	cr.Spec.Disabled = helpers.BoolPtr(true)
+	sync := func() {
+		t.Helper()
+		if err := kube.Update(ctx, cr); err != nil {
+			t.Fatal(err)
+		}
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if obs.ResourceUpToDate {
+			t.Fatal("expected drift")
+		}
+		if err := ext.Update(ctx, cr); err != nil {
+			t.Fatal(err)
+		}
+		obs, err = ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceExists || !obs.ResourceUpToDate {
+			t.Fatalf("unexpected observation: %+v", obs)
+		}
+	}
+	sync()
+	if fake.String(repo["name"]) != "app-renamed" || fake.String(repo["defaultBranch"]) != "refs/heads/main" ||
+		repo["isDisabled"] != true || !cr.Status.Disabled {
+		t.Fatalf("unexpected repository: %v", repo)
+	}
+	if srv.Repository(fake.Organization, prj.Status.Id, "app") != nil {
+		t.Fatal("expected the repository to be renamed")
+	}
+
+	// The disabled repository is enabled to be renamed.
+	cr.Spec.Name = "app"
+	cr.Spec.Disabled = helpers.BoolPtr(false)
+	sync()
+	if fake.String(repo["name"]) != "app" || repo["isDisabled"] != false {
+		t.Fatalf("unexpected repository: %v", repo)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Repository(fake.Organization, prj.Status.Id, "app") != nil {
+		t.Fatal("expected repository to be deleted")
+	}
+}
+
+func TestGitRepositorySeedRetry(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "app",
+			Initialize:         helpers.BoolPtr(true),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The initial commit fails: the repository is tracked anyway.
+	srv.HandleOnce(http.MethodPost, "{org}/{project}/_apis/git/repositories/{repository}/pushes", func(c *fake.Call) {
+		c.Error(http.StatusInternalServerError, "GitPushException", "push failed")
+	})
+	if err := ext.Create(ctx, cr); err == nil {
+		t.Fatal("expected the initial commit to fail")
+	}
+	stored := &repositoriesv1alpha1.GitRepository{}
+	if err := kube.Get(ctx, client.ObjectKeyFromObject(cr), stored); err != nil {
+		t.Fatal(err)
+	}
+	repo := srv.Repository(fake.Organization, prj.Status.Id, "app")
+	if repo == nil || meta.GetExternalName(stored) != fake.String(repo["id"]) {
+		t.Fatalf("unexpected external name: %s", meta.GetExternalName(stored))
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || obs.ResourceUpToDate || cr.Status.Seeded {
+		t.Fatalf("expected repository to be seeded, got: %+v (seeded: %t)", obs, cr.Status.Seeded)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Refs(fake.Organization, prj.Status.Id, "app")["refs/heads/main"]; !ok {
+		t.Fatal("expected initialized default branch")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate || !cr.Status.Seeded {
+		t.Fatalf("unexpected observation: %+v (seeded: %t)", obs, cr.Status.Seeded)
+	}
+	if got := srv.CountRequests(http.MethodPost, "/pushes"); got != 2 {
+		t.Fatalf("expected 2 pushes, got: %d", got)
+	}
+}
+
+func TestGitRepositoryTemplate(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	golden := controllertest.GitRepository("golden", prj, srv.AddRepository(fake.Organization, prj.Status.Id, "golden"))
+
+	ctx := context.TODO()
+	cli := srv.Client()
+	logo := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	add := func(p string, content []byte) repositories.GitChange {
+		return repositories.GitChange{
+			ChangeType: repositories.ChangeTypeAdd,
+			Item:       map[string]string{"path": p},
+			NewContent: &repositories.ItemContent{
+				Content:     base64.StdEncoding.EncodeToString(content),
+				ContentType: repositories.ContentTypeBase64Encoded,
+			},
+		}
+	}
+	_, err := repositories.CreatePush(ctx, cli, repositories.GitPushOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: golden.Status.Id,
+		Push: &repositories.GitPush{
+			RefUpdates: &[]repositories.GitRefUpdate{
+				{Name: helpers.StringPtr("refs/heads/main"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+			},
+			Commits: &[]repositories.GitCommitRef{{
+				Comment: helpers.StringPtr("Golden path"),
+				Changes: []repositories.GitChange{
+					add("/README.md", []byte("# golden")),
+					add("/ci/azure-pipelines.yml", []byte("name: {{ .Spec.Name }}")),
+					add("/logo.png", logo),
+				},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-files", Namespace: controllertest.Namespace},
+		Data: map[string]string{
+			"readme":     "# {{ .Spec.Name }} service\n",
+			"codeowners": "* @platform",
+			"unused":     "ignored",
+		},
+	}
+	cr := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "app",
+			DefaultBranch:      helpers.StringPtr("develop"),
+			Template: &repositoriesv1alpha1.RepositoryTemplate{
+				RepositoryRef: controllertest.Ref(golden),
+				ConfigMapRef:  controllertest.Ref(cm),
+				Items: []repositoriesv1alpha1.TemplateItem{
+					{Key: "readme", Path: "README.md"},
+					{Key: "codeowners", Path: "docs/CODEOWNERS"},
+					{Key: "missing", Path: "missing.txt"},
+				},
+				Render: true,
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, golden, cm, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The repository is not created when the template is invalid.
+	if err := ext.Create(ctx, cr); err == nil {
+		t.Fatal("expected missing template key error")
+	}
+	if srv.Repository(fake.Organization, prj.Status.Id, "app") != nil {
+		t.Fatal("expected repository not to be created")
+	}
+
+	cr.Spec.Template.Items = cr.Spec.Template.Items[:2]
+	pushes := srv.CountRequests("POST", "/pushes")
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.CountRequests("POST", "/pushes") - pushes; got != 1 {
+		t.Fatalf("expected a single push, got %d", got)
+	}
+	refs := srv.Refs(fake.Organization, prj.Status.Id, "app")
+	if _, ok := refs["refs/heads/develop"]; !ok || len(refs) != 1 {
+		t.Fatalf("unexpected refs: %v", refs)
+	}
+
+	want := map[string]string{
+		"/README.md":              "# app service\n",
+		"/ci/azure-pipelines.yml": "name: app",
+		"/docs/CODEOWNERS":        "* @platform",
+		"/logo.png":               string(logo),
+	}
+	items, err := repositories.ListItems(ctx, cli, repositories.ListItemsOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: meta.GetExternalName(cr),
+		Branch:       "develop",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := 0
+	for _, it := range items {
+		if helpers.Bool(it.IsFolder) {
+			continue
+		}
+		files++
+		data, err := repositories.GetItemContent(ctx, cli, repositories.GetItemContentOptions{
+			Organization: fake.Organization,
+			Project:      prj.Status.Id,
+			RepositoryId: meta.GetExternalName(cr),
+			Path:         helpers.String(it.Path),
+			Branch:       "develop",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want[helpers.String(it.Path)] {
+			t.Fatalf("unexpected content of %s: %q", helpers.String(it.Path), data)
+		}
+	}
+	if files != len(want) {
+		t.Fatalf("expected %d files, got: %+v", len(want), items)
+	}
+}
+
+func TestGitRepositoryImport(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: controllertest.Namespace},
+		Data:       map[string][]byte{"username": []byte("bot"), "password": []byte("pat")},
+	}
+	ep := &endpointsv1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: controllertest.Namespace},
+		Status: endpointsv1alpha1.EndpointStatus{
+			Id: helpers.StringPtr(fake.String(srv.AddServiceEndpoint(fake.Organization, "Demo", "github", "git")["id"])),
+		},
+	}
+	newRepository := func(name string, src *repositoriesv1alpha1.RepositoryImport) *repositoriesv1alpha1.GitRepository {
+		return &repositoriesv1alpha1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: controllertest.Namespace},
+			Spec: repositoriesv1alpha1.GitRepositorySpec{
+				ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+				ProjectRef:         controllertest.Ref(prj),
+				Name:               name,
+				Import:             src,
+			},
+		}
+	}
+	cr := newRepository("app", &repositoriesv1alpha1.RepositoryImport{
+		Url:       "https://github.com/org/app.git",
+		SecretRef: controllertest.Ref(sec),
+	})
+	shared := newRepository("lib", &repositoriesv1alpha1.RepositoryImport{
+		Url:         "https://github.com/org/lib.git",
+		EndpointRef: controllertest.Ref(ep),
+	})
+
+	kube := controllertest.NewKube(t, srv, prj, sec, ep, cr, shared)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observe := func(cr *repositoriesv1alpha1.GitRepository) {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceExists || !obs.ResourceUpToDate {
+			t.Fatalf("unexpected observation: %+v", obs)
+		}
+	}
+	lastRequest := func(cr *repositoriesv1alpha1.GitRepository) repositories.GitImportRequest {
+		t.Helper()
+		all, err := repositories.ListImportRequests(ctx, srv.Client(), repositories.ListImportRequestsOptions{
+			Organization: fake.Organization, Project: prj.Status.Id, RepositoryId: meta.GetExternalName(cr),
+		})
+		if err != nil || len(all) == 0 {
+			t.Fatalf("expected import request: %v", err)
+		}
+		return all[len(all)-1]
+	}
+
+	cr.Spec.Initialize = helpers.BoolPtr(true)
+	if err := ext.Create(ctx, cr); err == nil {
+		t.Fatal("expected import with initialize error")
+	}
+	cr.Spec.Initialize = nil
+
+	// Credentials from a Secret: a service connection is created for the import.
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	req := lastRequest(cr)
+	endpointId := helpers.String(req.Parameters.ServiceEndpointId)
+	tmp := srv.ServiceEndpoint(fake.Organization, endpointId)
+	if tmp == nil || !helpers.Bool(req.Parameters.DeleteServiceEndpointAfterImportIsDone) {
+		t.Fatalf("expected temporary service endpoint, got: %+v", req.Parameters)
+	}
+	if params := tmp["authorization"].(fake.Object)["parameters"].(fake.Object); params["username"] != "bot" || params["password"] != "pat" {
+		t.Fatalf("unexpected credentials: %v", params)
+	}
+
+	observe(cr)
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Reason != repositoriesv1alpha1.ReasonImporting {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+	if cr.Status.Import == nil || cr.Status.Import.Progress != "1/5 Processing request" {
+		t.Fatalf("unexpected import status: %+v", cr.Status.Import)
+	}
+
+	srv.CompleteImport(fake.Organization, prj.Status.Id, "app", "")
+	observe(cr)
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Reason != rtv1.ReasonAvailable {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+	if cr.Status.Import.Status != string(repositories.AsyncOperationCompleted) || cr.Status.DefaultBranch != "refs/heads/main" {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+	if srv.ServiceEndpoint(fake.Organization, endpointId) != nil {
+		t.Fatal("expected temporary service endpoint to be deleted")
+	}
+
+	// Credentials from an Endpoint: the service connection is kept.
+	if err := ext.Create(ctx, shared); err != nil {
+		t.Fatal(err)
+	}
+	if req := lastRequest(shared); helpers.String(req.Parameters.ServiceEndpointId) != helpers.String(ep.Status.Id) {
+		t.Fatalf("unexpected service endpoint: %+v", req.Parameters)
+	}
+	srv.CompleteImport(fake.Organization, prj.Status.Id, "lib", "authentication failed")
+	observe(shared)
+	cond := shared.GetCondition(rtv1.TypeReady)
+	if cond.Reason != repositoriesv1alpha1.ReasonImportFailed || cond.Message != "authentication failed" {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+	if srv.ServiceEndpoint(fake.Organization, helpers.String(ep.Status.Id)) == nil {
+		t.Fatal("expected shared service endpoint to be kept")
+	}
+
+	// The failed import is not listed again until the spec changes.
+	if err := kube.Status().Update(ctx, shared); err != nil {
+		t.Fatal(err)
+	}
+	imports := srv.CountRequests("GET", "/importRequests")
+	observe(shared)
+	if got := srv.CountRequests("GET", "/importRequests") - imports; got != 0 {
+		t.Fatalf("expected no import requests listing, got %d", got)
+	}
+	if cond := shared.GetCondition(rtv1.TypeReady); cond.Reason != repositoriesv1alpha1.ReasonImportFailed {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+
+	// The URL is fixed: the import is requested again.
+	failed := lastRequest(shared)
+	shared.Spec.Import.Url = "https://github.com/org/lib-v2.git"
+	shared.Generation++
+	obs, err := ext.Observe(ctx, shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate || shared.GetCondition(rtv1.TypeReady).Reason != repositoriesv1alpha1.ReasonImporting {
+		t.Fatalf("expected failed import to be retried, got: %+v", obs)
+	}
+	if err := ext.Update(ctx, shared); err != nil {
+		t.Fatal(err)
+	}
+	req = lastRequest(shared)
+	if helpers.Int(req.ImportRequestId) == helpers.Int(failed.ImportRequestId) ||
+		helpers.String(req.Parameters.GitSource.Url) != "https://github.com/org/lib-v2.git" {
+		t.Fatalf("expected a new import request, got: %+v", req)
+	}
+	if err := kube.Status().Update(ctx, shared); err != nil {
+		t.Fatal(err)
+	}
+	srv.CompleteImport(fake.Organization, prj.Status.Id, "lib", "")
+	observe(shared)
+	if cond := shared.GetCondition(rtv1.TypeReady); cond.Reason != rtv1.ReasonAvailable {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+}
+
+func TestGitRepositoryFork(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	upstream := controllertest.TeamProject("upstream", srv.AddProject(fake.Organization, "Upstream"))
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	lib := controllertest.GitRepository("lib", upstream, srv.AddRepository(fake.Organization, upstream.Status.Id, "lib"))
+	other := controllertest.GitRepository("other", upstream, srv.AddRepository(fake.Organization, upstream.Status.Id, "other"))
+
+	ctx := context.TODO()
+	_, err := repositories.CreatePush(ctx, srv.Client(), repositories.GitPushOptions{
+		Organization: fake.Organization,
+		Project:      upstream.Status.Id,
+		RepositoryId: lib.Status.Id,
+		Push: &repositories.GitPush{
+			RefUpdates: &[]repositories.GitRefUpdate{
+				{Name: helpers.StringPtr("refs/heads/main"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+				{Name: helpers.StringPtr("refs/heads/release"), OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000")},
+			},
+			Commits: &[]repositories.GitCommitRef{{Comment: helpers.StringPtr("Initial commit")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "lib-fork", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "lib-fork",
+			Fork: &repositoriesv1alpha1.RepositoryFork{
+				ParentRef:    controllertest.Ref(lib),
+				SourceBranch: helpers.StringPtr("release"),
+			},
+		},
+	}
+
+	plain := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(upstream),
+			Name:               "other",
+			Fork:               &repositoriesv1alpha1.RepositoryFork{ParentRef: controllertest.Ref(lib)},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, upstream, prj, lib, other, cr, plain)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observe := func() {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceExists || !obs.ResourceUpToDate {
+			t.Fatalf("unexpected observation: %+v", obs)
+		}
+	}
+
+	cr.Spec.Initialize = helpers.BoolPtr(true)
+	if err := ext.Create(ctx, cr); err == nil {
+		t.Fatal("expected fork with initialize error")
+	}
+	cr.Spec.Initialize = nil
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	observe()
+	if p := cr.Status.Parent; p == nil || p.Id != lib.Status.Id || p.Name != "lib" || p.Project != "Upstream" {
+		t.Fatalf("unexpected parent: %+v", p)
+	}
+	if s := cr.Status.ForkSync; s == nil || s.Status != string(repositories.AsyncOperationInProgress) || s.Progress != "1/3 Processing request" {
+		t.Fatalf("unexpected fork sync: %+v", s)
+	}
+
+	srv.CompleteForkSync(fake.Organization, prj.Status.Id, "lib-fork", "")
+	observe()
+	if cr.Status.ForkSync.Status != string(repositories.AsyncOperationCompleted) || cr.Status.DefaultBranch != "refs/heads/release" {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+	if refs := srv.Refs(fake.Organization, prj.Status.Id, "lib-fork"); len(refs) != 1 {
+		t.Fatalf("expected only the source branch, got: %v", refs)
+	}
+
+	// The completed sync is not listed again until the spec changes.
+	if err := kube.Status().Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	syncs := srv.CountRequests("GET", "/forkSyncRequests")
+	if syncs == 0 {
+		t.Fatal("expected fork sync requests to be listed")
+	}
+	observe()
+	if got := srv.CountRequests("GET", "/forkSyncRequests") - syncs; got != 0 {
+		t.Fatalf("expected no fork sync requests listing, got %d", got)
+	}
+
+	// An adopted repository must be a fork of the referenced parent.
+	cr.Spec.Fork.ParentRef = controllertest.Ref(other)
+	observe()
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Reason != repositoriesv1alpha1.ReasonParentMismatch {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+	obs, err := ext.Observe(ctx, plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond := plain.GetCondition(rtv1.TypeReady); !obs.ResourceExists ||
+		cond.Reason != repositoriesv1alpha1.ReasonParentMismatch || !strings.Contains(cond.Message, "is not a fork") {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+
+	// The parent deleted before the fork is not verified.
+	cr.Spec.Fork.ParentRef = controllertest.Ref(lib)
+	if err := kube.Delete(ctx, lib); err != nil {
+		t.Fatal(err)
+	}
+	observe()
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Reason != rtv1.ReasonAvailable {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+}
+
+func TestGitRepositoryDeletionMode(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &repositoriesv1alpha1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: controllertest.Namespace},
+		Spec: repositoriesv1alpha1.GitRepositorySpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "app",
+			Initialize:         helpers.BoolPtr(true),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recreate := func() error {
+		t.Helper()
+		meta.SetExternalName(cr, "")
+		cr.Status.Id = ""
+		return ext.Create(ctx, cr)
+	}
+	observe := func() {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !obs.ResourceExists {
+			t.Fatal("expected repository to exist")
+		}
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	observe()
+	id := cr.Status.Id
+
+	// SoftDelete: the name is held by the recycle bin.
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := recreate(); err == nil || !strings.Contains(err.Error(), "recycle bin") {
+		t.Fatalf("expected recycle bin conflict, got: %v", err)
+	}
+
+	// Restore: the soft-deleted repository comes back with its content.
+	cr.Spec.DeletionMode = repositoriesv1alpha1.RestoreDeletion
+	pushes := srv.CountRequests("POST", "/pushes")
+	if err := recreate(); err != nil {
+		t.Fatal(err)
+	}
+	if meta.GetExternalName(cr) != id || srv.CountRequests("POST", "/pushes") != pushes {
+		t.Fatalf("expected restored repository %s, got: %s", id, meta.GetExternalName(cr))
+	}
+	if _, ok := srv.Refs(fake.Organization, prj.Status.Id, "app")["refs/heads/main"]; !ok {
+		t.Fatal("expected restored branches")
+	}
+	observe()
+
+	// HardDelete: the name is released.
+	cr.Spec.DeletionMode = repositoriesv1alpha1.HardDeletion
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := recreate(); err != nil {
+		t.Fatal(err)
+	}
+	if meta.GetExternalName(cr) == id {
+		t.Fatal("expected a new repository")
+	}
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+
+	repositoriesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositories/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/repositories"
+	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+)
+
+// templateFiles returns the contents of the files of the template of
+// the repository by path.
+func (e *external) templateFiles(ctx context.Context, cr *repositoriesv1alpha1.GitRepository) (map[string][]byte, error) {
+	tpl := cr.Spec.Template
+	files := map[string][]byte{}
+
+	if ref := tpl.RepositoryRef; ref != nil {
+		if err := e.copyRepositoryFiles(ctx, ref, helpers.String(tpl.Branch), files); err != nil {
+			return nil, err
+		}
+	}
+
+	data := map[string][]byte{}
+	if ref := tpl.ConfigMapRef; ref != nil {
+		cm := &corev1.ConfigMap{}
+		if err := e.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return nil, fmt.Errorf("cannot get configmap '%s': %w", ref.Name, err)
+		}
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			data[k] = v
+		}
+	}
+	if ref := tpl.SecretRef; ref != nil {
+		sec := &corev1.Secret{}
+		if err := e.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, sec); err != nil {
+			return nil, fmt.Errorf("cannot get secret '%s': %w", ref.Name, err)
+		}
+		for k, v := range sec.Data {
+			data[k] = v
+		}
+	}
+	if len(tpl.Items) == 0 {
+		for k, v := range data {
+			files[filePath(k)] = v
+		}
+	}
+	for _, it := range tpl.Items {
+		val, ok := data[it.Key]
+		if !ok {
+			return nil, fmt.Errorf("key '%s' not found in the template configmap or secret", it.Key)
+		}
+		files[filePath(it.Path)] = val
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("the template of the repository has no files")
+	}
+
+	if tpl.Render {
+		for p, val := range files {
+			// The binary files are copied as they are.
+			if !utf8.Valid(val) {
+				continue
+			}
+			res, err := render(p, val, cr)
+			if err != nil {
+				return nil, err
+			}
+			files[p] = res
+		}
+	}
+
+	return files, nil
+}
+
+// copyRepositoryFiles reads the files of a branch of the referenced
+// GitRepository.
+func (e *external) copyRepositoryFiles(ctx context.Context, ref *rtv1.Reference, branch string, files map[string][]byte) error {
+	src, err := resolvers.ResolveGitRepository(ctx, e.kube, ref)
+	if err != nil {
+		return fmt.Errorf("unable to resolve template GitRepository '%s': %w", ref.Name, err)
+	}
+	if len(src.Status.Id) == 0 {
+		return fmt.Errorf("template GitRepository '%s' has not been created yet", ref.Name)
+	}
+	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, src.Spec.ProjectRef)
+	if err != nil {
+		return fmt.Errorf("unable to resolve TeamProject of template GitRepository '%s': %w", ref.Name, err)
+	}
+
+	items, err := repositories.ListItems(ctx, e.azCli, repositories.ListItemsOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		RepositoryId: src.Status.Id,
+		Branch:       branch,
+	})
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if helpers.Bool(it.IsFolder) || helpers.String(it.GitObjectType) == "tree" {
+			continue
+		}
+		val, err := repositories.GetItemContent(ctx, e.azCli, repositories.GetItemContentOptions{
+			Organization: prj.Spec.Organization,
+			Project:      prj.Status.Id,
+			RepositoryId: src.Status.Id,
+			Path:         helpers.String(it.Path),
+			Branch:       branch,
+		})
+		if err != nil {
+			return err
+		}
+		files[filePath(helpers.String(it.Path))] = val
+	}
+	return nil
+}
+
+// render executes the file as a Go template of the GitRepository.
+func render(name string, text []byte, cr *repositoriesv1alpha1.GitRepository) ([]byte, error) {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template file '%s': %w", name, err)
+	}
+	buf := bytes.Buffer{}
+	if err := tpl.Execute(&buf, cr); err != nil {
+		return nil, fmt.Errorf("cannot render template file '%s': %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// isSeeded reports whether the initial commit of the repository, if
+// required by the spec, is pushed.
+func isSeeded(cr *repositoriesv1alpha1.GitRepository) bool {
+	if !helpers.Bool(cr.Spec.Initialize) && cr.Spec.Template == nil {
+		return true
+	}
+	return cr.Status.Seeded
+}
+
+// seedFiles returns the files of the initial commit of the repository.
+func (e *external) seedFiles(ctx context.Context, cr *repositoriesv1alpha1.GitRepository) (map[string][]byte, error) {
+	if cr.Spec.Template != nil {
+		return e.templateFiles(ctx, cr)
+	}
+	return map[string][]byte{
+		"/README.md": []byte(fmt.Sprintf("# %s", cr.Spec.Name)),
+	}, nil
+}
+
+// seed pushes the initial commit of the repository on the default
+// branch and records it in the status.
+func (e *external) seed(ctx context.Context, cr *repositoriesv1alpha1.GitRepository, organization, projectId string, repo *repositories.GitRepository, files map[string][]byte) error {
+	defaultBranch := helpers.String(cr.Spec.DefaultBranch)
+	if len(defaultBranch) == 0 {
+		defaultBranch = "main"
+	}
+
+	_, err := repositories.CreatePush(ctx, e.azCli, repositories.GitPushOptions{
+		Organization: organization,
+		Project:      projectId,
+		RepositoryId: helpers.String(repo.Id),
+		Push: &repositories.GitPush{
+			RefUpdates: &[]repositories.GitRefUpdate{
+				{
+					Name:        helpers.StringPtr(branchRef(defaultBranch)),
+					OldObjectId: helpers.StringPtr("0000000000000000000000000000000000000000"),
+				},
+			},
+			Commits: &[]repositories.GitCommitRef{
+				{
+					Comment: helpers.StringPtr("Initial commit."),
+					Changes: initialChanges(files),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot push the initial commit: %w", err)
+	}
+	repo.DefaultBranch = helpers.StringPtr(branchRef(defaultBranch))
+
+	cr.Status.Seeded = true
+	cr.Status.DefaultBranch = helpers.String(repo.DefaultBranch)
+
+	return e.kube.Status().Update(ctx, cr)
+}
+
+// initialChanges returns the changes adding the files sorted by path.
+func initialChanges(files map[string][]byte) []repositories.GitChange {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	res := make([]repositories.GitChange, 0, len(paths))
+	for _, p := range paths {
+		res = append(res, repositories.GitChange{
+			ChangeType: repositories.ChangeTypeAdd,
+			Item:       map[string]string{"path": p},
+			NewContent: &repositories.ItemContent{
+				Content:     base64.StdEncoding.EncodeToString(files[p]),
+				ContentType: repositories.ContentTypeBase64Encoded,
+			},
+		})
+	}
+	return res
+}
+
+// filePath returns the absolute path of a file of the repository.
+func filePath(p string) string {
+	return path.Clean("/" + strings.TrimPrefix(p, "/"))
+}
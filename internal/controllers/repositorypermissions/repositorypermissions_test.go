@@ -0,0 +1,96 @@
+package repositorypermissions
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	repositorypermissionsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/repositorypermissions/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/identities"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestRepositoryPermissionLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	repo := controllertest.GitRepository("demo", prj, srv.Repository(fake.Organization, prj.Status.Id, "Demo"))
+	cr := &repositorypermissionsv1alpha1.RepositoryPermission{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-build", Namespace: controllertest.Namespace},
+		Spec: repositorypermissionsv1alpha1.RepositoryPermissionSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			RepositoryRef:      controllertest.Ref(repo),
+			Permissions: &repositorypermissionsv1alpha1.Permissions{
+				Identity: &repositorypermissionsv1alpha1.Identity{
+					Type:       helpers.StringPtr(string(identities.BuildService)),
+					ProjectRef: controllertest.Ref(prj),
+				},
+				AllowList: []string{"genericread", "genericcontribute"},
+				DenyList:  []string{"forcepush"},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, repo, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Status.IdentityDescriptor) == 0 || cr.Status.AllowPermissionBit == nil {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected repository permission to be up to date")
+	}
+
+	// Bits set outside of the spec make the permission outdated unless merging.
+	cr.Spec.Permissions.AllowList = []string{"genericread"}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected repository permission to be outdated")
+	}
+
+	cr.Spec.Permissions.Merge = true
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected merged repository permission to be up to date")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -20,13 +21,17 @@ import (
 	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
 	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
 	"github.com/krateoplatformops/provider-runtime/pkg/resource"
-	"github.com/lucasepe/httplib"
 	"github.com/pkg/errors"
 
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
 	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/runs"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 )
 
 const (
@@ -40,13 +45,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(runsv1alpha1.RunGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(runsv1alpha1.RunKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -55,7 +62,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&runsv1alpha1.Run{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(runsv1alpha1.RunKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -100,25 +107,20 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		return reconciler.ExternalObservation{}, errors.New(errNotCR)
 	}
 
-	spec := cr.Spec.DeepCopy()
-
-	pip, err := resolvers.ResolvePipeline(ctx, e.kube, spec.PipelineRef)
-	if err != nil || pip == nil {
-		return reconciler.ExternalObservation{},
-			errors.Wrapf(err, "unble to resolve Pipeline: %s", spec.PipelineRef.Name)
-	}
-
-	pipelineId, err := strconv.Atoi(helpers.String(pip.Status.Id))
+	prj, pipelineId, err := e.resolvePipeline(ctx, cr)
 	if err != nil {
+		// The Pipeline may be deleted before its Runs: without it the
+		// run can be neither observed nor canceled.
+		if meta.WasDeleted(cr) && apierrors.IsNotFound(err) {
+			e.log.Debug("Pipeline of the Run being deleted not found, skip canceling.")
+			return reconciler.ExternalObservation{
+				ResourceExists:   false,
+				ResourceUpToDate: true,
+			}, nil
+		}
 		return reconciler.ExternalObservation{}, err
 	}
 
-	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, pip.Spec.ProjectRef)
-	if err != nil || prj == nil {
-		return reconciler.ExternalObservation{},
-			errors.Wrapf(err, "unble to resolve Project: %s", pip.Spec.ProjectRef.Name)
-	}
-
 	var run *runs.RunInfo
 	if runId := meta.GetExternalName(cr); runId != "" {
 		id, err := strconv.Atoi(runId)
@@ -132,7 +134,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 			PipelineId:   pipelineId,
 			RunId:        id,
 		})
-		if err != nil && !httplib.IsNotFoundError(err) {
+		if err != nil && !azuredevops.IsNotFound(err) {
 			return reconciler.ExternalObservation{}, err
 		}
 	}
@@ -144,20 +146,22 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		}, nil
 	}
 
-	cr.SetConditions(rtv1.Available())
-
-	cr.Status.Id = helpers.IntPtr(*run.Id)
-	cr.Status.PipelineId = helpers.IntPtr(pipelineId)
-	cr.Status.State = helpers.StringPtr(*run.State)
-	cr.Status.Url = helpers.StringPtr(*run.Url)
+	if err := e.setStatus(ctx, cr, prj, pipelineId, run); err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
 
-	//if err := e.kube.Update(ctx, cr); err != nil {
-	//	return reconciler.ExternalObservation{}, err
-	//}
+	// Runs are never deleted: a completed run is gone for the Run
+	// being deleted, while the runs in progress must be canceled.
+	if meta.WasDeleted(cr) {
+		return reconciler.ExternalObservation{
+			ResourceExists:   !isCompleted(run),
+			ResourceUpToDate: true,
+		}, nil
+	}
 
 	return reconciler.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceUpToDate: !isTimedOut(cr, run),
 	}, nil
 }
 
@@ -176,23 +180,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 
 	cr.SetConditions(rtv1.Creating())
 
-	spec := cr.Spec.DeepCopy()
-
-	pip, err := resolvers.ResolvePipeline(ctx, e.kube, spec.PipelineRef)
-	if err != nil || pip == nil {
-		return errors.Wrapf(err, "unble to resolve Pipeline: %s", spec.PipelineRef.Name)
-	}
-
-	pipelineId, err := strconv.Atoi(helpers.String(pip.Status.Id))
+	prj, pipelineId, err := e.resolvePipeline(ctx, cr)
 	if err != nil {
 		return err
 	}
 
-	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, pip.Spec.ProjectRef)
-	if err != nil || prj == nil {
-		return errors.Wrapf(err, "unble to resolve Project: %s", pip.Spec.ProjectRef.Name)
-	}
-
 	if cr.Spec.RunParameters == nil {
 		cr.Spec.RunParameters = &runsv1alpha1.RunPipelineParameters{}
 	}
@@ -241,10 +233,90 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 	return nil
 }
 
+// Update cancels the runs in progress after the timeout.
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
-	return nil // noop
+	cr, ok := mg.(*runsv1alpha1.Run)
+	if !ok {
+		return errors.New(errNotCR)
+	}
+
+	if !meta.IsActionAllowed(cr, meta.ActionUpdate) {
+		e.log.Debug("External resource should not be updated by provider, skip updating.")
+		return nil
+	}
+
+	if cr.Spec.Timeout == nil || helpers.String(cr.Status.State) == runStateCompleted {
+		return nil
+	}
+
+	e.log.Info("Canceling timed out run", "timeout", cr.Spec.Timeout.Duration.String())
+
+	if err := e.cancel(ctx, cr); err != nil {
+		return err
+	}
+
+	e.rec.Eventf(cr, corev1.EventTypeWarning, "PipelineRunTimedOut",
+		"Run '%s' canceled after %s", helpers.String(cr.Status.Url), cr.Spec.Timeout.Duration)
+
+	return nil
 }
 
+// Delete cancels the run in progress.
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	return nil // noop
+	cr, ok := mg.(*runsv1alpha1.Run)
+	if !ok {
+		return errors.New(errNotCR)
+	}
+
+	if !meta.IsActionAllowed(cr, meta.ActionDelete) {
+		e.log.Debug("External resource should not be deleted by provider, skip deleting.")
+		return nil
+	}
+
+	cr.SetConditions(rtv1.Deleting())
+
+	if state := helpers.String(cr.Status.State); state == runStateCompleted || state == runStateCanceling {
+		return nil
+	}
+
+	e.log.Info("Canceling run")
+
+	return e.cancel(ctx, cr)
+}
+
+// resolvePipeline returns the project and the id of the pipeline of the run.
+func (e *external) resolvePipeline(ctx context.Context, cr *runsv1alpha1.Run) (*projectsv1alpha1.TeamProject, int, error) {
+	pip, err := resolvers.ResolvePipeline(ctx, e.kube, cr.Spec.PipelineRef)
+	if err != nil || pip == nil {
+		return nil, 0, errors.Wrapf(err, "unble to resolve Pipeline: %s", cr.Spec.PipelineRef.Name)
+	}
+
+	pipelineId, err := strconv.Atoi(helpers.String(pip.Status.Id))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	prj, err := resolvers.ResolveTeamProject(ctx, e.kube, pip.Spec.ProjectRef)
+	if err != nil || prj == nil {
+		return nil, 0, errors.Wrapf(err, "unble to resolve Project: %s", pip.Spec.ProjectRef.Name)
+	}
+
+	return prj, pipelineId, nil
+}
+
+func (e *external) cancel(ctx context.Context, cr *runsv1alpha1.Run) error {
+	prj, _, err := e.resolvePipeline(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	err = runs.Cancel(ctx, e.azCli, runs.CancelOptions{
+		Organization: prj.Spec.Organization,
+		Project:      prj.Status.Id,
+		RunId:        helpers.Int(cr.Status.Id),
+	})
+	if err != nil && !azuredevops.IsNotFound(err) {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,313 @@
+package run
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	pipelinesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/pipelines/v1alpha1"
+	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+)
+
+func TestRunLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	pip := controllertest.Pipeline("ci", prj, srv.AddPipeline(fake.Organization, prj.Status.Id, "ci"))
+	cr := &runsv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-1", Namespace: controllertest.Namespace},
+		Spec: runsv1alpha1.RunSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			PipelineRef:        controllertest.Ref(pip),
+			RunParameters: &runsv1alpha1.RunPipelineParameters{
+				TemplateParameters: map[string]string{"env": "test"},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, pip, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected run not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	runId, err := strconv.Atoi(meta.GetExternalName(cr))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if helpers.String(cr.Status.State) != "inProgress" {
+		t.Fatalf("unexpected state: %s", helpers.String(cr.Status.State))
+	}
+
+	pipelineId, _ := strconv.Atoi(helpers.String(pip.Status.Id))
+	if !srv.CompleteRun(fake.Organization, prj.Status.Id, pipelineId, runId, "succeeded") {
+		t.Fatal("run not found")
+	}
+	if _, err := ext.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if helpers.String(cr.Status.State) != "completed" || helpers.String(cr.Status.Result) != "succeeded" {
+		t.Fatalf("unexpected state: %s (%s)", helpers.String(cr.Status.State), helpers.String(cr.Status.Result))
+	}
+	if cr.Status.CreatedDate == nil || cr.Status.FinishedDate == nil {
+		t.Fatalf("unexpected status dates: %+v", cr.Status)
+	}
+	if len(cr.Status.Stages) != 1 || helpers.String(cr.Status.Stages[0].Result) != "succeeded" {
+		t.Fatalf("unexpected stages: %+v", cr.Status.Stages)
+	}
+	if cond := cr.GetCondition(runsv1alpha1.TypeCompleted); cond.Reason != runsv1alpha1.ReasonSucceeded {
+		t.Fatalf("unexpected completed condition: %+v", cond)
+	}
+	if cond := cr.GetCondition(rtv1.TypeReady); cond.Status != metav1.ConditionTrue {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+	expectEvent(t, c.recorder, "PipelineRunSucceeded")
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunCanceledOnDelete(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr, ext, _ := newRun(t, srv, nil)
+
+	ctx := context.TODO()
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists {
+		t.Fatal("expected run in progress to exist")
+	}
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected canceled run not to exist")
+	}
+	if helpers.String(cr.Status.Result) != "canceled" {
+		t.Fatalf("unexpected result: %s", helpers.String(cr.Status.Result))
+	}
+}
+
+func TestRunDeletedAfterPipeline(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr, ext, _ := newRun(t, srv, nil)
+
+	ctx := context.TODO()
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	pip := &pipelinesv1alpha1.Pipeline{}
+	if err := ext.kube.Get(ctx, types.NamespacedName{Namespace: cr.Spec.PipelineRef.Namespace, Name: cr.Spec.PipelineRef.Name}, pip); err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.kube.Delete(ctx, pip); err != nil {
+		t.Fatal(err)
+	}
+
+	// The live Runs report the missing Pipeline.
+	if _, err := ext.Observe(ctx, cr); err == nil {
+		t.Fatal("expected the missing Pipeline to be reported")
+	}
+
+	// The Runs being deleted do not wait for it.
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected run of the deleted Pipeline not to exist")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr, ext, rec := newRun(t, srv, &metav1.Duration{Duration: time.Nanosecond})
+
+	ctx := context.TODO()
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	// The fake server dates have a resolution of one second.
+	time.Sleep(time.Second)
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected timed out run to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, rec, "PipelineRunTimedOut")
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if cond := cr.GetCondition(runsv1alpha1.TypeCompleted); cond.Reason != runsv1alpha1.ReasonCanceled {
+		t.Fatalf("unexpected completed condition: %+v", cond)
+	}
+	expectEvent(t, rec, "PipelineRunCanceled")
+}
+
+func newRun(t *testing.T, srv *fake.Server, timeout *metav1.Duration) (*runsv1alpha1.Run, *external, *record.FakeRecorder) {
+	t.Helper()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	pip := controllertest.Pipeline("ci", prj, srv.AddPipeline(fake.Organization, prj.Status.Id, "ci"))
+	cr := &runsv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-1", Namespace: controllertest.Namespace},
+		Spec: runsv1alpha1.RunSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			PipelineRef:        controllertest.Ref(pip),
+			Timeout:            timeout,
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, pip, cr)
+	rec := record.NewFakeRecorder(10)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: rec}
+
+	ext, err := c.Connect(context.TODO(), cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cr, ext.(*external), rec
+}
+
+// expectEvent drains the recorded events until the one with the reason.
+func expectEvent(t *testing.T, rec record.EventRecorder, reason string) {
+	t.Helper()
+
+	events := rec.(*record.FakeRecorder).Events
+	for {
+		select {
+		case ev := <-events:
+			if strings.Contains(ev, " "+reason+" ") {
+				return
+			}
+		default:
+			t.Fatalf("expected %s event", reason)
+		}
+	}
+}
+
+func TestRunFailureDetails(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	cr, ext, rec := newRun(t, srv, nil)
+	cr.Spec.FailureLogs = &runsv1alpha1.FailureLogs{
+		ConfigMapRef: &rtv1.Reference{Name: "ci-logs", Namespace: controllertest.Namespace},
+		TailLines:    helpers.IntPtr(10),
+	}
+
+	ctx := context.TODO()
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	_, pipelineId, err := ext.resolvePipeline(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runId, _ := strconv.Atoi(meta.GetExternalName(cr))
+	srv.PublishArtifact(fake.Organization, "Demo", runId, "drop")
+	srv.CompleteRun(fake.Organization, "Demo", pipelineId, runId, "failed")
+
+	if _, err := ext.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cond := cr.GetCondition(runsv1alpha1.TypeCompleted); cond.Reason != runsv1alpha1.ReasonFailed {
+		t.Fatalf("unexpected completed condition: %+v", cond)
+	}
+	if len(cr.Status.FailedTasks) != 1 {
+		t.Fatalf("unexpected failed tasks: %+v", cr.Status.FailedTasks)
+	}
+	task := cr.Status.FailedTasks[0]
+	if task.Name != "Run tests" || helpers.String(task.Stage) != "Build" ||
+		len(task.Errors) != 1 || task.Errors[0] != "Process completed with exit code 1." {
+		t.Fatalf("unexpected failed task: %+v", task)
+	}
+	if len(cr.Status.Artifacts) != 1 || cr.Status.Artifacts[0].Name != "drop" ||
+		helpers.String(cr.Status.Artifacts[0].DownloadUrl) == "" {
+		t.Fatalf("unexpected artifacts: %+v", cr.Status.Artifacts)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := ext.kube.Get(ctx, types.NamespacedName{Name: "ci-logs", Namespace: controllertest.Namespace}, cm); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(cm.Data["Run-tests.log"], "\n"), "\n")
+	if len(lines) != 10 || !strings.HasPrefix(lines[9], "##[error]") {
+		t.Fatalf("unexpected log tail: %q", cm.Data["Run-tests.log"])
+	}
+	expectEvent(t, rec, "FailureLogsPublished")
+	expectEvent(t, rec, "PipelineRunFailed")
+}
@@ -0,0 +1,267 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	projectsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
+	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/runs"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/pkg/errors"
+)
+
+// States and results of the pipeline runs.
+const (
+	runStateCompleted = "completed"
+	runStateCanceling = "canceling"
+
+	runResultSucceeded = "succeeded"
+	runResultFailed    = "failed"
+	runResultCanceled  = "canceled"
+)
+
+// setStatus copies the observed run in the status and sets the
+// conditions, emitting an event when the run completes.
+func (e *external) setStatus(ctx context.Context, cr *runsv1alpha1.Run, prj *projectsv1alpha1.TeamProject, pipelineId int, run *runs.RunInfo) error {
+	// The timeline and the artifacts of a completed run do not change
+	// anymore; they are fetched before updating the state so that a
+	// failure is retried on the next observation.
+	if helpers.String(cr.Status.State) != runStateCompleted {
+		records, err := runs.GetTimeline(ctx, e.azCli, runs.GetTimelineOptions{
+			Organization: prj.Spec.Organization,
+			Project:      prj.Status.Id,
+			RunId:        *run.Id,
+		})
+		if err != nil && !azuredevops.IsNotFound(err) {
+			return err
+		}
+
+		if isCompleted(run) {
+			artifacts, err := runs.ListArtifacts(ctx, e.azCli, runs.ListArtifactsOptions{
+				Organization: prj.Spec.Organization,
+				Project:      prj.Status.Id,
+				RunId:        *run.Id,
+			})
+			if err != nil && !azuredevops.IsNotFound(err) {
+				return err
+			}
+			if err := e.publishFailureLogs(ctx, cr, prj, *run.Id, records); err != nil {
+				return err
+			}
+
+			cr.Status.FailedTasks = failedTasks(records)
+			cr.Status.Artifacts = nil
+			for _, el := range artifacts {
+				st := runsv1alpha1.ArtifactStatus{Name: helpers.String(el.Name)}
+				if el.Resource != nil {
+					st.Type = el.Resource.Type
+					st.DownloadUrl = el.Resource.DownloadUrl
+				}
+				cr.Status.Artifacts = append(cr.Status.Artifacts, st)
+			}
+		}
+
+		cr.Status.Stages = nil
+		for _, el := range runs.Stages(records) {
+			cr.Status.Stages = append(cr.Status.Stages, runsv1alpha1.StageStatus{
+				Name:   helpers.String(el.Name),
+				State:  el.State,
+				Result: el.Result,
+			})
+		}
+	}
+
+	cr.Status.Id = helpers.IntPtr(*run.Id)
+	cr.Status.PipelineId = helpers.IntPtr(pipelineId)
+	cr.Status.State = run.State
+	cr.Status.Result = run.Result
+	cr.Status.CreatedDate = asTime(run.CreatedDate)
+	cr.Status.FinishedDate = asTime(run.FinishedDate)
+	cr.Status.Url = run.Url
+
+	cond := completedCondition(run)
+	if prev := cr.GetCondition(runsv1alpha1.TypeCompleted); prev.Reason != cond.Reason {
+		e.recordTransition(cr, cond)
+	}
+	cr.SetConditions(cond)
+
+	switch cond.Reason {
+	case runsv1alpha1.ReasonInProgress:
+		cr.SetConditions(rtv1.Creating())
+	case runsv1alpha1.ReasonSucceeded:
+		cr.SetConditions(rtv1.Available())
+	default:
+		cr.SetConditions(rtv1.Unavailable())
+	}
+
+	return nil
+}
+
+func (e *external) recordTransition(cr *runsv1alpha1.Run, cond rtv1.Condition) {
+	switch cond.Reason {
+	case runsv1alpha1.ReasonSucceeded:
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "PipelineRunSucceeded",
+			"Run '%s' succeeded", helpers.String(cr.Status.Url))
+	case runsv1alpha1.ReasonFailed:
+		e.rec.Eventf(cr, corev1.EventTypeWarning, "PipelineRunFailed", cond.Message)
+	case runsv1alpha1.ReasonCanceled:
+		e.rec.Eventf(cr, corev1.EventTypeWarning, "PipelineRunCanceled", cond.Message)
+	}
+}
+
+// defaultTailLines is the number of log lines written for each failed task.
+const defaultTailLines = 50
+
+// publishFailureLogs writes the tail of the logs of the failed tasks to
+// the referenced ConfigMap, if any.
+func (e *external) publishFailureLogs(ctx context.Context, cr *runsv1alpha1.Run, prj *projectsv1alpha1.TeamProject, runId int, records []runs.TimelineRecord) error {
+	spec := cr.Spec.FailureLogs
+	if spec == nil || spec.ConfigMapRef == nil {
+		return nil
+	}
+
+	tailLines := defaultTailLines
+	if spec.TailLines != nil {
+		tailLines = *spec.TailLines
+	}
+
+	logs := map[string]string{}
+	for _, el := range records {
+		if !isFailedTask(el) || el.Log == nil || el.Log.Id == nil {
+			continue
+		}
+		lines, err := runs.GetLog(ctx, e.azCli, runs.GetLogOptions{
+			Organization: prj.Spec.Organization,
+			Project:      prj.Status.Id,
+			RunId:        runId,
+			LogId:        *el.Log.Id,
+		})
+		if err != nil {
+			if azuredevops.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if len(lines) > tailLines {
+			lines = lines[len(lines)-tailLines:]
+		}
+		logs[logKey(helpers.String(el.Name))] = strings.Join(lines, "\n") + "\n"
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.ConfigMapRef.Name,
+			Namespace: spec.ConfigMapRef.Namespace,
+		},
+	}
+	res, err := controllerutil.CreateOrUpdate(ctx, e.kube, cm, func() error {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		for key, val := range logs {
+			cm.Data[key] = val
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "cannot write %s configmap", cm.Name)
+	}
+
+	if res != controllerutil.OperationResultNone {
+		e.rec.Eventf(cr, corev1.EventTypeNormal, "FailureLogsPublished",
+			"Logs of the failed tasks written to configmap '%s'", cm.Name)
+	}
+	return nil
+}
+
+// failedTasks returns the failed tasks of the timeline with the name of
+// their stage and their error messages.
+func failedTasks(records []runs.TimelineRecord) []runsv1alpha1.FailedTaskStatus {
+	byId := map[string]runs.TimelineRecord{}
+	for _, el := range records {
+		byId[helpers.String(el.Id)] = el
+	}
+
+	var res []runsv1alpha1.FailedTaskStatus
+	for _, el := range records {
+		if !isFailedTask(el) {
+			continue
+		}
+		st := runsv1alpha1.FailedTaskStatus{Name: helpers.String(el.Name)}
+		// Tasks belong to a job which in turn belongs to a stage.
+		for parent, ok := byId[helpers.String(el.ParentId)]; ok; parent, ok = byId[helpers.String(parent.ParentId)] {
+			if strings.EqualFold(helpers.String(parent.Type), "Stage") {
+				st.Stage = parent.Name
+				break
+			}
+		}
+		for _, issue := range el.Issues {
+			if strings.EqualFold(helpers.String(issue.Type), "error") {
+				st.Errors = append(st.Errors, helpers.String(issue.Message))
+			}
+		}
+		res = append(res, st)
+	}
+	return res
+}
+
+func isFailedTask(el runs.TimelineRecord) bool {
+	return strings.EqualFold(helpers.String(el.Type), "Task") &&
+		strings.EqualFold(helpers.String(el.Result), runResultFailed)
+}
+
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
+
+// logKey returns a valid ConfigMap key for the log of the task.
+func logKey(task string) string {
+	return invalidKeyChars.ReplaceAllString(task, "-") + ".log"
+}
+
+// completedCondition returns the Completed condition of the run.
+func completedCondition(run *runs.RunInfo) rtv1.Condition {
+	if !isCompleted(run) {
+		return runsv1alpha1.InProgress()
+	}
+
+	switch result := helpers.String(run.Result); result {
+	case runResultSucceeded:
+		return runsv1alpha1.Succeeded()
+	case runResultCanceled:
+		return runsv1alpha1.Canceled(fmt.Sprintf("Run '%s' canceled", helpers.String(run.Url)))
+	default:
+		return runsv1alpha1.Failed(fmt.Sprintf("Run '%s' completed with result '%s'", helpers.String(run.Url), result))
+	}
+}
+
+func isCompleted(run *runs.RunInfo) bool {
+	return strings.EqualFold(helpers.String(run.State), runStateCompleted)
+}
+
+// isTimedOut returns true if the run is still in progress after the timeout.
+func isTimedOut(cr *runsv1alpha1.Run, run *runs.RunInfo) bool {
+	if cr.Spec.Timeout == nil || run.CreatedDate == nil || isCompleted(run) ||
+		strings.EqualFold(helpers.String(run.State), runStateCanceling) {
+		return false
+	}
+	return time.Since(run.CreatedDate.Time) > cr.Spec.Timeout.Duration
+}
+
+func asTime(t *azuredevops.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	return &metav1.Time{Time: t.Time}
+}
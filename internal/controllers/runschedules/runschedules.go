@@ -0,0 +1,302 @@
+package runschedules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+	runschedules "github.com/krateoplatformops/azuredevops-provider/apis/runschedules/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/controller"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+	"github.com/krateoplatformops/provider-runtime/pkg/ratelimiter"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// LabelRunSchedule is the name of the RunSchedule that created the Run.
+	LabelRunSchedule = "azuredevops.krateo.io/run-schedule"
+	// AnnotationScheduledAt is the scheduled time of the Run.
+	AnnotationScheduledAt = "azuredevops.krateo.io/scheduled-at"
+
+	DefaultSuccessfulRunsHistoryLimit = 3
+	DefaultFailedRunsHistoryLimit     = 1
+)
+
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "schedule/" + strings.ToLower(runschedules.RunScheduleGroupKind)
+
+	log := o.Logger.WithValues("controller", name)
+
+	r := &Reconciler{
+		kube: mgr.GetClient(),
+		log:  log,
+		rec:  mgr.GetEventRecorderFor(name),
+		now:  time.Now,
+	}
+
+	// The schedules are driven by RequeueAfter and by the
+	// changes of the child Runs.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&runschedules.RunSchedule{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&runsv1alpha1.Run{}).
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(runschedules.RunScheduleKind, r), o.GlobalRateLimiter))
+}
+
+// Reconciler creates the child Runs of the RunSchedules on their
+// cron schedule, similarly to the Kubernetes CronJobs.
+type Reconciler struct {
+	kube client.Client
+	log  logging.Logger
+	rec  record.EventRecorder
+	now  func() time.Time
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	rs := &runschedules.RunSchedule{}
+	if err := r.kube.Get(ctx, req.NamespacedName, rs); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	// The child Runs are deleted by the garbage collector.
+	if rs.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	log := r.log.WithValues("name", rs.Name)
+
+	children, err := r.children(ctx, rs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.pruneHistory(ctx, children.successful, *helpers.Int32OrDefault(rs.Spec.SuccessfulRunsHistoryLimit, DefaultSuccessfulRunsHistoryLimit)); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.pruneHistory(ctx, children.failed, *helpers.Int32OrDefault(rs.Spec.FailedRunsHistoryLimit, DefaultFailedRunsHistoryLimit)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	rs.Status.Active = nil
+	for _, el := range children.active {
+		rs.Status.Active = append(rs.Status.Active, el.Name)
+	}
+	if children.lastSuccessfulTime != nil {
+		rs.Status.LastSuccessfulTime = children.lastSuccessfulTime
+	}
+
+	sched, loc, err := parseSchedule(rs)
+	if err != nil {
+		rs.Status.NextScheduleTime = nil
+		if prev := rs.GetCondition(rtv1.TypeReady); prev.Reason != runschedules.ReasonInvalidSchedule {
+			r.rec.Eventf(rs, corev1.EventTypeWarning, "InvalidSchedule", err.Error())
+		}
+		rs.SetConditions(runschedules.InvalidSchedule(err.Error()))
+		// Nothing to do until the spec is fixed.
+		return reconcile.Result{}, r.kube.Status().Update(ctx, rs)
+	}
+
+	if helpers.Bool(rs.Spec.Suspend) {
+		rs.Status.NextScheduleTime = nil
+		rs.SetConditions(runschedules.Suspended())
+		return reconcile.Result{}, r.kube.Status().Update(ctx, rs)
+	}
+
+	now := r.now().In(loc)
+	scheduled, next := missedSchedule(sched, r.earliest(rs, now), now)
+	if !scheduled.IsZero() {
+		if err := r.start(ctx, log, rs, scheduled, children.active); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	rs.Status.NextScheduleTime = nil
+	if !next.IsZero() {
+		rs.Status.NextScheduleTime = &metav1.Time{Time: next}
+	}
+	rs.SetConditions(runschedules.Scheduled())
+	if err := r.kube.Status().Update(ctx, rs); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if next.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+}
+
+// start creates the Run scheduled at the specified time honoring the
+// concurrency policy.
+func (r *Reconciler) start(ctx context.Context, log logging.Logger, rs *runschedules.RunSchedule, scheduled time.Time, active []*runsv1alpha1.Run) error {
+	if len(active) > 0 {
+		switch rs.Spec.ConcurrencyPolicy {
+		case runschedules.ForbidConcurrent:
+			// The run starts as soon as the active ones complete,
+			// unless the starting deadline expires or the next schedule comes first.
+			log.Debug("Run skipped: previous run in progress", "scheduled", scheduled)
+			return nil
+		case runschedules.ReplaceConcurrent:
+			for _, el := range active {
+				// The Run cancels the pipeline run in progress when deleted.
+				if err := r.kube.Delete(ctx, el, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					return errors.Wrapf(err, "cannot delete run %s", el.Name)
+				}
+				r.rec.Eventf(rs, corev1.EventTypeNormal, "RunReplaced", "Deleted active run '%s'", el.Name)
+			}
+			rs.Status.Active = nil
+		}
+	}
+
+	run := newRun(rs, scheduled)
+	if err := controllerutil.SetControllerReference(rs, run, r.kube.Scheme()); err != nil {
+		return err
+	}
+	if err := r.kube.Create(ctx, run); err != nil {
+		// The name is derived from the scheduled time: the run was
+		// created by a reconciliation that failed to update the status.
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "cannot create run %s", run.Name)
+		}
+	} else {
+		log.Debug("Run created", "run", run.Name, "scheduled", scheduled)
+		r.rec.Eventf(rs, corev1.EventTypeNormal, "RunCreated", "Created run '%s'", run.Name)
+	}
+
+	rs.Status.Active = append(rs.Status.Active, run.Name)
+	rs.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+	return nil
+}
+
+func newRun(rs *runschedules.RunSchedule, scheduled time.Time) *runsv1alpha1.Run {
+	return &runsv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", rs.Name, scheduled.Unix()/60),
+			Labels:      map[string]string{LabelRunSchedule: rs.Name},
+			Annotations: map[string]string{AnnotationScheduledAt: scheduled.UTC().Format(time.RFC3339)},
+		},
+		Spec: runsv1alpha1.RunSpec{
+			PipelineRef:        rs.Spec.PipelineRef.DeepCopy(),
+			ConnectorConfigRef: rs.Spec.ConnectorConfigRef.DeepCopy(),
+			RunParameters:      rs.Spec.RunParameters.DeepCopy(),
+			Timeout:            rs.Spec.Timeout.DeepCopy(),
+		},
+	}
+}
+
+type children struct {
+	active, successful, failed []*runsv1alpha1.Run
+	lastSuccessfulTime         *metav1.Time
+}
+
+// children returns the Runs of the schedule grouped by their outcome.
+func (r *Reconciler) children(ctx context.Context, rs *runschedules.RunSchedule) (*children, error) {
+	all := &runsv1alpha1.RunList{}
+	if err := r.kube.List(ctx, all, client.MatchingLabels{LabelRunSchedule: rs.Name}); err != nil {
+		return nil, err
+	}
+
+	res := &children{}
+	for i := range all.Items {
+		run := &all.Items[i]
+		if !metav1.IsControlledBy(run, rs) || run.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		cond := run.GetCondition(runsv1alpha1.TypeCompleted)
+		switch {
+		case cond.Status != metav1.ConditionTrue:
+			res.active = append(res.active, run)
+		case cond.Reason == runsv1alpha1.ReasonSucceeded:
+			res.successful = append(res.successful, run)
+			if t := finishedTime(run); res.lastSuccessfulTime == nil || res.lastSuccessfulTime.Before(&t) {
+				res.lastSuccessfulTime = &t
+			}
+		default:
+			res.failed = append(res.failed, run)
+		}
+	}
+	return res, nil
+}
+
+// pruneHistory deletes the oldest completed Runs beyond the limit.
+func (r *Reconciler) pruneHistory(ctx context.Context, runs []*runsv1alpha1.Run, limit int32) error {
+	if len(runs) <= int(limit) {
+		return nil
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		ti, tj := finishedTime(runs[i]), finishedTime(runs[j])
+		return ti.Before(&tj)
+	})
+	for _, el := range runs[:len(runs)-int(limit)] {
+		if err := r.kube.Delete(ctx, el, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, "cannot delete run %s", el.Name)
+		}
+	}
+	return nil
+}
+
+// earliest returns the time from which the missed schedules are computed:
+// the schedules older than the starting deadline are skipped.
+func (r *Reconciler) earliest(rs *runschedules.RunSchedule, now time.Time) time.Time {
+	res := rs.GetCreationTimestamp().Time
+	if rs.Status.LastScheduleTime != nil {
+		res = rs.Status.LastScheduleTime.Time
+	}
+	if sec := rs.Spec.StartingDeadlineSeconds; sec != nil {
+		if limit := now.Add(-time.Duration(*sec) * time.Second); limit.After(res) {
+			res = limit
+		}
+	}
+	return res.In(now.Location())
+}
+
+// missedSchedule returns the most recent schedule time after the earliest
+// time not later than now, if any, and the next schedule time.
+func missedSchedule(sched cron.Schedule, earliest, now time.Time) (missed, next time.Time) {
+	for t := sched.Next(earliest); !t.IsZero(); t = sched.Next(t) {
+		if t.After(now) {
+			return missed, t
+		}
+		missed = t
+	}
+	return missed, time.Time{}
+}
+
+func parseSchedule(rs *runschedules.RunSchedule) (cron.Schedule, *time.Location, error) {
+	sched, err := cron.ParseStandard(rs.Spec.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.UTC
+	if tz := helpers.String(rs.Spec.TimeZone); len(tz) > 0 {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid time zone '%s'", tz)
+		}
+	}
+	return sched, loc, nil
+}
+
+func finishedTime(run *runsv1alpha1.Run) metav1.Time {
+	if run.Status.FinishedDate != nil {
+		return *run.Status.FinishedDate
+	}
+	return run.GetCreationTimestamp()
+}
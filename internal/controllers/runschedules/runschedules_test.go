@@ -0,0 +1,303 @@
+package runschedules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/runs/v1alpha1"
+	runschedules "github.com/krateoplatformops/azuredevops-provider/apis/runschedules/v1alpha1"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+var created = time.Date(2024, time.March, 1, 10, 0, 30, 0, time.UTC)
+
+func newTestReconciler(t *testing.T, spec runschedules.RunScheduleSpec) (*Reconciler, client.Client, *time.Time) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := runsv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := runschedules.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	spec.PipelineRef = &rtv1.Reference{Name: "ci", Namespace: "default"}
+	spec.ConnectorConfigRef = &rtv1.Reference{Name: "connector", Namespace: "default"}
+	rs := &runschedules.RunSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			UID:               "uid-1",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: spec,
+	}
+
+	kube := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(rs).
+		WithStatusSubresource(rs, &runsv1alpha1.Run{}).
+		Build()
+
+	now := created
+	return &Reconciler{
+		kube: kube,
+		log:  logging.NewNopLogger(),
+		rec:  record.NewFakeRecorder(10),
+		now:  func() time.Time { return now },
+	}, kube, &now
+}
+
+func reconcileSchedule(t *testing.T, r *Reconciler) (reconcile.Result, *runschedules.RunSchedule) {
+	t.Helper()
+
+	ctx := context.TODO()
+	res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "nightly"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := &runschedules.RunSchedule{}
+	if err := r.kube.Get(ctx, types.NamespacedName{Name: "nightly"}, rs); err != nil {
+		t.Fatal(err)
+	}
+	return res, rs
+}
+
+func listRuns(t *testing.T, kube client.Client) []runsv1alpha1.Run {
+	t.Helper()
+
+	all := &runsv1alpha1.RunList{}
+	if err := kube.List(context.TODO(), all); err != nil {
+		t.Fatal(err)
+	}
+	return all.Items
+}
+
+func complete(t *testing.T, kube client.Client, name string, cond rtv1.Condition, finished time.Time) {
+	t.Helper()
+
+	run := &runsv1alpha1.Run{}
+	if err := kube.Get(context.TODO(), types.NamespacedName{Name: name}, run); err != nil {
+		t.Fatal(err)
+	}
+	run.SetConditions(cond)
+	run.Status.FinishedDate = &metav1.Time{Time: finished}
+	if err := kube.Status().Update(context.TODO(), run); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunScheduleCreatesRuns(t *testing.T) {
+	r, kube, now := newTestReconciler(t, runschedules.RunScheduleSpec{
+		Schedule: "*/5 * * * *",
+		RunParameters: &runsv1alpha1.RunPipelineParameters{
+			TemplateParameters: map[string]string{"env": "dev"},
+		},
+	})
+
+	res, rs := reconcileSchedule(t, r)
+	if len(listRuns(t, kube)) != 0 {
+		t.Fatal("expected no runs before the first schedule")
+	}
+	if want := time.Date(2024, time.March, 1, 10, 5, 0, 0, time.UTC); !rs.Status.NextScheduleTime.Time.Equal(want) ||
+		res.RequeueAfter != want.Sub(*now) {
+		t.Fatalf("unexpected next schedule: %v (requeue after %s)", rs.Status.NextScheduleTime, res.RequeueAfter)
+	}
+	if cond := rs.GetCondition(rtv1.TypeReady); cond.Reason != runschedules.ReasonScheduled {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+
+	// The controller was down: only the most recent missed run starts.
+	*now = time.Date(2024, time.March, 1, 10, 17, 0, 0, time.UTC)
+	_, rs = reconcileSchedule(t, r)
+	runs := listRuns(t, kube)
+	if len(runs) != 1 {
+		t.Fatalf("expected one run, got %d", len(runs))
+	}
+	run := runs[0]
+	if run.Name != "nightly-28488135" || run.Labels[LabelRunSchedule] != "nightly" ||
+		run.Annotations[AnnotationScheduledAt] != "2024-03-01T10:15:00Z" {
+		t.Fatalf("unexpected run: %+v", run.ObjectMeta)
+	}
+	if !metav1.IsControlledBy(&run, rs) {
+		t.Fatal("expected run controlled by the schedule")
+	}
+	if run.Spec.PipelineRef.Name != "ci" || run.Spec.RunParameters.TemplateParameters["env"] != "dev" {
+		t.Fatalf("unexpected run spec: %+v", run.Spec)
+	}
+	if len(rs.Status.Active) != 1 || !rs.Status.LastScheduleTime.Time.Equal(time.Date(2024, time.March, 1, 10, 15, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected status: %+v", rs.Status)
+	}
+
+	// Allow: the runs overlap.
+	*now = time.Date(2024, time.March, 1, 10, 20, 0, 0, time.UTC)
+	reconcileSchedule(t, r)
+	if runs := listRuns(t, kube); len(runs) != 2 {
+		t.Fatalf("expected two runs, got %d", len(runs))
+	}
+}
+
+func TestRunScheduleConcurrencyPolicy(t *testing.T) {
+	r, kube, now := newTestReconciler(t, runschedules.RunScheduleSpec{
+		Schedule:          "@hourly",
+		ConcurrencyPolicy: runschedules.ForbidConcurrent,
+	})
+
+	*now = time.Date(2024, time.March, 1, 11, 0, 0, 0, time.UTC)
+	reconcileSchedule(t, r)
+	first := listRuns(t, kube)[0].Name
+
+	*now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	_, rs := reconcileSchedule(t, r)
+	if runs := listRuns(t, kube); len(runs) != 1 {
+		t.Fatalf("expected forbidden run, got %d runs", len(runs))
+	}
+	if len(rs.Status.Active) != 1 || rs.Status.Active[0] != first {
+		t.Fatalf("unexpected active runs: %v", rs.Status.Active)
+	}
+
+	// The skipped run starts once the previous one completes.
+	complete(t, kube, first, runsv1alpha1.Succeeded(), *now)
+	*now = now.Add(10 * time.Minute)
+	_, rs = reconcileSchedule(t, r)
+	if runs := listRuns(t, kube); len(runs) != 2 {
+		t.Fatalf("expected two runs, got %d", len(runs))
+	}
+	if rs.Status.LastSuccessfulTime == nil || len(rs.Status.Active) != 1 || rs.Status.Active[0] == first {
+		t.Fatalf("unexpected status: %+v", rs.Status)
+	}
+
+	rs.Spec.ConcurrencyPolicy = runschedules.ReplaceConcurrent
+	if err := kube.Update(context.TODO(), rs); err != nil {
+		t.Fatal(err)
+	}
+	*now = time.Date(2024, time.March, 1, 13, 0, 0, 0, time.UTC)
+	_, rs = reconcileSchedule(t, r)
+	runs := listRuns(t, kube)
+	if len(runs) != 2 {
+		t.Fatalf("expected replaced run, got %d runs", len(runs))
+	}
+	if len(rs.Status.Active) != 1 || rs.Status.Active[0] != "nightly-28488300" {
+		t.Fatalf("unexpected active runs: %v", rs.Status.Active)
+	}
+}
+
+func TestRunScheduleHistoryLimits(t *testing.T) {
+	r, kube, now := newTestReconciler(t, runschedules.RunScheduleSpec{
+		Schedule:                   "@hourly",
+		SuccessfulRunsHistoryLimit: ptr(int32(1)),
+		FailedRunsHistoryLimit:     ptr(int32(0)),
+	})
+
+	var names []string
+	for h := 11; h <= 13; h++ {
+		*now = time.Date(2024, time.March, 1, h, 0, 0, 0, time.UTC)
+		_, rs := reconcileSchedule(t, r)
+		names = append(names, rs.Status.Active[len(rs.Status.Active)-1])
+	}
+	complete(t, kube, names[0], runsv1alpha1.Succeeded(), *now)
+	complete(t, kube, names[1], runsv1alpha1.Succeeded(), now.Add(time.Minute))
+	complete(t, kube, names[2], runsv1alpha1.Failed("failed"), now.Add(2*time.Minute))
+
+	*now = now.Add(5 * time.Minute)
+	reconcileSchedule(t, r)
+	runs := listRuns(t, kube)
+	if len(runs) != 1 || runs[0].Name != names[1] {
+		t.Fatalf("expected only the last successful run, got %+v", runs)
+	}
+}
+
+func TestRunScheduleSuspendAndInvalid(t *testing.T) {
+	r, kube, now := newTestReconciler(t, runschedules.RunScheduleSpec{
+		Schedule: "@hourly",
+		Suspend:  helpers.BoolPtr(true),
+	})
+
+	*now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	res, rs := reconcileSchedule(t, r)
+	if len(listRuns(t, kube)) != 0 || res.RequeueAfter != 0 || rs.Status.NextScheduleTime != nil {
+		t.Fatal("expected no runs while suspended")
+	}
+	if cond := rs.GetCondition(rtv1.TypeReady); cond.Reason != runschedules.ReasonSuspended {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+
+	rs.Spec.Suspend = nil
+	rs.Spec.Schedule = "0 25 * * *"
+	if err := kube.Update(context.TODO(), rs); err != nil {
+		t.Fatal(err)
+	}
+	_, rs = reconcileSchedule(t, r)
+	if cond := rs.GetCondition(rtv1.TypeReady); cond.Reason != runschedules.ReasonInvalidSchedule {
+		t.Fatalf("unexpected ready condition: %+v", cond)
+	}
+}
+
+func TestRunScheduleStartingDeadline(t *testing.T) {
+	r, kube, now := newTestReconciler(t, runschedules.RunScheduleSpec{
+		Schedule:                "0 * * * *",
+		TimeZone:                helpers.StringPtr("Europe/Rome"),
+		StartingDeadlineSeconds: helpers.Int64Ptr(60),
+	})
+
+	*now = time.Date(2024, time.March, 1, 11, 5, 0, 0, time.UTC)
+	_, rs := reconcileSchedule(t, r)
+	if len(listRuns(t, kube)) != 0 {
+		t.Fatal("expected missed run beyond the starting deadline")
+	}
+	if want := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC); !rs.Status.NextScheduleTime.Time.Equal(want) {
+		t.Fatalf("unexpected next schedule: %v", rs.Status.NextScheduleTime)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2024, time.February, 27, 10, 30, 15, 0, time.UTC)
+
+	for _, tc := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, time.February, 27, 10, 45, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, time.February, 28, 9, 0, 0, 0, time.UTC)},
+		// Day of month or day of week when both are restricted,
+		// a star with a step too.
+		{"0 0 1 * 3", time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */10 * 1", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * *", time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		sched, _, err := parseSchedule(&runschedules.RunSchedule{
+			Spec: runschedules.RunScheduleSpec{Schedule: tc.spec},
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.spec, err)
+		}
+		if got := sched.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%s: expected %s, got %s", tc.spec, tc.want, got)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * foo *"} {
+		if _, _, err := parseSchedule(&runschedules.RunSchedule{
+			Spec: runschedules.RunScheduleSpec{Schedule: spec},
+		}); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
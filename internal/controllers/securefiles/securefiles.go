@@ -4,14 +4,22 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	pipelinepermissionsv1alpha2 "github.com/krateoplatformops/azuredevops-provider/apis/pipelinepermissions/v1alpha2"
 	securefilesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/securefiles/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/pipelinespermissions"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/securefiles"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/hashes"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -36,13 +44,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(securefilesv1alpha1.SecureFilesGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(securefilesv1alpha1.SecureFilesKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -51,7 +61,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&securefilesv1alpha1.SecureFiles{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(securefilesv1alpha1.SecureFilesKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -163,22 +173,219 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 
 	cr.Status.Id = &observed.ID
 
+	// Without a source the secure file is uploaded out of band; the source
+	// is not checked on deletion, it could have been deleted already.
+	upToDate := true
+	if cr.Spec.Source != nil && cr.GetDeletionTimestamp() == nil {
+		content, err := resolvers.ResolveSecureFileContent(ctx, e.kube, cr.Spec.Source)
+		if err != nil {
+			return reconciler.ExternalObservation{}, fmt.Errorf("cannot resolve secure file content: %w", err)
+		}
+		upToDate = contentHash(cr, content) == cr.Status.ContentHash
+	}
+
 	cr.SetConditions(rtv1.Available())
 
 	return reconciler.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceUpToDate: upToDate,
 	}, e.kube.Status().Update(ctx, cr)
 }
 
+func (e *external) Create(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*securefilesv1alpha1.SecureFiles)
+	if !ok {
+		return errors.New(errNotCR)
+	}
+
+	if cr.Spec.Source == nil {
+		e.log.Debug("No source specified, waiting for the secure file to be uploaded.", "name", cr.Spec.Name)
+		return nil
+	}
+
+	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
+	if err != nil {
+		return fmt.Errorf("cannot resolve project reference: %w", err)
+	}
+
+	content, err := resolvers.ResolveSecureFileContent(ctx, e.kube, cr.Spec.Source)
+	if err != nil {
+		return fmt.Errorf("cannot resolve secure file content: %w", err)
+	}
+
+	cr.SetConditions(rtv1.Creating())
+
+	return e.upload(ctx, cr, project.Spec.Organization, project.Status.Id, content)
+}
+
+// Update uploads the secure file again when its content changes: the
+// content of a secure file cannot be replaced, so the new content is
+// uploaded under a temporary name, then the old file is deleted and the
+// new one renamed. The pipeline authorizations of the old file are
+// applied to the new one.
 func (e *external) Update(ctx context.Context, mg resource.Managed) error {
-	return nil
+	cr, ok := mg.(*securefilesv1alpha1.SecureFiles)
+	if !ok {
+		return errors.New(errNotCR)
+	}
+
+	if cr.Spec.Source == nil {
+		return nil
+	}
+
+	project, err := resolvers.ResolveTeamProject(ctx, e.kube, cr.Spec.ProjectRef)
+	if err != nil {
+		return fmt.Errorf("cannot resolve project reference: %w", err)
+	}
+
+	content, err := resolvers.ResolveSecureFileContent(ctx, e.kube, cr.Spec.Source)
+	if err != nil {
+		return fmt.Errorf("cannot resolve secure file content: %w", err)
+	}
+
+	organization, projectId := project.Spec.Organization, project.Status.Id
+	oldId := helpers.String(cr.Status.Id)
+
+	perms, err := pipelinespermissions.Get(ctx, e.azCli, pipelinespermissions.GetOptions{
+		Organization: organization,
+		Project:      projectId,
+		ResourceType: string(pipelinepermissionsv1alpha2.SecureFiles),
+		ResourceId:   oldId,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get secure file pipeline permissions: %w", err)
+	}
+
+	// A temporary file left by a failed update is replaced.
+	tmpName := cr.Spec.Name + ".tmp"
+	tmp, err := securefiles.Find(ctx, e.azCli, securefiles.FindOptions{
+		ListOptions: securefiles.ListOptions{
+			Organization: organization,
+			Project:      projectId,
+		},
+		SecureFileName: tmpName,
+	})
+	if err != nil && !azuredevops.IsNotFound(err) {
+		return fmt.Errorf("cannot find temporary secure file: %w", err)
+	}
+	if tmp != nil {
+		err = securefiles.Delete(ctx, e.azCli, securefiles.DeleteOptions{
+			Organization: organization,
+			Project:      projectId,
+			SecureFileId: tmp.ID,
+		})
+		if err != nil && !azuredevops.IsNotFound(err) {
+			return fmt.Errorf("cannot delete temporary secure file: %w", err)
+		}
+	}
+
+	tmp, err = securefiles.Upload(ctx, e.azCli, securefiles.UploadOptions{
+		Organization: organization,
+		Project:      projectId,
+		Name:         tmpName,
+		Content:      content,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload secure file: %w", err)
+	}
+
+	err = securefiles.Delete(ctx, e.azCli, securefiles.DeleteOptions{
+		Organization: organization,
+		Project:      projectId,
+		SecureFileId: oldId,
+	})
+	if err != nil && !azuredevops.IsNotFound(err) {
+		return fmt.Errorf("cannot delete secure file: %w", err)
+	}
+
+	res, err := securefiles.Update(ctx, e.azCli, securefiles.UpdateOptions{
+		Organization: organization,
+		Project:      projectId,
+		SecureFileId: tmp.ID,
+		Name:         cr.Spec.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot rename secure file: %w", err)
+	}
+
+	cr.Status.Id = helpers.StringPtr(res.ID)
+	cr.Status.ContentHash = contentHash(cr, content)
+
+	e.log.Debug("Secure file uploaded", "id", res.ID, "name", res.Name)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "SecureFileUploaded",
+		"Secure file '%s' uploaded", res.Name)
+
+	if err := e.kube.Status().Update(ctx, cr); err != nil {
+		return err
+	}
+
+	return e.restorePipelinePermissions(ctx, cr, organization, projectId, perms)
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) error {
+// restorePipelinePermissions authorizes the secure file for the pipelines
+// that were authorized to use the file it replaced.
+func (e *external) restorePipelinePermissions(ctx context.Context, cr *securefilesv1alpha1.SecureFiles, organization, projectId string, perms *pipelinespermissions.ResourcePipelinePermissions) error {
+	upd := &pipelinespermissions.ResourcePipelinePermissions{
+		AllPipelines: &pipelinespermissions.Permission{
+			Authorized: perms.AllPipelines != nil && perms.AllPipelines.Authorized,
+		},
+		Resource: &azuredevops.Resource{
+			Id:   cr.Status.Id,
+			Type: helpers.StringPtr(string(pipelinepermissionsv1alpha2.SecureFiles)),
+		},
+	}
+	for _, el := range perms.Pipelines {
+		if el.Authorized {
+			upd.Pipelines = append(upd.Pipelines, pipelinespermissions.PipelinePermission{
+				Authorized: true,
+				Id:         el.Id,
+			})
+		}
+	}
+	if !upd.AllPipelines.Authorized && len(upd.Pipelines) == 0 {
+		return nil
+	}
+
+	_, err := pipelinespermissions.Update(ctx, e.azCli, pipelinespermissions.UpdateOptions{
+		Organization:          organization,
+		Project:               projectId,
+		ResourceType:          string(pipelinepermissionsv1alpha2.SecureFiles),
+		ResourceId:            helpers.String(cr.Status.Id),
+		ResourceAuthorization: upd,
+	})
+	if err != nil {
+		e.rec.Eventf(cr, corev1.EventTypeWarning, "PipelinePermissionsReset",
+			"Pipeline authorizations of secure file '%s' were reset: %s", cr.Spec.Name, err.Error())
+		return fmt.Errorf("cannot restore secure file pipeline permissions: %w", err)
+	}
+
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "PipelinePermissionsRestored",
+		"Pipeline authorizations of secure file '%s' restored", cr.Spec.Name)
+
 	return nil
 }
 
+func (e *external) upload(ctx context.Context, cr *securefilesv1alpha1.SecureFiles, organization, projectId string, content []byte) error {
+	res, err := securefiles.Upload(ctx, e.azCli, securefiles.UploadOptions{
+		Organization: organization,
+		Project:      projectId,
+		Name:         cr.Spec.Name,
+		Content:      content,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload secure file: %w", err)
+	}
+
+	cr.Status.Id = helpers.StringPtr(res.ID)
+	cr.Status.ContentHash = contentHash(cr, content)
+
+	e.log.Debug("Secure file uploaded", "id", res.ID, "name", res.Name)
+	e.rec.Eventf(cr, corev1.EventTypeNormal, "SecureFileUploaded",
+		"Secure file '%s' uploaded", res.Name)
+
+	return e.kube.Status().Update(ctx, cr)
+}
+
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*securefilesv1alpha1.SecureFiles)
 	if !ok {
@@ -211,3 +418,9 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	return e.kube.Status().Update(ctx, cr)
 }
+
+// contentHash returns the hash of the content keyed by the UID of the
+// secure file.
+func contentHash(cr *securefilesv1alpha1.SecureFiles, content []byte) string {
+	return hashes.Secret(cr.GetUID(), "content", string(content))
+}
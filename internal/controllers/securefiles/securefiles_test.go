@@ -0,0 +1,215 @@
+package securefiles
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	securefilesv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/securefiles/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/pipelinespermissions"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestSecureFilesLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	file := srv.AddSecureFile(fake.Organization, prj.Status.Id, "id_rsa", []byte("key"))
+	cr := &securefilesv1alpha1.SecureFiles{
+		ObjectMeta: metav1.ObjectMeta{Name: "id-rsa", Namespace: controllertest.Namespace},
+		Spec: securefilesv1alpha1.SecureFilesSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "id_rsa",
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+	if helpers.String(cr.Status.Id) != fake.String(file["id"]) {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	// Secure files are uploaded out of band: create and update are no-ops.
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := srv.SecureFile(fake.Organization, prj.Status.Id, "id_rsa"); f != nil {
+		t.Fatal("expected secure file to be deleted")
+	}
+
+	// The stale identifier is cleared.
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists || cr.Status.Id != nil {
+		t.Fatalf("unexpected observation: %+v (status: %+v)", obs, cr.Status)
+	}
+}
+
+func TestSecureFilesUploadFromSource(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "signing", Namespace: controllertest.Namespace},
+		Data:       map[string][]byte{"cert.p12": []byte("v1")},
+	}
+	cr := &securefilesv1alpha1.SecureFiles{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert", Namespace: controllertest.Namespace},
+		Spec: securefilesv1alpha1.SecureFilesSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "cert.p12",
+			Source: &securefilesv1alpha1.SecureFileSource{
+				SecretKeyRef: &rtv1.SecretKeySelector{
+					Reference: rtv1.Reference{Name: sec.Name, Namespace: sec.Namespace},
+					Key:       "cert.p12",
+				},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, sec, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected secure file not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	file, content := srv.SecureFile(fake.Organization, prj.Status.Id, "cert.p12")
+	if file == nil || string(content) != "v1" {
+		t.Fatalf("unexpected uploaded file: %v (content: %q)", file, content)
+	}
+	if helpers.String(cr.Status.Id) != fake.String(file["id"]) || len(cr.Status.ContentHash) == 0 {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+	// The status does not disclose the plain hash of the content.
+	if sum := sha256.Sum256([]byte("v1")); cr.Status.ContentHash == hex.EncodeToString(sum[:]) {
+		t.Fatal("expected content hash to be keyed")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// The source data changes.
+	sec.Data["cert.p12"] = []byte("v2")
+	if err := kube.Update(ctx, sec); err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected secure file to be outdated")
+	}
+
+	// A pipeline is authorized to use the file and a previous update
+	// left its temporary file behind.
+	prevId := helpers.String(cr.Status.Id)
+	_, err = pipelinespermissions.Update(ctx, srv.Client(), pipelinespermissions.UpdateOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		ResourceType: "securefile",
+		ResourceId:   prevId,
+		ResourceAuthorization: &pipelinespermissions.ResourcePipelinePermissions{
+			Pipelines: []pipelinespermissions.PipelinePermission{{Authorized: true, Id: 7}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpName := "cert.p12.tmp"
+	srv.AddSecureFile(fake.Organization, prj.Status.Id, tmpName, []byte("v1.5"))
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	file, content = srv.SecureFile(fake.Organization, prj.Status.Id, "cert.p12")
+	if file == nil || string(content) != "v2" {
+		t.Fatalf("unexpected uploaded file: %v (content: %q)", file, content)
+	}
+	if helpers.String(cr.Status.Id) == prevId || helpers.String(cr.Status.Id) != fake.String(file["id"]) {
+		t.Fatal("expected secure file to be uploaded again")
+	}
+	if f, _ := srv.SecureFile(fake.Organization, prj.Status.Id, tmpName); f != nil {
+		t.Fatalf("expected temporary secure file to be renamed, got: %v", f)
+	}
+	if f, _ := srv.SecureFile(fake.Organization, prj.Status.Id, prevId); f != nil {
+		t.Fatalf("expected previous secure file to be deleted, got: %v", f)
+	}
+
+	perms, err := pipelinespermissions.Get(ctx, srv.Client(), pipelinespermissions.GetOptions{
+		Organization: fake.Organization,
+		Project:      prj.Status.Id,
+		ResourceType: "securefile",
+		ResourceId:   helpers.String(cr.Status.Id),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms.Pipelines) != 1 || !perms.Pipelines[0].Authorized || perms.Pipelines[0].GetId() != "7" {
+		t.Fatalf("expected pipeline authorizations to be restored, got: %+v", perms.Pipelines)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+}
@@ -0,0 +1,108 @@
+package teams
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	groupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/groups/v1alpha1"
+	teamsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/teams/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestTeamLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	grp := &groupsv1alpha1.Groups{
+		ObjectMeta: metav1.ObjectMeta{Name: "readers", Namespace: controllertest.Namespace},
+		Status: groupsv1alpha1.GroupsStatus{
+			Descriptor: helpers.StringPtr(fake.String(srv.AddGroup(fake.Organization, "Readers")["descriptor"])),
+		},
+	}
+	cr := &teamsv1alpha1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs", Namespace: controllertest.Namespace},
+		Spec: teamsv1alpha1.TeamSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			ProjectRef:         controllertest.Ref(prj),
+			Name:               "Devs",
+			Description:        helpers.StringPtr("developers"),
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, grp, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected team not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Id == nil || cr.Status.Descriptor == nil {
+		t.Fatalf("unexpected status: %+v", cr.Status)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// Adding a group makes the team outdated until the membership is created.
+	cr.Spec.GroupRefs = []rtv1.Reference{*controllertest.Ref(grp)}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected team to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.IsMember(fake.Organization, *cr.Status.Descriptor, *grp.Status.Descriptor) {
+		t.Fatal("expected team to be a member of the group")
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected team to be up to date")
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected team to be deleted")
+	}
+}
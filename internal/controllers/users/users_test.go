@@ -0,0 +1,90 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	groupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/groups/v1alpha1"
+	usersv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/users/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestUsersLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	grp := &groupsv1alpha1.Groups{
+		ObjectMeta: metav1.ObjectMeta{Name: "readers", Namespace: controllertest.Namespace},
+		Status: groupsv1alpha1.GroupsStatus{
+			Descriptor: helpers.StringPtr(fake.String(srv.AddGroup(fake.Organization, "Readers")["descriptor"])),
+		},
+	}
+	cr := &usersv1alpha1.Users{
+		ObjectMeta: metav1.ObjectMeta{Name: "jdoe", Namespace: controllertest.Namespace},
+		Spec: usersv1alpha1.UsersSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Organization:       fake.Organization,
+			User: usersv1alpha1.User{
+				Name: helpers.StringPtr("jdoe@fake.onmicrosoft.com"),
+			},
+			GroupsRefs: []rtv1.Reference{*controllertest.Ref(grp)},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, grp, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected user not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.Descriptor == nil {
+		t.Fatal("expected user descriptor")
+	}
+	if !srv.IsMember(fake.Organization, *cr.Status.Descriptor, *grp.Status.Descriptor) {
+		t.Fatal("expected user to be a member of the group")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected user to be deleted")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -12,7 +13,12 @@ import (
 	variablegroupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
 	vgclient "github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/variablegroups"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/conditions"
+	valuehashes "github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/hashes"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/metrics"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/throttling"
 	"github.com/krateoplatformops/azuredevops-provider/internal/resolvers"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/event"
@@ -36,13 +42,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	recorder := mgr.GetEventRecorderFor(name)
 
-	r := reconciler.NewReconciler(mgr,
+	mapper := conditions.NewMapper()
+
+	r := reconciler.NewReconciler(mapper.Manager(mgr),
 		resource.ManagedKind(variablegroupsv1alpha1.VariableGroupsGroupVersionKind),
-		reconciler.WithExternalConnecter(&connector{
+		reconciler.WithExternalConnecter(mapper.NewExternalConnecter(metrics.NewExternalConnecter(variablegroupsv1alpha1.VariableGroupsKind, tracing.NewExternalConnecter(&connector{
 			kube:     mgr.GetClient(),
 			log:      log,
 			recorder: recorder,
-		}),
+		})))),
 		reconciler.WithPollInterval(o.PollInterval),
 		reconciler.WithLogger(log),
 		reconciler.WithRecorder(event.NewAPIRecorder(recorder)))
@@ -51,7 +59,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&variablegroupsv1alpha1.VariableGroups{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, tracing.NewReconciler(variablegroupsv1alpha1.VariableGroupsKind, throttling.NewReconciler(r)), o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -112,7 +120,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 			Project:         project.Status.Id,
 			VariableGroupId: intId,
 		})
-		if err != nil {
+		if err != nil && !azuredevops.IsNotFound(err) {
 			return reconciler.ExternalObservation{}, err
 		}
 	} else {
@@ -141,7 +149,24 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler
 		return reconciler.ExternalObservation{}, err
 	}
 
-	isSynced, err := isSynced(*cr, observed)
+	// The referenced secrets could have been deleted already.
+	if cr.GetDeletionTimestamp() != nil {
+		return reconciler.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+
+	variables, hashes, err := e.resolveVariables(ctx, cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+	providerData, err := e.providerData(ctx, cr)
+	if err != nil {
+		return reconciler.ExternalObservation{}, err
+	}
+
+	isSynced, err := isSynced(*cr, observed, variables, hashes, providerData)
 	if err != nil {
 		return reconciler.ExternalObservation{}, err
 	}
@@ -193,32 +218,37 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) error {
 		})
 		organization = project.Spec.Organization
 	}
-	variables := map[string]vgclient.Variable{}
-	for k, v := range cr.Spec.Variables {
-		variables[k] = vgclient.Variable{
-			IsReadOnly: v.IsReadOnly,
-			Value:      v.Value,
-			IsSecret:   v.IsSecret,
-		}
+	variables, hashes, err := e.resolveVariables(ctx, cr)
+	if err != nil {
+		return err
+	}
+	providerData, err := e.providerData(ctx, cr)
+	if err != nil {
+		return err
 	}
 	intId, err := strconv.Atoi(cr.Status.Id)
 	if err != nil {
 		return fmt.Errorf("failed to convert id to int: %w", err)
 	}
 
-	vgclient.Update(ctx, e.azCli, vgclient.UpdateOptions{
+	_, err = vgclient.Update(ctx, e.azCli, vgclient.UpdateOptions{
 		Organization:    organization,
 		Project:         projects[0].ProjectReference.Name,
 		VariableGroupId: intId,
 		VariableGroup: &vgclient.VariableGroupBody{
-			Type:                           helpers.String(cr.Spec.Type),
+			Type:                           groupType(cr),
 			Variables:                      variables,
 			Name:                           cr.Name,
 			Description:                    helpers.String(cr.Spec.Description),
 			VariableGroupProjectReferences: projects,
+			ProviderData:                   providerData,
 		},
 	})
+	if err != nil {
+		return fmt.Errorf("failed to update variable group: %w", err)
+	}
 
+	cr.Status.ValueHashes = hashes
 	return e.kube.Status().Update(ctx, cr)
 }
 
@@ -256,24 +286,25 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 		})
 		organization = project.Spec.Organization
 	}
-	variables := map[string]vgclient.Variable{}
-	for k, v := range cr.Spec.Variables {
-		variables[k] = vgclient.Variable{
-			IsReadOnly: v.IsReadOnly,
-			Value:      v.Value,
-			IsSecret:   v.IsSecret,
-		}
+	variables, hashes, err := e.resolveVariables(ctx, cr)
+	if err != nil {
+		return err
+	}
+	providerData, err := e.providerData(ctx, cr)
+	if err != nil {
+		return err
 	}
 
 	response, err := vgclient.Create(ctx, e.azCli, vgclient.CreateOptions{
 		Organization: organization,
 		Project:      projects[0].ProjectReference.Name,
 		VariableGroup: &vgclient.VariableGroupBody{
-			Type:                           helpers.String(cr.Spec.Type),
+			Type:                           groupType(cr),
 			Variables:                      variables,
 			Name:                           cr.Name,
 			Description:                    helpers.String(cr.Spec.Description),
 			VariableGroupProjectReferences: projects,
+			ProviderData:                   providerData,
 		},
 	})
 	if err != nil {
@@ -281,6 +312,7 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) error {
 	}
 
 	cr.Status.Id = fmt.Sprintf("%d", response.ID)
+	cr.Status.ValueHashes = hashes
 	cr.SetConditions(rtv1.Creating())
 	return e.kube.Status().Update(ctx, cr)
 }
@@ -320,11 +352,87 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	return nil
 }
 
-func isSynced(cr variablegroupsv1alpha1.VariableGroups, observed *vgclient.VariableGroupResponse) (bool, error) {
+// resolveVariables returns the variables of the group, reading the
+// referenced values, and the hashes of the secret and referenced ones.
+func (e *external) resolveVariables(ctx context.Context, cr *variablegroupsv1alpha1.VariableGroups) (map[string]vgclient.Variable, map[string]string, error) {
+	variables := map[string]vgclient.Variable{}
+	hashes := map[string]string{}
+	if kv := cr.Spec.KeyVault; kv != nil {
+		if len(cr.Spec.Variables) > 0 {
+			return nil, nil, errors.New("variables cannot be specified for Key Vault variable groups")
+		}
+		// The values of the linked secrets are read by Azure DevOps.
+		for _, name := range kv.Secrets {
+			variables[name] = vgclient.Variable{IsSecret: true, Enabled: true}
+		}
+		return variables, hashes, nil
+	}
 	for k, v := range cr.Spec.Variables {
-		if !observed.Variables[k].IsSecret &&
-			observed.Variables[k].Value != v.Value ||
-			observed.Variables[k].IsReadOnly != v.IsReadOnly {
+		val, err := resolvers.ResolveVariableValue(ctx, e.kube, v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve variable '%s': %w", k, err)
+		}
+		variables[k] = vgclient.Variable{
+			IsReadOnly: v.IsReadOnly,
+			Value:      val,
+			IsSecret:   v.IsSecret,
+		}
+		if v.IsSecret || v.ValueFrom != nil {
+			hashes[k] = valuehashes.Secret(cr.GetUID(), k, val)
+		}
+	}
+	return variables, hashes, nil
+}
+
+// providerData returns the Key Vault provider data of the group, if any.
+func (e *external) providerData(ctx context.Context, cr *variablegroupsv1alpha1.VariableGroups) (*vgclient.ProviderData, error) {
+	if cr.Spec.KeyVault == nil {
+		return nil, nil
+	}
+	endpoint, err := resolvers.ResolveEndpoint(ctx, e.kube, cr.Spec.KeyVault.EndpointRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve endpoint reference: %w", err)
+	}
+	if len(helpers.String(endpoint.Status.Id)) == 0 {
+		return nil, fmt.Errorf("endpoint '%s' has not been created yet", endpoint.Name)
+	}
+	return &vgclient.ProviderData{
+		ServiceEndpointId: helpers.String(endpoint.Status.Id),
+		Vault:             cr.Spec.KeyVault.Vault,
+	}, nil
+}
+
+func groupType(cr *variablegroupsv1alpha1.VariableGroups) string {
+	if cr.Spec.KeyVault != nil {
+		return vgclient.TypeAzureKeyVault
+	}
+	return helpers.String(cr.Spec.Type)
+}
+
+func isSynced(cr variablegroupsv1alpha1.VariableGroups, observed *vgclient.VariableGroupResponse, variables map[string]vgclient.Variable, hashes map[string]string, providerData *vgclient.ProviderData) (bool, error) {
+	if providerData != nil {
+		if observed.Type != vgclient.TypeAzureKeyVault || observed.ProviderData == nil ||
+			observed.ProviderData.Vault != providerData.Vault ||
+			!strings.EqualFold(observed.ProviderData.ServiceEndpointId, providerData.ServiceEndpointId) {
+			return false, nil
+		}
+		// The variables are the linked secrets: the unlisted ones are unlinked.
+		if len(observed.Variables) != len(variables) {
+			return false, nil
+		}
+	}
+	for k, v := range variables {
+		obs, ok := observed.Variables[k]
+		if !ok ||
+			!obs.IsSecret && obs.Value != v.Value ||
+			obs.IsReadOnly != v.IsReadOnly {
+			return false, nil
+		}
+	}
+	// Secret values are never returned: their changes are detected
+	// comparing the hashes of the values last sent.
+	for k, h := range hashes {
+		if cr.Status.ValueHashes[k] != h {
 			return false, nil
 		}
 	}
@@ -0,0 +1,321 @@
+package variablegroups
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	endpointsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
+	variablegroupsv1alpha1 "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
+	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops/fake"
+	"github.com/krateoplatformops/azuredevops-provider/internal/controllers/controllertest"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
+	"github.com/krateoplatformops/provider-runtime/pkg/logging"
+)
+
+func TestVariableGroupsLifecycle(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	cr := &variablegroupsv1alpha1.VariableGroups{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: controllertest.Namespace},
+		Spec: variablegroupsv1alpha1.VariableGroupsSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Name:               helpers.StringPtr("settings"),
+			Type:               helpers.StringPtr("Vsts"),
+			VariableGroupProjectReferences: []variablegroupsv1alpha1.VariableGroupProjectReference{
+				{Name: helpers.StringPtr("settings"), ProjectRef: controllertest.Ref(prj)},
+			},
+			Variables: map[string]variablegroupsv1alpha1.VariableValue{
+				"env":      {Value: "test"},
+				"password": {Value: "s3cr3t", IsSecret: true},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected variable group not to exist")
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	id, err := strconv.Atoi(cr.Status.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// The variables added out of band are left alone.
+	vars, _ := srv.VariableGroup(fake.Organization, id)["variables"].(map[string]any)
+	vars["added"] = map[string]any{"value": "by hand"}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatal("expected variable group with an added variable to be up to date")
+	}
+
+	cr.Spec.Variables["env"] = variablegroupsv1alpha1.VariableValue{Value: "prod"}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected variable group to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	vars, _ = srv.VariableGroup(fake.Organization, id)["variables"].(map[string]any)
+	if got := fake.String(vars["env"].(map[string]any)["value"]); got != "prod" {
+		t.Fatalf("unexpected variable value: %s", got)
+	}
+
+	if err := ext.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists {
+		t.Fatal("expected variable group to be deleted")
+	}
+}
+
+func TestVariableGroupsValueFromSecret(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: controllertest.Namespace},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("v1"),
+		},
+	}
+	secretKeyRef := func(key string) *variablegroupsv1alpha1.VariableValueSource {
+		return &variablegroupsv1alpha1.VariableValueSource{
+			SecretKeyRef: &rtv1.SecretKeySelector{
+				Reference: rtv1.Reference{Name: sec.Name, Namespace: sec.Namespace},
+				Key:       key,
+			},
+		}
+	}
+	cr := &variablegroupsv1alpha1.VariableGroups{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: controllertest.Namespace, UID: "0a1b2c3d"},
+		Spec: variablegroupsv1alpha1.VariableGroupsSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Name:               helpers.StringPtr("settings"),
+			Type:               helpers.StringPtr("Vsts"),
+			VariableGroupProjectReferences: []variablegroupsv1alpha1.VariableGroupProjectReference{
+				{Name: helpers.StringPtr("settings"), ProjectRef: controllertest.Ref(prj)},
+			},
+			Variables: map[string]variablegroupsv1alpha1.VariableValue{
+				"username": {ValueFrom: secretKeyRef("username")},
+				"password": {ValueFrom: secretKeyRef("password"), IsSecret: true},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, sec, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	id, err := strconv.Atoi(cr.Status.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars, _ := srv.VariableGroup(fake.Organization, id)["variables"].(map[string]any)
+	if got := fake.String(vars["username"].(map[string]any)["value"]); got != "admin" {
+		t.Fatalf("unexpected variable value: %s", got)
+	}
+	hash := cr.Status.ValueHashes["password"]
+	if len(hash) == 0 || len(cr.Status.ValueHashes) != 2 {
+		t.Fatalf("unexpected value hashes: %v", cr.Status.ValueHashes)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// The secret is rotated.
+	sec.Data["password"] = []byte("v2")
+	if err := kube.Update(ctx, sec); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected variable group to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := cr.Status.ValueHashes["password"]; got == hash {
+		t.Fatal("expected value hash to change")
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// A missing key is reported.
+	cr.Spec.Variables["token"] = variablegroupsv1alpha1.VariableValue{ValueFrom: secretKeyRef("token")}
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ext.Observe(ctx, cr); err == nil {
+		t.Fatal("expected missing secret key error")
+	}
+}
+
+func TestVariableGroupsKeyVault(t *testing.T) {
+	srv := fake.NewServer()
+	defer srv.Close()
+
+	prj := controllertest.TeamProject("demo", srv.AddProject(fake.Organization, "Demo"))
+	ep := &endpointsv1alpha1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: controllertest.Namespace},
+		Status: endpointsv1alpha1.EndpointStatus{
+			Id: helpers.StringPtr(fake.String(srv.AddServiceEndpoint(fake.Organization, "Demo", "azure", "azurerm")["id"])),
+		},
+	}
+	cr := &variablegroupsv1alpha1.VariableGroups{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: controllertest.Namespace},
+		Spec: variablegroupsv1alpha1.VariableGroupsSpec{
+			ConnectorConfigRef: controllertest.ConnectorConfigRef(),
+			Name:               helpers.StringPtr("vault"),
+			VariableGroupProjectReferences: []variablegroupsv1alpha1.VariableGroupProjectReference{
+				{Name: helpers.StringPtr("vault"), ProjectRef: controllertest.Ref(prj)},
+			},
+			KeyVault: &variablegroupsv1alpha1.KeyVaultProvider{
+				EndpointRef: controllertest.Ref(ep),
+				Vault:       "demo-kv",
+				Secrets:     []string{"db-password"},
+			},
+		},
+	}
+
+	kube := controllertest.NewKube(t, srv, prj, ep, cr)
+	c := &connector{kube: kube, log: logging.NewNopLogger(), recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.TODO()
+	ext, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ext.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	id, err := strconv.Atoi(cr.Status.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vg := srv.VariableGroup(fake.Organization, id)
+	data, _ := vg["providerData"].(map[string]any)
+	if fake.String(vg["type"]) != "AzureKeyVault" || fake.String(data["vault"]) != "demo-kv" ||
+		fake.String(data["serviceEndpointId"]) != helpers.String(ep.Status.Id) {
+		t.Fatalf("unexpected variable group: %v", vg)
+	}
+
+	obs, err := ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// A secret is linked.
+	cr.Spec.KeyVault.Secrets = append(cr.Spec.KeyVault.Secrets, "api-token")
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected variable group to be outdated")
+	}
+	if err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	vars, _ := srv.VariableGroup(fake.Organization, id)["variables"].(map[string]any)
+	if _, ok := vars["api-token"]; !ok || len(vars) != 2 {
+		t.Fatalf("unexpected linked secrets: %v", vars)
+	}
+
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Fatalf("unexpected observation: %+v", obs)
+	}
+
+	// The vault is changed.
+	cr.Spec.KeyVault.Vault = "other-kv"
+	if err := kube.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	obs, err = ext.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("expected variable group to be outdated")
+	}
+}
@@ -2,20 +2,49 @@ package resolvers
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
+	"net/url"
 
 	connectorconfigs "github.com/krateoplatformops/azuredevops-provider/apis/connectorconfigs/v1alpha1"
 	"github.com/krateoplatformops/azuredevops-provider/internal/clients/azuredevops"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// ConnectorNotReadyError is returned when the referenced ConnectorConfig
+// failed its last validation.
+type ConnectorNotReadyError struct {
+	Name    string
+	Reason  string
+	Message string
+}
+
+func (e *ConnectorNotReadyError) Error() string {
+	return fmt.Sprintf("connector config %s not ready (%s): %s", e.Name, e.Reason, e.Message)
+}
+
+// IsConnectorNotReady returns true if the error is a ConnectorNotReadyError.
+func IsConnectorNotReady(err error) bool {
+	var val *ConnectorNotReadyError
+	return errors.As(err, &val)
+}
+
 func ResolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.Reference) (azuredevops.ClientOptions, error) {
+	ctx, span := startSpan(ctx, "ResolveConnectorConfig", ref)
+	opts, err := resolveConnectorConfig(ctx, kube, ref)
+	tracing.End(span, err)
+	return opts, err
+}
+
+func resolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.Reference) (azuredevops.ClientOptions, error) {
 	opts := azuredevops.ClientOptions{}
 
 	cfg := connectorconfigs.ConnectorConfig{}
@@ -28,6 +57,23 @@ func ResolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.R
 		return opts, errors.Wrapf(err, "cannot get %s connector config", ref.Name)
 	}
 
+	// Short-circuit only when the connector has been validated
+	// (a missing status means that it has not been checked yet).
+	if cond := cfg.GetCondition(rtv1.TypeReady); cond.Status == metav1.ConditionFalse &&
+		cfg.Status.ObservedGeneration == cfg.Generation {
+		return opts, &ConnectorNotReadyError{Name: cfg.Name, Reason: string(cond.Reason), Message: cond.Message}
+	}
+
+	return ResolveConnectorConfigSpec(ctx, kube, &cfg)
+}
+
+// ResolveConnectorConfigSpec returns the client options described by
+// the ConnectorConfig spec, resolving the referenced secrets.
+func ResolveConnectorConfigSpec(ctx context.Context, kube client.Client, cfg *connectorconfigs.ConnectorConfig) (azuredevops.ClientOptions, error) {
+	opts := azuredevops.ClientOptions{}
+
+	var err error
+
 	apiUrl := cfg.Spec.ApiUrl
 	if apiUrl == "" {
 		return opts, fmt.Errorf("no apiUrl specified")
@@ -43,6 +89,45 @@ func ResolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.R
 
 	opts.ApiVersionConfig = cfg.Spec.APIVersionConfig
 
+	if err := resolveConnection(ctx, kube, cfg, &opts); err != nil {
+		return opts, err
+	}
+
+	if wi := cfg.Spec.WorkloadIdentity; wi != nil {
+		opts.TokenSource, err = azuredevops.WorkloadIdentityTokenSource(&azuredevops.WorkloadIdentityCredentials{
+			TenantID:      wi.TenantID,
+			ClientID:      wi.ClientID,
+			TokenFilePath: wi.TokenFilePath,
+			TokenURL:      wi.TokenURL,
+			Scope:         wi.Scope,
+		}, azuredevops.NewHTTPClient(opts))
+		if err != nil {
+			return opts, err
+		}
+		opts.Verbose = false
+
+		return opts, nil
+	}
+
+	if sp := cfg.Spec.ServicePrincipal; sp != nil {
+		creds, err := resolveServicePrincipal(ctx, kube, sp)
+		if err != nil {
+			return opts, err
+		}
+
+		opts.TokenSource, err = azuredevops.ServicePrincipalTokenSource(creds, azuredevops.NewHTTPClient(opts))
+		if err != nil {
+			return opts, err
+		}
+		opts.Verbose = false
+
+		return opts, nil
+	}
+
+	if cfg.Spec.Credentials == nil {
+		return opts, fmt.Errorf("no credentials, servicePrincipal or workloadIdentity specified")
+	}
+
 	csr := cfg.Spec.Credentials.SecretRef
 	if csr == nil {
 		return opts, fmt.Errorf("no credentials secret referenced")
@@ -51,7 +136,7 @@ func ResolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.R
 	sec := corev1.Secret{}
 	err = kube.Get(ctx, types.NamespacedName{Namespace: csr.Namespace, Name: csr.Name}, &sec)
 	if err != nil {
-		return opts, errors.Wrapf(err, "cannot get %s secret", ref.Name)
+		return opts, errors.Wrapf(err, "cannot get %s secret", csr.Name)
 	}
 
 	token, err := resource.GetSecret(ctx, kube, csr.DeepCopy())
@@ -64,3 +149,80 @@ func ResolveConnectorConfig(ctx context.Context, kube client.Client, ref *rtv1.R
 
 	return opts, nil
 }
+
+func resolveServicePrincipal(ctx context.Context, kube client.Client, sp *connectorconfigs.ServicePrincipal) (*azuredevops.ServicePrincipalCredentials, error) {
+	creds := &azuredevops.ServicePrincipalCredentials{
+		TenantID: sp.TenantID,
+		ClientID: sp.ClientID,
+		TokenURL: sp.TokenURL,
+		Scope:    sp.Scope,
+	}
+
+	switch {
+	case sp.ClientSecretRef != nil:
+		secret, err := resource.GetSecret(ctx, kube, sp.ClientSecretRef.DeepCopy())
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("empty key '%s' in %s client secret", sp.ClientSecretRef.Key, sp.ClientSecretRef.Name)
+		}
+		creds.ClientSecret = secret
+	case sp.ClientCertificateRef != nil:
+		cert, err := resource.GetSecret(ctx, kube, sp.ClientCertificateRef.DeepCopy())
+		if err != nil {
+			return nil, err
+		}
+		if len(cert) == 0 {
+			return nil, fmt.Errorf("empty key '%s' in %s client certificate secret", sp.ClientCertificateRef.Key, sp.ClientCertificateRef.Name)
+		}
+		creds.ClientCertificate = []byte(cert)
+	default:
+		return nil, fmt.Errorf("no servicePrincipal clientSecretRef or clientCertificateRef specified")
+	}
+
+	return creds, nil
+}
+
+// resolveConnection sets the path mode, TLS and proxy client options.
+func resolveConnection(ctx context.Context, kube client.Client, cfg *connectorconfigs.ConnectorConfig, opts *azuredevops.ClientOptions) error {
+	if cfg.Spec.PathMode == connectorconfigs.PathModeCollection {
+		opts.PathMode = azuredevops.CollectionPathMode
+		opts.VirtualDirectory = cfg.Spec.VirtualDirectory
+	}
+
+	if len(cfg.Spec.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(cfg.Spec.ProxyURL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid proxyUrl")
+		}
+		if len(proxyURL.Scheme) == 0 || len(proxyURL.Host) == 0 {
+			return fmt.Errorf("invalid proxyUrl '%s': scheme and host are required", cfg.Spec.ProxyURL)
+		}
+		opts.ProxyURL = proxyURL
+	}
+
+	tlsCfg := cfg.Spec.TLS
+	if tlsCfg == nil {
+		return nil
+	}
+	opts.InsecureSkipVerify = tlsCfg.InsecureSkipVerify
+
+	if ref := tlsCfg.CABundleRef; ref != nil {
+		bundle, err := resource.GetSecret(ctx, kube, ref.DeepCopy())
+		if err != nil {
+			return err
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(bundle)) {
+			return fmt.Errorf("no PEM certificates found in key '%s' of %s CA bundle secret", ref.Key, ref.Name)
+		}
+		opts.RootCAs = pool
+	}
+
+	return nil
+}
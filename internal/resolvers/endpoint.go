@@ -8,7 +8,6 @@ import (
 	endpoint "github.com/krateoplatformops/azuredevops-provider/apis/endpoints/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,7 +17,7 @@ func ResolveEndpoint(ctx context.Context, kube client.Client, ref *rtv1.Referenc
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveEndpoint", ref, res)
 	return res, err
 }
 
@@ -43,3 +42,37 @@ func FindEndpointRef(ctx context.Context, kube client.Client, id string) (*rtv1.
 	}
 	return nil, fmt.Errorf("no Endpoint referenced with id: %s", id)
 }
+
+// ResolveEndpointSecrets returns the secret authorization parameters by
+// name, reading the referenced secret keys in place of the plain values.
+func ResolveEndpointSecrets(ctx context.Context, kube client.Client, params *endpoint.EndpointAuthorizationParams) (map[string]string, error) {
+	res := map[string]string{}
+	if params == nil {
+		return res, nil
+	}
+
+	for _, el := range []struct {
+		name  string
+		value *string
+		ref   *rtv1.SecretKeySelector
+	}{
+		{"serviceprincipalKey", params.ServiceprincipalKey, params.ServiceprincipalKeyRef},
+		{"serviceAccountCertificate", params.ServiceAccountCertificate, params.ServiceAccountCertificateRef},
+		{"apitoken", params.Apitoken, params.ApitokenRef},
+	} {
+		if el.ref == nil {
+			if len(helpers.String(el.value)) > 0 {
+				res[el.name] = helpers.String(el.value)
+			}
+			continue
+		}
+
+		val, err := resolveSecretKey(ctx, kube, el.ref)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", el.name, err)
+		}
+		res[el.name] = string(val)
+	}
+
+	return res, nil
+}
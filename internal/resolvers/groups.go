@@ -8,8 +8,6 @@ import (
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"github.com/krateoplatformops/provider-runtime/pkg/helpers"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	"k8s.io/apimachinery/pkg/types"
 )
 
 func ResolveGroup(ctx context.Context, kube client.Client, ref *rtv1.Reference) (*groups.Groups, error) {
@@ -18,7 +16,7 @@ func ResolveGroup(ctx context.Context, kube client.Client, ref *rtv1.Reference)
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveGroup", ref, res)
 	return res, err
 }
 
@@ -7,8 +7,6 @@ import (
 	projects "github.com/krateoplatformops/azuredevops-provider/apis/projects/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	"k8s.io/apimachinery/pkg/types"
 )
 
 func ResolveTeamProject(ctx context.Context, kube client.Client, ref *rtv1.Reference) (*projects.TeamProject, error) {
@@ -17,7 +15,7 @@ func ResolveTeamProject(ctx context.Context, kube client.Client, ref *rtv1.Refer
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveTeamProject", ref, res)
 	return res, err
 }
 
@@ -6,7 +6,6 @@ import (
 
 	queue "github.com/krateoplatformops/azuredevops-provider/apis/queues/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,7 +15,7 @@ func ResolveQueue(ctx context.Context, kube client.Client, ref *rtv1.Reference)
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveQueue", ref, res)
 	return res, err
 }
 
@@ -0,0 +1,26 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// resolveSecretKey returns the value of the referenced secret key;
+// unlike resource.GetSecret, a missing key is an error.
+func resolveSecretKey(ctx context.Context, kube client.Client, ref *rtv1.SecretKeySelector) ([]byte, error) {
+	sec := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, sec); err != nil {
+		return nil, errors.Wrapf(err, "cannot get %s secret", ref.Name)
+	}
+	if val, ok := sec.Data[ref.Key]; ok {
+		return val, nil
+	}
+	return nil, fmt.Errorf("key '%s' not found in %s secret", ref.Key, ref.Name)
+}
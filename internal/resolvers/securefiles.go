@@ -6,8 +6,11 @@ import (
 
 	securefiles "github.com/krateoplatformops/azuredevops-provider/apis/securefiles/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func ResolveSecureFiles(ctx context.Context, kube client.Client, ref *rtv1.Reference) (*securefiles.SecureFiles, error) {
@@ -16,7 +19,7 @@ func ResolveSecureFiles(ctx context.Context, kube client.Client, ref *rtv1.Refer
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveSecureFiles", ref, res)
 	return res, err
 }
 
@@ -41,3 +44,30 @@ func FindSecureFilesRef(ctx context.Context, kube client.Client, id string) (*rt
 	}
 	return nil, err
 }
+
+// ResolveSecureFileContent returns the content of the secure file
+// held by the referenced Secret or ConfigMap key.
+func ResolveSecureFileContent(ctx context.Context, kube client.Client, src *securefiles.SecureFileSource) ([]byte, error) {
+	switch {
+	case src == nil:
+		return nil, fmt.Errorf("no secure file source specified")
+	case src.SecretKeyRef != nil && src.ConfigMapKeyRef != nil:
+		return nil, fmt.Errorf("only one of secretKeyRef and configMapKeyRef can be specified")
+	case src.SecretKeyRef != nil:
+		return resolveSecretKey(ctx, kube, src.SecretKeyRef)
+	case src.ConfigMapKeyRef != nil:
+		ref := src.ConfigMapKeyRef
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return nil, errors.Wrapf(err, "cannot get %s config map", ref.Name)
+		}
+		if val, ok := cm.BinaryData[ref.Key]; ok {
+			return val, nil
+		}
+		if val, ok := cm.Data[ref.Key]; ok {
+			return []byte(val), nil
+		}
+		return nil, fmt.Errorf("key '%s' not found in %s config map", ref.Key, ref.Name)
+	}
+	return nil, fmt.Errorf("one of secretKeyRef and configMapKeyRef must be specified")
+}
@@ -0,0 +1,33 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
+	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// startSpan starts the span of the named resolver lookup.
+func startSpan(ctx context.Context, name string, ref *rtv1.Reference) (context.Context, trace.Span) {
+	ctx, span := tracing.Start(ctx, "resolvers."+name)
+	if ref != nil {
+		span.SetAttributes(
+			tracing.AttrName.String(ref.Name),
+			tracing.AttrNamespace.String(ref.Namespace),
+		)
+	}
+	return ctx, span
+}
+
+// getReferenced fetches the referenced object tracing the lookup
+// as a child span of the named resolver.
+func getReferenced(ctx context.Context, kube client.Client, name string, ref *rtv1.Reference, obj client.Object) error {
+	ctx, span := startSpan(ctx, name, ref)
+	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj)
+	tracing.End(span, err)
+	return err
+}
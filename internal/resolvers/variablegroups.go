@@ -6,7 +6,6 @@ import (
 
 	variablegroups "github.com/krateoplatformops/azuredevops-provider/apis/variablegroups/v1alpha1"
 	rtv1 "github.com/krateoplatformops/provider-runtime/apis/common/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,7 +15,7 @@ func ResolveVariableGroups(ctx context.Context, kube client.Client, ref *rtv1.Re
 		return res, fmt.Errorf("no %s referenced", res.Kind)
 	}
 
-	err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, res)
+	err := getReferenced(ctx, kube, "ResolveVariableGroups", ref, res)
 	return res, err
 }
 
@@ -41,3 +40,17 @@ func FindVariableGroupsRef(ctx context.Context, kube client.Client, id string) (
 	}
 	return nil, err
 }
+
+// ResolveVariableValue returns the value of the variable,
+// reading it from the referenced secret key if specified.
+func ResolveVariableValue(ctx context.Context, kube client.Client, val variablegroups.VariableValue) (string, error) {
+	if val.ValueFrom == nil {
+		return val.Value, nil
+	}
+	if val.ValueFrom.SecretKeyRef == nil {
+		return "", fmt.Errorf("no variable value source specified")
+	}
+
+	res, err := resolveSecretKey(ctx, kube, val.ValueFrom.SecretKeyRef)
+	return string(res), err
+}
@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler returns a reconcile.Reconciler starting a span
+// for each reconcile of the specified kind.
+func NewReconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracingReconciler{kind: kind, next: r}
+}
+
+type tracingReconciler struct {
+	kind string
+	next reconcile.Reconciler
+}
+
+func (r *tracingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Start(ctx, "Reconcile "+r.kind,
+		AttrKind.String(r.kind),
+		AttrName.String(req.Name),
+		AttrNamespace.String(req.Namespace),
+	)
+
+	res, err := r.next.Reconcile(ctx, req)
+
+	span.SetAttributes(
+		attribute.Bool("krateo.reconcile.requeue", res.Requeue),
+		attribute.String("krateo.reconcile.requeue_after", res.RequeueAfter.String()),
+	)
+	End(span, err)
+
+	return res, err
+}
+
+// NewExternalConnecter returns an ExternalConnecter starting a span for
+// each operation of the supplied one; the external name of the managed
+// resource is added to the reconcile span.
+func NewExternalConnecter(c reconciler.ExternalConnecter) reconciler.ExternalConnecter {
+	return &connecter{next: c}
+}
+
+type connecter struct {
+	next reconciler.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (reconciler.ExternalClient, error) {
+	if name := meta.GetExternalName(mg); len(name) > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(AttrExternalName.String(name))
+	}
+
+	ctx, span := Start(ctx, "Connect")
+	ext, err := c.next.Connect(ctx, mg)
+	End(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{next: ext}, nil
+}
+
+type external struct {
+	next reconciler.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (reconciler.ExternalObservation, error) {
+	ctx, span := Start(ctx, "Observe")
+	obs, err := e.next.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("krateo.resource.exists", obs.ResourceExists),
+		attribute.Bool("krateo.resource.up_to_date", obs.ResourceUpToDate),
+	)
+	End(span, err)
+	return obs, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) error {
+	ctx, span := Start(ctx, "Create")
+	err := e.next.Create(ctx, mg)
+	End(span, err)
+	return err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) error {
+	ctx, span := Start(ctx, "Update")
+	err := e.next.Update(ctx, mg)
+	End(span, err)
+	return err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := Start(ctx, "Delete")
+	err := e.next.Delete(ctx, mg)
+	End(span, err)
+	return err
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krateoplatformops/provider-runtime/pkg/meta"
+	"github.com/krateoplatformops/provider-runtime/pkg/reconciler"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource"
+	"github.com/krateoplatformops/provider-runtime/pkg/resource/fake"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestProvider(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return sr
+}
+
+func TestReconcileSpans(t *testing.T) {
+	sr := newTestProvider(t)
+
+	errBoom := errors.New("boom")
+	ext := reconciler.ExternalClientFns{
+		ObserveFn: func(context.Context, resource.Managed) (reconciler.ExternalObservation, error) {
+			return reconciler.ExternalObservation{ResourceExists: true}, nil
+		},
+		UpdateFn: func(context.Context, resource.Managed) error { return errBoom },
+	}
+	c := NewExternalConnecter(reconciler.ExternalConnectorFn(
+		func(context.Context, resource.Managed) (reconciler.ExternalClient, error) {
+			return ext, nil
+		}))
+
+	mg := &fake.Managed{}
+	meta.SetExternalName(mg, "ext-name")
+
+	r := NewReconciler("Test", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		cli, err := c.Connect(ctx, mg)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if _, err := cli.Observe(ctx, mg); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, cli.Update(ctx, mg)
+	}))
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "demo"},
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected the update error to be returned, got: %v", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got: %d", len(spans))
+	}
+
+	root := spans[3]
+	if root.Name() != "Reconcile Test" {
+		t.Fatalf("expected the reconcile span to end last, got: %s", root.Name())
+	}
+	if root.Status().Code != codes.Error {
+		t.Fatalf("expected the reconcile span status to be an error")
+	}
+	attrs := map[string]string{}
+	for _, kv := range root.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	want := map[string]string{
+		string(AttrKind):         "Test",
+		string(AttrName):         "demo",
+		string(AttrNamespace):    "default",
+		string(AttrExternalName): "ext-name",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, attrs[k])
+		}
+	}
+
+	for i, name := range []string{"Connect", "Observe", "Update"} {
+		if spans[i].Name() != name {
+			t.Errorf("expected span %q, got: %q", name, spans[i].Name())
+		}
+		if spans[i].Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("%s: expected to be a child of the reconcile span", name)
+		}
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Errorf("expected the update span status to be an error")
+	}
+}
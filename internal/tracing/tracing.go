@@ -0,0 +1,101 @@
+// Package tracing configures the OpenTelemetry tracing of the reconcile
+// loops, the resolvers lookups and the Azure DevOps REST API calls.
+//
+// Tracing is disabled until Setup is called: the spans are then started
+// with the no-op global tracer provider and cost nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer of this provider.
+	TracerName = "github.com/krateoplatformops/azuredevops-provider"
+)
+
+// Span attributes of the managed resources.
+const (
+	AttrKind         = attribute.Key("krateo.resource.kind")
+	AttrName         = attribute.Key("krateo.resource.name")
+	AttrNamespace    = attribute.Key("krateo.resource.namespace")
+	AttrExternalName = attribute.Key("krateo.resource.external_name")
+)
+
+type Options struct {
+	// ServiceName is reported as the 'service.name' resource attribute.
+	ServiceName string
+	// Endpoint is the OTLP/HTTP collector 'host:port' (default: from
+	// the OTEL_EXPORTER_OTLP_ENDPOINT environment variable).
+	Endpoint string
+	// Insecure disables the collector TLS connection.
+	Insecure bool
+	// SampleRatio is the fraction of the traces to sample (0..1).
+	SampleRatio float64
+}
+
+// Setup registers the global tracer provider exporting the spans to the
+// OTLP collector and the W3C trace context propagator; the returned
+// function flushes the pending spans and must be called on exit.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporterOpts []otlptracehttp.Option
+	if len(opts.Endpoint) > 0 {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create tracing resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of this provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start starts a span with the specified attributes.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
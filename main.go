@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/krateoplatformops/azuredevops-provider/apis"
 	"github.com/krateoplatformops/azuredevops-provider/internal/controller-utils/ratelimiter"
+	"github.com/krateoplatformops/azuredevops-provider/internal/tracing"
 	"github.com/krateoplatformops/provider-runtime/pkg/controller"
 	"github.com/krateoplatformops/provider-runtime/pkg/logging"
 
@@ -58,6 +60,22 @@ func main() {
 					Default("1s").
 					OverrideDefaultFromEnvar(fmt.Sprintf("%s_MIN_ERROR_RETRY_INTERVAL", envVarPrefix)).
 					Duration()
+		tracingEnabled = app.Flag("tracing", "Export the OpenTelemetry traces of the reconcile loops and of the Azure DevOps API calls.").
+				Default("false").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_TRACING", envVarPrefix)).
+				Bool()
+		tracingEndpoint = app.Flag("tracing-endpoint", "The OTLP/HTTP collector host:port (default: the OTEL_EXPORTER_OTLP_ENDPOINT environment variable).").
+				Default("").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_TRACING_ENDPOINT", envVarPrefix)).
+				String()
+		tracingInsecure = app.Flag("tracing-insecure", "Connect to the OTLP collector without TLS.").
+				Default("false").
+				OverrideDefaultFromEnvar(fmt.Sprintf("%s_TRACING_INSECURE", envVarPrefix)).
+				Bool()
+		tracingSampleRatio = app.Flag("tracing-sample-ratio", "The fraction of the reconcile traces to sample (0..1).").
+					Default("1").
+					OverrideDefaultFromEnvar(fmt.Sprintf("%s_TRACING_SAMPLE_RATIO", envVarPrefix)).
+					Float64()
 	)
 
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -90,6 +108,21 @@ func main() {
 
 	log.Debug("Starting", "sync-period", syncPeriod.String())
 
+	if *tracingEnabled {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+			ServiceName: fmt.Sprintf("%s-provider", strcase.KebabCase(providerName)),
+			Endpoint:    *tracingEndpoint,
+			Insecure:    *tracingInsecure,
+			SampleRatio: *tracingSampleRatio,
+		})
+		kingpin.FatalIfError(err, "Cannot setup tracing")
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Info("Cannot flush traces", "error", err)
+			}
+		}()
+	}
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 